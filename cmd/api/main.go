@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -15,10 +16,16 @@ import (
 	_ "github.com/mfuadfakhruzzaki/jubel/docs/swagger" // Import swagger docs
 	"github.com/mfuadfakhruzzaki/jubel/internal/config"
 	"github.com/mfuadfakhruzzaki/jubel/internal/database"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
 	"github.com/mfuadfakhruzzaki/jubel/internal/handler"
+	"github.com/mfuadfakhruzzaki/jubel/internal/mailer"
 	"github.com/mfuadfakhruzzaki/jubel/internal/middleware"
+	"github.com/mfuadfakhruzzaki/jubel/internal/oidc"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
 	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
 	"github.com/mfuadfakhruzzaki/jubel/internal/service"
+	"github.com/mfuadfakhruzzaki/jubel/internal/storage"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
 	"github.com/rs/zerolog"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -93,8 +100,13 @@ func main() {
 		log.Fatalf("Failed to run auto-migration: %v", err)
 	}
 
+	// Context untuk worker latar belakang, dibatalkan saat server berhenti
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+
 	// Inisialisasi router
-	router := setupRouter(cfg, db, rootLogger)
+	router := setupRouter(workerCtx, &workers, cfg, db, rootLogger)
 
 	// Jalankan server
 	server := &http.Server{
@@ -112,6 +124,10 @@ func main() {
 
 	// Graceful shutdown
 	gracefulShutdown(server, rootLogger)
+
+	// Hentikan worker dan tunggu worker yang harus menyelesaikan pekerjaannya
+	stopWorkers()
+	workers.Wait()
 }
 
 // initDatabase menginisialisasi koneksi database
@@ -167,8 +183,10 @@ func initDatabase(cfg *config.Config, logger zerolog.Logger) (*gorm.DB, error) {
 	return db, nil
 }
 
-// setupRouter menginisialisasi router dan endpoints
-func setupRouter(cfg *config.Config, db *gorm.DB, logger zerolog.Logger) *gin.Engine {
+// setupRouter menginisialisasi router dan endpoints.
+// Worker latar belakang berjalan sampai workerCtx dibatalkan; worker yang perlu ditunggu
+// saat server berhenti didaftarkan ke workers.
+func setupRouter(workerCtx context.Context, workers *sync.WaitGroup, cfg *config.Config, db *gorm.DB, logger zerolog.Logger) *gin.Engine {
 	routerLogger := logger.With().Str("component", "router").Logger()
 	
 	// Mode
@@ -202,34 +220,113 @@ func setupRouter(cfg *config.Config, db *gorm.DB, logger zerolog.Logger) *gin.En
 	// Inisialisasi repositories
 	userRepo := repository.NewUserRepository(db)
 	itemRepo := repository.NewItemRepository(db)
+	itemImageRepo := repository.NewItemImageRepository(db)
 	transactionRepo := repository.NewTransactionRepository(db)
 	chatRepo := repository.NewChatRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	revokedTokenRepo := repository.NewCachedRevokedTokenRepository(repository.NewRevokedTokenRepository(db), 10*time.Second)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	oidcStateRepo := repository.NewOIDCStateRepository(db)
+	oidcIdentityRepo := repository.NewOIDCIdentityRepository(db)
+	accessTokenRepo := repository.NewAccessTokenRepository(db)
+	adminLogRepo := repository.NewAdminLogRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	dataExportRepo := repository.NewDataExportRepository(db)
+	blockRepo := repository.NewBlockRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	followRepo := repository.NewFollowRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	var loginAttemptRepo repository.LoginAttemptRepository
+	switch cfg.Auth.LoginAttemptStore {
+	case "memory":
+		loginAttemptRepo = repository.NewMemoryLoginAttemptRepository(cfg.Auth.LoginAttemptWindow + cfg.Auth.LoginLockoutDuration)
+	default:
+		loginAttemptRepo = repository.NewLoginAttemptRepository(db)
+	}
 
 	routerLogger.Debug().Msg("Repositories initialized")
 
+	// Inisialisasi mailer
+	mail, err := mailer.New(cfg.Mail)
+	if err != nil {
+		routerLogger.Fatal().Err(err).Msg("Failed to initialize mailer")
+	}
+
+	// Inisialisasi storage file upload
+	store, err := storage.New(cfg)
+	if err != nil {
+		routerLogger.Fatal().Err(err).Msg("Failed to initialize storage")
+	}
+	routerLogger.Info().Str("driver", cfg.Storage.Driver).Msg("Storage initialized")
+
+	// Inisialisasi key ring JWT
+	keyRing, err := loadKeyRing(cfg)
+	if err != nil {
+		routerLogger.Fatal().Err(err).Msg("Failed to load JWT keys")
+	}
+	routerLogger.Info().Str("kid", keyRing.ActiveKeyID()).Msg("JWT key ring loaded")
+
 	// Inisialisasi services
-	authService := service.NewAuthService(userRepo, cfg)
-	userService := service.NewUserService(userRepo)
-	itemService := service.NewItemService(itemRepo, cfg)
-	transactionService := service.NewTransactionService(transactionRepo, itemRepo)
-	chatService := service.NewChatService(chatRepo, userRepo, itemRepo)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, revokedTokenRepo, passwordResetRepo, recoveryCodeRepo, loginAttemptRepo, sessionRepo, mail, keyRing, cfg)
+	userService := service.NewUserService(userRepo, refreshTokenRepo, sessionRepo, mail, store, cfg)
+	sessionService := service.NewSessionService(sessionRepo, refreshTokenRepo, cfg)
+	accessTokenService := service.NewAccessTokenService(accessTokenRepo, userRepo)
+	adminService := service.NewAdminService(userRepo, adminLogRepo, sessionRepo, refreshTokenRepo)
+	reviewService := service.NewReviewService(reviewRepo, transactionRepo, adminLogRepo, cfg)
+	dataExportService := service.NewDataExportService(dataExportRepo, userRepo, itemRepo, transactionRepo, chatRepo, cfg)
+	oidcService := service.NewOIDCService(oidc.NewProviders(cfg.OIDC, nil), oidcStateRepo, oidcIdentityRepo, userRepo, authService, cfg)
+	notificationService := service.NewNotificationService(notificationRepo)
+	itemService := service.NewItemService(itemRepo, itemImageRepo, notificationService, store, cfg)
+	searchService := service.NewSearchService(searchRepo, itemRepo, cfg)
+	transactionService := service.NewTransactionService(transactionRepo, itemRepo, blockRepo)
+	chatService := service.NewChatService(chatRepo, userRepo, itemRepo, blockRepo)
+	blockService := service.NewBlockService(blockRepo, userRepo, followRepo)
+	followService := service.NewFollowService(followRepo, userRepo, blockRepo, itemRepo)
+	reportService := service.NewReportService(reportRepo, itemRepo, chatRepo, userRepo, adminLogRepo, cfg)
 	
 	routerLogger.Debug().Msg("Services initialized")
 
+	// Jalankan worker pembuat arsip ekspor data dan penganoniman akun yang dihapus
+	go dataExportService.Run(workerCtx)
+	go userService.RunDeletionWorker(workerCtx)
+
+	// Worker pencatat pencarian ditunggu saat server berhenti agar antrean log pencarian tidak hilang
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		searchService.Run(workerCtx)
+	}()
+
 	// Inisialisasi middleware
-	authMiddleware := middleware.NewAuthMiddleware(authService)
+	authMiddleware := middleware.NewAuthMiddleware(authService, accessTokenService)
 
 	// Inisialisasi handlers
 	authHandler := handler.NewAuthHandler(authService)
 	userHandler := handler.NewUserHandler(userService)
-	itemHandler := handler.NewItemHandler(itemService)
+	itemHandler := handler.NewItemHandler(itemService, searchService)
 	transactionHandler := handler.NewTransactionHandler(transactionService)
 	chatHandler := handler.NewChatHandler(chatService)
+	wellKnownHandler := handler.NewWellKnownHandler(keyRing)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	oidcHandler := handler.NewOIDCHandler(oidcService)
+	accessTokenHandler := handler.NewAccessTokenHandler(accessTokenService)
+	adminHandler := handler.NewAdminHandler(adminService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	dataExportHandler := handler.NewDataExportHandler(dataExportService)
+	blockHandler := handler.NewBlockHandler(blockService)
+	reportHandler := handler.NewReportHandler(reportService)
+	followHandler := handler.NewFollowHandler(followService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
 	
 	routerLogger.Debug().Msg("Handlers initialized")
 
-	// Setup static file serving
-	router.Static("/uploads", cfg.Upload.Dir)
+	// Setup static file serving untuk driver storage local
+	if cfg.Storage.Driver == storage.DriverLocal {
+		router.Static("/uploads", cfg.Upload.Dir)
+	}
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -239,15 +336,28 @@ func setupRouter(cfg *config.Config, db *gorm.DB, logger zerolog.Logger) *gin.En
 		})
 	})
 
+	// Kunci publik JWT untuk layanan lain
+	wellKnownHandler.RegisterRoutes(&router.RouterGroup)
+
 	// API version
 	v1 := router.Group("/api/v1")
 	{
 		// Register routes
-		authHandler.RegisterRoutes(v1)
-		userHandler.RegisterRoutes(v1, authMiddleware.VerifyToken(), authMiddleware.RequireAdmin())
-		itemHandler.RegisterRoutes(v1, authMiddleware.VerifyToken(), authMiddleware.RequireAdmin())
-		transactionHandler.RegisterRoutes(v1, authMiddleware.VerifyToken())
-		chatHandler.RegisterRoutes(v1, authMiddleware.VerifyToken())
+		authHandler.RegisterRoutes(v1, authMiddleware.VerifyToken())
+		oidcHandler.RegisterRoutes(v1)
+		userHandler.RegisterRoutes(v1, authMiddleware.VerifyToken(), authMiddleware.RequirePermission(policy.UserListAll), authMiddleware.RequireScope, authMiddleware.OptionalScope(domain.ScopeProfileRead))
+		sessionHandler.RegisterRoutes(v1, authMiddleware.VerifyToken())
+		accessTokenHandler.RegisterRoutes(v1, authMiddleware.VerifyToken())
+		adminHandler.RegisterRoutes(v1, authMiddleware.VerifyToken(), authMiddleware.RequirePermission)
+		itemHandler.RegisterRoutes(v1, authMiddleware.RequireScope, authMiddleware.OptionalScope(domain.ScopeItemsRead), authMiddleware.RequirePermission(policy.ItemDelete), authMiddleware.RequireVerifiedEmail())
+		transactionHandler.RegisterRoutes(v1, authMiddleware.RequireScope, authMiddleware.RequireVerifiedEmail())
+		chatHandler.RegisterRoutes(v1, authMiddleware.RequireScope, authMiddleware.RequireVerifiedEmail())
+		reviewHandler.RegisterRoutes(v1, authMiddleware.VerifyToken(), authMiddleware.RequirePermission)
+		dataExportHandler.RegisterRoutes(v1, authMiddleware.VerifyToken())
+		blockHandler.RegisterRoutes(v1, authMiddleware.VerifyToken())
+		reportHandler.RegisterRoutes(v1, authMiddleware.VerifyToken(), authMiddleware.RequirePermission)
+		followHandler.RegisterRoutes(v1, authMiddleware.VerifyToken(), authMiddleware.RequireScope)
+		notificationHandler.RegisterRoutes(v1, authMiddleware.VerifyToken())
 	}
 	
 	routerLogger.Info().Msg("Routes registered successfully")
@@ -255,6 +365,14 @@ func setupRouter(cfg *config.Config, db *gorm.DB, logger zerolog.Logger) *gin.En
 	return router
 }
 
+// loadKeyRing memuat kunci JWT dari JWT_KEYS_DIR, atau memakai HS256 dengan JWT_SECRET jika kosong
+func loadKeyRing(cfg *config.Config) (*utils.KeyRing, error) {
+	if cfg.JWT.KeysDir == "" {
+		return utils.NewHMACKeyRing(cfg.JWT.Secret), nil
+	}
+	return utils.LoadKeyRing(cfg.JWT.KeysDir, cfg.JWT.ActiveKeyID, cfg.JWT.Secret, cfg.JWT.AcceptHS256)
+}
+
 // gracefulShutdown menangani shutdown server secara graceful
 func gracefulShutdown(server *http.Server, logger zerolog.Logger) {
 	// Channel for OS signals
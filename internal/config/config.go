@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -15,14 +16,24 @@ type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	JWT      JWTConfig
+	Auth     AuthConfig
+	Mail     MailConfig
+	OIDC     OIDCConfig
+	Review   ReviewConfig
+	Export   ExportConfig
+	Report   ReportConfig
+	Search   SearchConfig
 	Upload   UploadConfig
+	Storage  StorageConfig
 	Appwrite AppwriteConfig
+	S3       S3Config
 }
 
 // ServerConfig menyimpan konfigurasi server
 type ServerConfig struct {
-	Port string
-	Env  string
+	Port        string
+	Env         string
+	FrontendURL string
 }
 
 // DatabaseConfig menyimpan konfigurasi database
@@ -37,8 +48,105 @@ type DatabaseConfig struct {
 
 // JWTConfig menyimpan konfigurasi JWT
 type JWTConfig struct {
-	Secret     string
-	ExpiryTime time.Duration
+	// Secret dipakai untuk HS256 jika KeysDir kosong dan untuk menandatangani tautan (misalnya verifikasi email)
+	Secret            string
+	ExpiryTime        time.Duration
+	RefreshExpiryTime time.Duration
+	// KeysDir adalah direktori berisi kunci PEM RS256/EdDSA. Jika kosong, token memakai HS256.
+	KeysDir string
+	// ActiveKeyID adalah kid kunci yang dipakai untuk menandatangani token baru
+	ActiveKeyID string
+	// AcceptHS256 mengizinkan token HS256 lama selama masa migrasi ke kunci asimetris
+	AcceptHS256 bool
+}
+
+// AuthConfig menyimpan konfigurasi alur autentikasi
+type AuthConfig struct {
+	PasswordResetExpiry     time.Duration
+	EmailVerificationExpiry time.Duration
+	MFAPendingExpiry        time.Duration
+	// LoginAttemptStore menentukan tempat menyimpan percobaan login gagal ("postgres" atau "memory")
+	LoginAttemptStore     string
+	LoginMaxAttempts      int
+	LoginMaxAttemptsPerIP int
+	LoginAttemptWindow    time.Duration
+	LoginLockoutDuration  time.Duration
+	LoginBackoffBase      time.Duration
+	// AccountDeletionGrace adalah masa tenggang sebelum akun yang dihapus dianonimkan
+	AccountDeletionGrace time.Duration
+	// AccountDeletionCheckInterval adalah jeda worker memeriksa akun yang masa tenggangnya habis
+	AccountDeletionCheckInterval time.Duration
+}
+
+// MailConfig menyimpan konfigurasi pengiriman email
+type MailConfig struct {
+	Driver    string // "smtp" atau "outbox"
+	Host      string
+	Port      string
+	Username  string
+	Password  string
+	From      string
+	OutboxDir string
+}
+
+// OIDCConfig menyimpan konfigurasi login dengan penyedia identitas OpenID Connect
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+	// StateExpiry adalah batas waktu pengguna menyelesaikan login di penyedia identitas
+	StateExpiry time.Duration
+}
+
+// OIDCProviderConfig menyimpan konfigurasi satu penyedia identitas OpenID Connect
+type OIDCProviderConfig struct {
+	// Name dipakai pada URL /auth/oidc/{name}
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// ReviewConfig menyimpan konfigurasi ulasan transaksi
+type ReviewConfig struct {
+	// SubmitWindow adalah batas waktu memberi ulasan setelah transaksi selesai
+	SubmitWindow time.Duration
+	// EditWindow adalah batas waktu mengubah ulasan atau balasan setelah dibuat
+	EditWindow time.Duration
+}
+
+// ExportConfig menyimpan konfigurasi ekspor data pribadi pengguna
+type ExportConfig struct {
+	// Dir adalah direktori penyimpanan arsip ZIP hasil ekspor
+	Dir string
+	// Retention adalah lama arsip disimpan sebelum dihapus otomatis
+	Retention time.Duration
+	// LinkExpiry adalah masa berlaku tautan unduhan yang diberikan ke pengguna
+	LinkExpiry time.Duration
+	// PollInterval adalah jeda worker memeriksa permintaan ekspor baru
+	PollInterval time.Duration
+}
+
+// ReportConfig menyimpan konfigurasi laporan pengguna
+type ReportConfig struct {
+	// AutoHideThreshold adalah jumlah pelapor berbeda yang membuat target disembunyikan otomatis
+	// sampai ditinjau moderator. 0 menonaktifkan penyembunyian otomatis.
+	AutoHideThreshold int
+}
+
+// SearchConfig menyimpan konfigurasi pencarian dan saran pencarian barang
+type SearchConfig struct {
+	// BlockedTerms adalah kata atau frasa terlarang yang tidak pernah muncul di saran pencarian
+	BlockedTerms []string
+	// SuggestCacheTTL adalah lama saran pencarian untuk teks yang sama disimpan di memori. 0 menonaktifkan cache.
+	SuggestCacheTTL time.Duration
+	// PopularMinCount adalah jumlah pencarian minimal sebelum kueri disarankan sebagai kueri populer
+	PopularMinCount int64
+	// PopularDedupeWindow adalah jendela waktu di mana pencarian kueri yang sama oleh pencari
+	// yang sama hanya dihitung sekali di kueri populer
+	PopularDedupeWindow time.Duration
+	// LogRetention adalah lama log pencarian disimpan sebelum dihapus otomatis. 0 berarti tidak dihapus.
+	LogRetention time.Duration
 }
 
 // UploadConfig menyimpan konfigurasi upload file
@@ -48,6 +156,27 @@ type UploadConfig struct {
 	AllowedTypes []string
 }
 
+// StorageConfig menyimpan konfigurasi penyimpanan file yang diupload
+type StorageConfig struct {
+	// Driver adalah backend penyimpanan: local, appwrite atau s3
+	Driver string
+	// PublicURL adalah prefix URL file untuk driver local, tempat UPLOAD_DIR disajikan
+	PublicURL string
+}
+
+// S3Config menyimpan konfigurasi bucket yang kompatibel dengan S3
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle memakai URL endpoint/bucket/key (MinIO), bukan bucket.endpoint/key
+	PathStyle bool
+	// PublicURL adalah prefix URL publik file, misalnya domain CDN. Kosong berarti URL objek S3.
+	PublicURL string
+}
+
 // AppwriteConfig menyimpan konfigurasi Appwrite
 type AppwriteConfig struct {
 	Endpoint   string
@@ -69,6 +198,7 @@ func LoadConfig() (*Config, error) {
 	// Konfigurasi server
 	port := getEnv("APP_PORT", "8080")
 	env := getEnv("APP_ENV", "development")
+	frontendURL := getEnv("FRONTEND_URL", "http://localhost:3000")
 
 	// Konfigurasi database
 	dbHost := getEnv("DB_HOST", "postgres")
@@ -79,12 +209,154 @@ func LoadConfig() (*Config, error) {
 	dbSSLMode := getEnv("DB_SSLMODE", "disable")
 
 	// Konfigurasi JWT
-	jwtSecret := getEnv("JWT_SECRET", "kelompok5tasbd")
-	jwtExpiryStr := getEnv("JWT_EXPIRY", "24h")
+	jwtSecret := getEnv("JWT_SECRET", "")
+	if jwtSecret == "" {
+		return nil, fmt.Errorf("JWT_SECRET wajib diisi")
+	}
+	jwtExpiryStr := getEnv("JWT_EXPIRY", "15m")
 	jwtExpiry, err := time.ParseDuration(jwtExpiryStr)
 	if err != nil {
 		return nil, fmt.Errorf("gagal parse JWT_EXPIRY: %v", err)
 	}
+	jwtRefreshExpiryStr := getEnv("JWT_REFRESH_EXPIRY", "720h") // Default 30 hari
+	jwtRefreshExpiry, err := time.ParseDuration(jwtRefreshExpiryStr)
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse JWT_REFRESH_EXPIRY: %v", err)
+	}
+	jwtKeysDir := getEnv("JWT_KEYS_DIR", "")
+	jwtActiveKeyID := getEnv("JWT_ACTIVE_KID", "")
+	jwtAcceptHS256, err := strconv.ParseBool(getEnv("JWT_ACCEPT_HS256", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse JWT_ACCEPT_HS256: %v", err)
+	}
+
+	// Konfigurasi alur autentikasi
+	passwordResetExpiryStr := getEnv("PASSWORD_RESET_EXPIRY", "1h")
+	passwordResetExpiry, err := time.ParseDuration(passwordResetExpiryStr)
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse PASSWORD_RESET_EXPIRY: %v", err)
+	}
+	emailVerificationExpiryStr := getEnv("EMAIL_VERIFICATION_EXPIRY", "48h")
+	emailVerificationExpiry, err := time.ParseDuration(emailVerificationExpiryStr)
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse EMAIL_VERIFICATION_EXPIRY: %v", err)
+	}
+	mfaPendingExpiryStr := getEnv("MFA_PENDING_EXPIRY", "5m")
+	mfaPendingExpiry, err := time.ParseDuration(mfaPendingExpiryStr)
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse MFA_PENDING_EXPIRY: %v", err)
+	}
+
+	// Konfigurasi perlindungan brute-force login
+	loginAttemptStore := getEnv("LOGIN_ATTEMPT_STORE", "postgres")
+	loginMaxAttempts, err := strconv.Atoi(getEnv("LOGIN_MAX_ATTEMPTS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse LOGIN_MAX_ATTEMPTS: %v", err)
+	}
+	loginMaxAttemptsPerIP, err := strconv.Atoi(getEnv("LOGIN_MAX_ATTEMPTS_PER_IP", "50"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse LOGIN_MAX_ATTEMPTS_PER_IP: %v", err)
+	}
+	loginAttemptWindow, err := time.ParseDuration(getEnv("LOGIN_ATTEMPT_WINDOW", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse LOGIN_ATTEMPT_WINDOW: %v", err)
+	}
+	loginLockoutDuration, err := time.ParseDuration(getEnv("LOGIN_LOCKOUT_DURATION", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse LOGIN_LOCKOUT_DURATION: %v", err)
+	}
+	loginBackoffBase, err := time.ParseDuration(getEnv("LOGIN_BACKOFF_BASE", "1s"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse LOGIN_BACKOFF_BASE: %v", err)
+	}
+
+	// Konfigurasi penghapusan akun
+	accountDeletionGrace, err := time.ParseDuration(getEnv("ACCOUNT_DELETION_GRACE", "336h")) // Default 14 hari
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse ACCOUNT_DELETION_GRACE: %v", err)
+	}
+	accountDeletionCheckInterval, err := time.ParseDuration(getEnv("ACCOUNT_DELETION_CHECK_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse ACCOUNT_DELETION_CHECK_INTERVAL: %v", err)
+	}
+
+	// Konfigurasi email
+	mailDriver := getEnv("MAIL_DRIVER", "outbox")
+	smtpHost := getEnv("SMTP_HOST", "localhost")
+	smtpPort := getEnv("SMTP_PORT", "587")
+	smtpUsername := getEnv("SMTP_USERNAME", "")
+	smtpPassword := getEnv("SMTP_PASSWORD", "")
+	mailFrom := getEnv("MAIL_FROM", "Jubel <no-reply@jubel.app>")
+	mailOutboxDir := getEnv("MAIL_OUTBOX_DIR", "./outbox")
+
+	// Konfigurasi OpenID Connect
+	oidcStateExpiry, err := time.ParseDuration(getEnv("OIDC_STATE_EXPIRY", "10m"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse OIDC_STATE_EXPIRY: %v", err)
+	}
+	oidcProviders, err := loadOIDCProviders(getEnv("OIDC_PROVIDERS", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	// Konfigurasi ulasan
+	reviewSubmitWindow, err := time.ParseDuration(getEnv("REVIEW_SUBMIT_WINDOW", "720h"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse REVIEW_SUBMIT_WINDOW: %v", err)
+	}
+	reviewEditWindow, err := time.ParseDuration(getEnv("REVIEW_EDIT_WINDOW", "72h"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse REVIEW_EDIT_WINDOW: %v", err)
+	}
+
+	// Konfigurasi ekspor data
+	exportDir := getEnv("EXPORT_DIR", "./exports")
+	exportRetention, err := time.ParseDuration(getEnv("EXPORT_RETENTION", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse EXPORT_RETENTION: %v", err)
+	}
+	exportLinkExpiry, err := time.ParseDuration(getEnv("EXPORT_LINK_EXPIRY", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse EXPORT_LINK_EXPIRY: %v", err)
+	}
+	exportPollInterval, err := time.ParseDuration(getEnv("EXPORT_POLL_INTERVAL", "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse EXPORT_POLL_INTERVAL: %v", err)
+	}
+
+	// Konfigurasi laporan pengguna
+	reportAutoHideThreshold, err := strconv.Atoi(getEnv("REPORT_AUTO_HIDE_THRESHOLD", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse REPORT_AUTO_HIDE_THRESHOLD: %v", err)
+	}
+
+	// Konfigurasi pencarian
+	searchSuggestCacheTTL, err := time.ParseDuration(getEnv("SEARCH_SUGGEST_CACHE_TTL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse SEARCH_SUGGEST_CACHE_TTL: %v", err)
+	}
+	searchPopularMinCount, err := strconv.ParseInt(getEnv("SEARCH_POPULAR_MIN_COUNT", "3"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse SEARCH_POPULAR_MIN_COUNT: %v", err)
+	}
+	searchPopularDedupeWindow, err := time.ParseDuration(getEnv("SEARCH_POPULAR_DEDUPE_WINDOW", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse SEARCH_POPULAR_DEDUPE_WINDOW: %v", err)
+	}
+	searchLogRetention, err := time.ParseDuration(getEnv("SEARCH_LOG_RETENTION", "2160h"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse SEARCH_LOG_RETENTION: %v", err)
+	}
+	// Deduplikasi membaca log pencarian, sehingga log harus disimpan minimal selama jendela deduplikasi
+	if searchLogRetention > 0 && searchLogRetention < searchPopularDedupeWindow {
+		return nil, fmt.Errorf("SEARCH_LOG_RETENTION tidak boleh lebih pendek dari SEARCH_POPULAR_DEDUPE_WINDOW")
+	}
+	var searchBlockedTerms []string
+	for _, term := range strings.Split(getEnv("SEARCH_BLOCKED_TERMS", ""), ",") {
+		if term = strings.TrimSpace(term); term != "" {
+			searchBlockedTerms = append(searchBlockedTerms, term)
+		}
+	}
 
 	// Konfigurasi upload
 	uploadDir := getEnv("UPLOAD_DIR", "./uploads")
@@ -94,11 +366,27 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("gagal parse MAX_UPLOAD_SIZE: %v", err)
 	}
 
-	// Konfigurasi Appwrite
-	appwriteEndpoint := getEnv("APPWRITE_ENDPOINT", "http://tugas-akhir-sbd-appwrite-baa3ca-34-50-95-184.traefik.me/v1")
-	appwriteProjectID := getEnv("APPWRITE_PROJECT_ID", "67ec168f001774966f85")
-	appwriteAPIKey := getEnv("APPWRITE_API_KEY", "standard_c198465575b7925e70d344fe9b76414e306f0d2f3d6137371e23a36bbf48c0d89f884981271b0f7de15b9d2162badf9cdaab963560bc99cc6278e99b02f17cb1d08d5cd81140c8c09397fcf503a3a2c1f4dd2056f46eb57d16bdd315f2b74e38600bd82e0ab061eb1786697cf7c1aa94e7a1bed5d0747f7e4fec6ac0bfb602f3")
-	appwriteBucketID := getEnv("APPWRITE_BUCKET_ID", "67ec16ad001dd1f0a484")
+	// Konfigurasi storage
+	storageDriver := getEnv("STORAGE_DRIVER", "local")
+	storagePublicURL := getEnv("STORAGE_PUBLIC_URL", "/uploads")
+
+	// Konfigurasi Appwrite, wajib diisi jika STORAGE_DRIVER=appwrite
+	appwriteEndpoint := getEnv("APPWRITE_ENDPOINT", "")
+	appwriteProjectID := getEnv("APPWRITE_PROJECT_ID", "")
+	appwriteAPIKey := getEnv("APPWRITE_API_KEY", "")
+	appwriteBucketID := getEnv("APPWRITE_BUCKET_ID", "")
+
+	// Konfigurasi S3, wajib diisi jika STORAGE_DRIVER=s3
+	s3Endpoint := getEnv("S3_ENDPOINT", "")
+	s3Region := getEnv("S3_REGION", "us-east-1")
+	s3Bucket := getEnv("S3_BUCKET", "")
+	s3AccessKeyID := getEnv("S3_ACCESS_KEY_ID", "")
+	s3SecretAccessKey := getEnv("S3_SECRET_ACCESS_KEY", "")
+	s3PublicURL := getEnv("S3_PUBLIC_URL", "")
+	s3PathStyle, err := strconv.ParseBool(getEnv("S3_PATH_STYLE", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("gagal parse S3_PATH_STYLE: %v", err)
+	}
 
 	// Pastikan direktori upload ada
 	if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
@@ -110,8 +398,9 @@ func LoadConfig() (*Config, error) {
 
 	return &Config{
 		Server: ServerConfig{
-			Port: port,
-			Env:  env,
+			Port:        port,
+			Env:         env,
+			FrontendURL: frontendURL,
 		},
 		Database: DatabaseConfig{
 			Host:     dbHost,
@@ -122,8 +411,58 @@ func LoadConfig() (*Config, error) {
 			SSLMode:  dbSSLMode,
 		},
 		JWT: JWTConfig{
-			Secret:     jwtSecret,
-			ExpiryTime: jwtExpiry,
+			Secret:            jwtSecret,
+			ExpiryTime:        jwtExpiry,
+			RefreshExpiryTime: jwtRefreshExpiry,
+			KeysDir:           jwtKeysDir,
+			ActiveKeyID:       jwtActiveKeyID,
+			AcceptHS256:       jwtAcceptHS256,
+		},
+		Auth: AuthConfig{
+			PasswordResetExpiry:          passwordResetExpiry,
+			EmailVerificationExpiry:      emailVerificationExpiry,
+			MFAPendingExpiry:             mfaPendingExpiry,
+			LoginAttemptStore:            loginAttemptStore,
+			LoginMaxAttempts:             loginMaxAttempts,
+			LoginMaxAttemptsPerIP:        loginMaxAttemptsPerIP,
+			LoginAttemptWindow:           loginAttemptWindow,
+			LoginLockoutDuration:         loginLockoutDuration,
+			LoginBackoffBase:             loginBackoffBase,
+			AccountDeletionGrace:         accountDeletionGrace,
+			AccountDeletionCheckInterval: accountDeletionCheckInterval,
+		},
+		Mail: MailConfig{
+			Driver:    mailDriver,
+			Host:      smtpHost,
+			Port:      smtpPort,
+			Username:  smtpUsername,
+			Password:  smtpPassword,
+			From:      mailFrom,
+			OutboxDir: mailOutboxDir,
+		},
+		OIDC: OIDCConfig{
+			Providers:   oidcProviders,
+			StateExpiry: oidcStateExpiry,
+		},
+		Review: ReviewConfig{
+			SubmitWindow: reviewSubmitWindow,
+			EditWindow:   reviewEditWindow,
+		},
+		Export: ExportConfig{
+			Dir:          exportDir,
+			Retention:    exportRetention,
+			LinkExpiry:   exportLinkExpiry,
+			PollInterval: exportPollInterval,
+		},
+		Report: ReportConfig{
+			AutoHideThreshold: reportAutoHideThreshold,
+		},
+		Search: SearchConfig{
+			BlockedTerms:        searchBlockedTerms,
+			SuggestCacheTTL:     searchSuggestCacheTTL,
+			PopularMinCount:     searchPopularMinCount,
+			PopularDedupeWindow: searchPopularDedupeWindow,
+			LogRetention:        searchLogRetention,
 		},
 		Upload: UploadConfig{
 			Dir:     uploadDir,
@@ -134,21 +473,72 @@ func LoadConfig() (*Config, error) {
 				"image/gif",
 			},
 		},
+		Storage: StorageConfig{
+			Driver:    storageDriver,
+			PublicURL: storagePublicURL,
+		},
 		Appwrite: AppwriteConfig{
 			Endpoint:   appwriteEndpoint,
 			ProjectID:  appwriteProjectID,
 			APIKey:     appwriteAPIKey,
 			BucketID:   appwriteBucketID,
 		},
+		S3: S3Config{
+			Endpoint:        s3Endpoint,
+			Region:          s3Region,
+			Bucket:          s3Bucket,
+			AccessKeyID:     s3AccessKeyID,
+			SecretAccessKey: s3SecretAccessKey,
+			PathStyle:       s3PathStyle,
+			PublicURL:       s3PublicURL,
+		},
 	}, nil
 }
 
+// loadOIDCProviders memuat konfigurasi penyedia OIDC dari daftar nama yang dipisahkan koma.
+// Setiap penyedia dikonfigurasi dengan variabel OIDC_<NAMA>_ISSUER, OIDC_<NAMA>_CLIENT_ID,
+// OIDC_<NAMA>_CLIENT_SECRET, OIDC_<NAMA>_REDIRECT_URL dan OIDC_<NAMA>_SCOPES.
+func loadOIDCProviders(names string) ([]OIDCProviderConfig, error) {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider := OIDCProviderConfig{
+			Name:         name,
+			Issuer:       strings.TrimSuffix(getEnv(prefix+"ISSUER", ""), "/"),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", ""),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			return nil, fmt.Errorf("%sISSUER, %sCLIENT_ID dan %sREDIRECT_URL wajib diisi", prefix, prefix, prefix)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
 // GetDSN mengembalikan connection string untuk PostgreSQL
 func (c *DatabaseConfig) GetDSN() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode)
 }
 
+// IsAllowedType memeriksa apakah content type termasuk dalam AllowedTypes
+func (c *UploadConfig) IsAllowedType(contentType string) bool {
+	for _, allowed := range c.AllowedTypes {
+		if strings.EqualFold(contentType, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
 // Helper function untuk mendapatkan environment variable dengan nilai default
 func getEnv(key, defaultValue string) string {
 	value := viper.GetString(key)
@@ -34,7 +34,12 @@ func AutoMigrate(db *gorm.DB) error {
 	} else {
 		log.Println("Database schema already exists. Skipping auto-migration.")
 	}
-	
+
+	// Jalankan perubahan skema yang ditambahkan setelah skema awal
+	if err := applySchemaUpdates(db); err != nil {
+		return err
+	}
+
 	return nil
 }
 
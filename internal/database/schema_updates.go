@@ -0,0 +1,482 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// schemaUpdate adalah satu perubahan skema yang dijalankan setelah skema awal dibuat
+type schemaUpdate struct {
+	// Version adalah nama unik perubahan, sama dengan nama file di folder migrations
+	Version string
+	// Statements adalah perintah SQL yang dijalankan secara berurutan
+	Statements []string
+	// NoTransaction menandai perubahan yang tidak boleh dijalankan di dalam transaksi
+	// (misalnya ALTER TYPE ... ADD VALUE)
+	NoTransaction bool
+}
+
+// schemaUpdates berisi daftar perubahan skema secara berurutan.
+// Tambahkan perubahan baru di akhir daftar dan jangan ubah perubahan yang sudah dirilis.
+var schemaUpdates = []schemaUpdate{
+	{
+		Version: "002_refresh_token",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS refresh_token (
+				id SERIAL PRIMARY KEY,
+				pengguna_id INT NOT NULL,
+				token_hash VARCHAR(64) UNIQUE NOT NULL,
+				family_id VARCHAR(36) NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				used_at TIMESTAMP,
+				revoked_at TIMESTAMP,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_refresh_token_pengguna ON refresh_token(pengguna_id);`,
+			`CREATE INDEX IF NOT EXISTS idx_refresh_token_family ON refresh_token(family_id);`,
+		},
+	},
+	{
+		Version: "003_token_dicabut",
+		Statements: []string{
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS token_valid_after TIMESTAMP;`,
+			`CREATE TABLE IF NOT EXISTS token_dicabut (
+				jti VARCHAR(36) PRIMARY KEY,
+				pengguna_id INT NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_token_dicabut_expires ON token_dicabut(expires_at);`,
+		},
+	},
+	{
+		Version: "004_reset_password",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS reset_password (
+				id SERIAL PRIMARY KEY,
+				pengguna_id INT NOT NULL,
+				token_hash VARCHAR(64) UNIQUE NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				used_at TIMESTAMP,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_reset_password_pengguna ON reset_password(pengguna_id);`,
+		},
+	},
+	{
+		Version: "005_verifikasi_email",
+		Statements: []string{
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP;`,
+			// Akun yang sudah ada sebelum verifikasi email diberlakukan dianggap terverifikasi
+			`UPDATE pengguna SET email_verified_at = created_at WHERE email_verified_at IS NULL;`,
+		},
+	},
+	{
+		Version: "006_mfa",
+		Statements: []string{
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64);`,
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMP;`,
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;`,
+			`ALTER TABLE refresh_token ADD COLUMN IF NOT EXISTS mfa BOOLEAN NOT NULL DEFAULT FALSE;`,
+			`CREATE TABLE IF NOT EXISTS kode_pemulihan (
+				id SERIAL PRIMARY KEY,
+				pengguna_id INT NOT NULL,
+				code_hash VARCHAR(64) NOT NULL,
+				used_at TIMESTAMP,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_kode_pemulihan_pengguna ON kode_pemulihan(pengguna_id);`,
+		},
+	},
+	{
+		Version: "007_percobaan_login",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS percobaan_login (
+				attempt_key VARCHAR(255) PRIMARY KEY,
+				failures INT NOT NULL DEFAULT 0,
+				last_failure_at TIMESTAMP NOT NULL,
+				locked_until TIMESTAMP
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_percobaan_login_last_failure ON percobaan_login(last_failure_at);`,
+		},
+	},
+	{
+		Version: "008_sesi",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS sesi (
+				id VARCHAR(36) PRIMARY KEY,
+				pengguna_id INT NOT NULL,
+				user_agent TEXT,
+				ip_address VARCHAR(45),
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				last_seen_at TIMESTAMP NOT NULL,
+				revoked_at TIMESTAMP,
+				FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_sesi_pengguna ON sesi(pengguna_id);`,
+		},
+	},
+	{
+		Version: "009_oidc",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS oidc_state (
+				state_hash VARCHAR(64) PRIMARY KEY,
+				provider VARCHAR(50) NOT NULL,
+				nonce VARCHAR(64) NOT NULL,
+				code_verifier VARCHAR(128) NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_oidc_state_expires ON oidc_state(expires_at);`,
+			`CREATE TABLE IF NOT EXISTS identitas_oidc (
+				id SERIAL PRIMARY KEY,
+				pengguna_id INT NOT NULL,
+				provider VARCHAR(50) NOT NULL,
+				subject VARCHAR(255) NOT NULL,
+				email VARCHAR(100),
+				last_login_at TIMESTAMP,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (provider, subject),
+				FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_identitas_oidc_pengguna ON identitas_oidc(pengguna_id);`,
+		},
+	},
+	{
+		Version: "010_token_akses",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS token_akses (
+				id SERIAL PRIMARY KEY,
+				pengguna_id INT NOT NULL,
+				name VARCHAR(100) NOT NULL,
+				token_hash VARCHAR(64) UNIQUE NOT NULL,
+				token_prefix VARCHAR(16) NOT NULL,
+				scopes TEXT NOT NULL,
+				expires_at TIMESTAMP,
+				last_used_at TIMESTAMP,
+				revoked_at TIMESTAMP,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_token_akses_pengguna ON token_akses(pengguna_id);`,
+		},
+	},
+	{
+		Version: "011_role_moderator",
+		Statements: []string{
+			`ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'moderator';`,
+		},
+		NoTransaction: true,
+	},
+	{
+		Version: "012_penangguhan",
+		Statements: []string{
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP;`,
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS suspended_until TIMESTAMP;`,
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS suspend_reason TEXT;`,
+			`CREATE INDEX IF NOT EXISTS idx_pengguna_suspended ON pengguna(suspended_at) WHERE suspended_at IS NOT NULL;`,
+			`CREATE TABLE IF NOT EXISTS log_admin (
+				id SERIAL PRIMARY KEY,
+				admin_id INT NOT NULL,
+				target_id INT NOT NULL,
+				action VARCHAR(50) NOT NULL,
+				detail TEXT,
+				reason TEXT,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (admin_id) REFERENCES pengguna(id),
+				FOREIGN KEY (target_id) REFERENCES pengguna(id)
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_log_admin_target ON log_admin(target_id);`,
+		},
+	},
+	{
+		Version: "013_ulasan",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS ulasan (
+				id SERIAL PRIMARY KEY,
+				transaksi_id INT NOT NULL,
+				penulis_id INT NOT NULL,
+				target_id INT NOT NULL,
+				peran VARCHAR(10) NOT NULL,
+				rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+				komentar TEXT,
+				balasan TEXT,
+				balasan_at TIMESTAMP,
+				hidden_at TIMESTAMP,
+				hidden_reason TEXT,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (transaksi_id, penulis_id),
+				FOREIGN KEY (transaksi_id) REFERENCES transaksi(id) ON DELETE CASCADE,
+				FOREIGN KEY (penulis_id) REFERENCES pengguna(id) ON DELETE CASCADE,
+				FOREIGN KEY (target_id) REFERENCES pengguna(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_ulasan_target ON ulasan(target_id);`,
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS rating_rata_rata NUMERIC(3,2);`,
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS jumlah_ulasan INT NOT NULL DEFAULT 0;`,
+		},
+	},
+	{
+		Version: "014_ekspor_data",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS ekspor_data (
+				id SERIAL PRIMARY KEY,
+				pengguna_id INT NOT NULL,
+				status VARCHAR(20) NOT NULL DEFAULT 'pending',
+				file_path VARCHAR(255),
+				error TEXT,
+				completed_at TIMESTAMP,
+				expires_at TIMESTAMP,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_ekspor_data_pengguna ON ekspor_data(pengguna_id, created_at);`,
+			`CREATE INDEX IF NOT EXISTS idx_ekspor_data_status ON ekspor_data(status) WHERE status IN ('pending', 'processing', 'ready');`,
+		},
+	},
+	{
+		Version: "015_anonimisasi_akun",
+		Statements: []string{
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMP;`,
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMP;`,
+			`ALTER TABLE pengguna ALTER COLUMN email DROP NOT NULL;`,
+			`ALTER TABLE pengguna ALTER COLUMN no_hp DROP NOT NULL;`,
+			`CREATE INDEX IF NOT EXISTS idx_pengguna_deletion ON pengguna(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL AND anonymized_at IS NULL;`,
+			// Pengguna yang dihapus dengan soft delete lama langsung dijadwalkan untuk dianonimkan
+			`UPDATE pengguna SET deletion_scheduled_at = deleted_at, deleted_at = NULL WHERE deleted_at IS NOT NULL;`,
+			// Menghapus pengguna atau barang tidak boleh ikut menghapus riwayat pihak lain
+			`ALTER TABLE barang DROP CONSTRAINT IF EXISTS barang_penjual_id_fkey;`,
+			`ALTER TABLE barang ADD CONSTRAINT barang_penjual_id_fkey FOREIGN KEY (penjual_id) REFERENCES pengguna(id);`,
+			`ALTER TABLE transaksi DROP CONSTRAINT IF EXISTS transaksi_pembeli_id_fkey;`,
+			`ALTER TABLE transaksi ADD CONSTRAINT transaksi_pembeli_id_fkey FOREIGN KEY (pembeli_id) REFERENCES pengguna(id);`,
+			`ALTER TABLE transaksi DROP CONSTRAINT IF EXISTS transaksi_barang_id_fkey;`,
+			`ALTER TABLE transaksi ADD CONSTRAINT transaksi_barang_id_fkey FOREIGN KEY (barang_id) REFERENCES barang(id);`,
+			`ALTER TABLE chat DROP CONSTRAINT IF EXISTS chat_pengirim_id_fkey;`,
+			`ALTER TABLE chat ADD CONSTRAINT chat_pengirim_id_fkey FOREIGN KEY (pengirim_id) REFERENCES pengguna(id);`,
+			`ALTER TABLE chat DROP CONSTRAINT IF EXISTS chat_penerima_id_fkey;`,
+			`ALTER TABLE chat ADD CONSTRAINT chat_penerima_id_fkey FOREIGN KEY (penerima_id) REFERENCES pengguna(id);`,
+			`ALTER TABLE chat DROP CONSTRAINT IF EXISTS chat_barang_id_fkey;`,
+			`ALTER TABLE chat ADD CONSTRAINT chat_barang_id_fkey FOREIGN KEY (barang_id) REFERENCES barang(id);`,
+			`ALTER TABLE ulasan DROP CONSTRAINT IF EXISTS ulasan_penulis_id_fkey;`,
+			`ALTER TABLE ulasan ADD CONSTRAINT ulasan_penulis_id_fkey FOREIGN KEY (penulis_id) REFERENCES pengguna(id);`,
+			`ALTER TABLE ulasan DROP CONSTRAINT IF EXISTS ulasan_target_id_fkey;`,
+			`ALTER TABLE ulasan ADD CONSTRAINT ulasan_target_id_fkey FOREIGN KEY (target_id) REFERENCES pengguna(id);`,
+		},
+	},
+	{
+		Version: "016_avatar",
+		Statements: []string{
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS avatar_file_id VARCHAR(64);`,
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS avatar_url TEXT;`,
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS avatar_thumb_url TEXT;`,
+		},
+	},
+	{
+		Version: "017_blokir",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS blokir (
+				id SERIAL PRIMARY KEY,
+				pemblokir_id INT NOT NULL,
+				diblokir_id INT NOT NULL,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (pemblokir_id, diblokir_id),
+				FOREIGN KEY (pemblokir_id) REFERENCES pengguna(id) ON DELETE CASCADE,
+				FOREIGN KEY (diblokir_id) REFERENCES pengguna(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_blokir_diblokir ON blokir(diblokir_id);`,
+		},
+	},
+	{
+		Version: "018_laporan",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS laporan (
+				id SERIAL PRIMARY KEY,
+				pelapor_id INT NOT NULL,
+				target_type VARCHAR(20) NOT NULL,
+				target_id INT NOT NULL,
+				pemilik_target_id INT NOT NULL,
+				alasan VARCHAR(30) NOT NULL,
+				deskripsi TEXT,
+				cuplikan TEXT,
+				status VARCHAR(20) NOT NULL DEFAULT 'open',
+				moderator_id INT,
+				catatan_penyelesaian TEXT,
+				resolved_at TIMESTAMP,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (pelapor_id) REFERENCES pengguna(id),
+				FOREIGN KEY (pemilik_target_id) REFERENCES pengguna(id),
+				FOREIGN KEY (moderator_id) REFERENCES pengguna(id)
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_laporan_antrean ON laporan(status, created_at);`,
+			`CREATE INDEX IF NOT EXISTS idx_laporan_target ON laporan(target_type, target_id) WHERE status IN ('open', 'reviewing');`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_laporan_pelapor_terbuka ON laporan(pelapor_id, target_type, target_id) WHERE status IN ('open', 'reviewing');`,
+			`ALTER TABLE barang ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP;`,
+			`ALTER TABLE chat ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP;`,
+			`ALTER TABLE pengguna ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP;`,
+			`ALTER TABLE log_admin ADD COLUMN IF NOT EXISTS laporan_id INT REFERENCES laporan(id);`,
+			`CREATE INDEX IF NOT EXISTS idx_log_admin_laporan ON log_admin(laporan_id) WHERE laporan_id IS NOT NULL;`,
+		},
+	},
+	{
+		Version: "019_mengikuti",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS mengikuti (
+				id SERIAL PRIMARY KEY,
+				pengikut_id INT NOT NULL,
+				diikuti_id INT NOT NULL,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (pengikut_id, diikuti_id),
+				FOREIGN KEY (pengikut_id) REFERENCES pengguna(id) ON DELETE CASCADE,
+				FOREIGN KEY (diikuti_id) REFERENCES pengguna(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_mengikuti_diikuti ON mengikuti(diikuti_id);`,
+			`CREATE INDEX IF NOT EXISTS idx_barang_penjual_terbaru ON barang(penjual_id, created_at DESC, id DESC);`,
+			`CREATE TABLE IF NOT EXISTS notifikasi (
+				id SERIAL PRIMARY KEY,
+				pengguna_id INT NOT NULL,
+				jenis VARCHAR(30) NOT NULL,
+				judul VARCHAR(200) NOT NULL,
+				pesan TEXT,
+				barang_id INT,
+				read_at TIMESTAMP,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE,
+				FOREIGN KEY (barang_id) REFERENCES barang(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_notifikasi_pengguna ON notifikasi(pengguna_id, created_at DESC);`,
+			`CREATE INDEX IF NOT EXISTS idx_notifikasi_belum_dibaca ON notifikasi(pengguna_id) WHERE read_at IS NULL;`,
+		},
+	},
+	{
+		Version: "020_galeri_barang",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS item_images (
+				id SERIAL PRIMARY KEY,
+				barang_id INT NOT NULL,
+				file_key VARCHAR(255) NOT NULL DEFAULT '',
+				url VARCHAR(255) NOT NULL,
+				keterangan VARCHAR(255) NOT NULL DEFAULT '',
+				urutan INT NOT NULL DEFAULT 0,
+				sampul BOOLEAN NOT NULL DEFAULT FALSE,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (barang_id) REFERENCES barang(id) ON DELETE CASCADE
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_item_images_barang ON item_images(barang_id, urutan);`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_item_images_sampul ON item_images(barang_id) WHERE sampul;`,
+			// Gambar lama menjadi sampul galeri; file_key kosong karena key file-nya tidak disimpan
+			`INSERT INTO item_images (barang_id, url, urutan, sampul)
+				SELECT id, gambar, 0, TRUE FROM barang
+				WHERE gambar IS NOT NULL AND gambar <> ''
+				AND NOT EXISTS (SELECT 1 FROM item_images WHERE item_images.barang_id = barang.id);`,
+		},
+	},
+	{
+		Version: "021_varian_gambar",
+		Statements: []string{
+			`ALTER TABLE item_images ADD COLUMN IF NOT EXISTS thumb_url VARCHAR(255) NOT NULL DEFAULT '';`,
+			`ALTER TABLE item_images ADD COLUMN IF NOT EXISTS medium_url VARCHAR(255) NOT NULL DEFAULT '';`,
+		},
+	},
+	{
+		Version: "022_pencarian_barang",
+		Statements: []string{
+			`CREATE EXTENSION IF NOT EXISTS pg_trgm;`,
+			// Kategori dipetakan dengan CASE karena cast enum ke text tidak immutable
+			// sehingga tidak dapat dipakai di generated column
+			`ALTER TABLE barang ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+				setweight(to_tsvector('simple', coalesce(nama_barang, '')), 'A') ||
+				setweight(to_tsvector('simple', coalesce(deskripsi, '')), 'B') ||
+				setweight(to_tsvector('simple', CASE kategori
+					WHEN 'Buku' THEN 'Buku'
+					WHEN 'Elektronik' THEN 'Elektronik'
+					WHEN 'Perabotan' THEN 'Perabotan'
+					WHEN 'Kos-kosan' THEN 'Kos-kosan'
+					ELSE 'Lainnya'
+				END), 'C')
+			) STORED;`,
+			`CREATE INDEX IF NOT EXISTS idx_barang_search_vector ON barang USING GIN (search_vector);`,
+			`CREATE INDEX IF NOT EXISTS idx_barang_nama_trgm ON barang USING GIN (nama_barang gin_trgm_ops);`,
+		},
+	},
+	{
+		Version: "023_saran_pencarian",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS log_pencarian (
+				id SERIAL PRIMARY KEY,
+				kueri VARCHAR(100) NOT NULL,
+				jumlah_hasil BIGINT NOT NULL DEFAULT 0,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_log_pencarian_created_at ON log_pencarian(created_at);`,
+			`CREATE TABLE IF NOT EXISTS kueri_populer (
+				kueri VARCHAR(100) PRIMARY KEY,
+				jumlah BIGINT NOT NULL DEFAULT 0,
+				terakhir_dicari TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);`,
+			// text_pattern_ops membuat pencocokan awalan kueri LIKE 'awalan%' memakai index
+			`CREATE INDEX IF NOT EXISTS idx_kueri_populer_prefix ON kueri_populer(kueri text_pattern_ops);`,
+		},
+	},
+	{
+		Version: "024_pencari_log_pencarian",
+		Statements: []string{
+			`ALTER TABLE log_pencarian ADD COLUMN IF NOT EXISTS pencari_hash VARCHAR(64);`,
+			`ALTER TABLE log_pencarian ADD COLUMN IF NOT EXISTS dihitung BOOLEAN NOT NULL DEFAULT FALSE;`,
+			// Deduplikasi hanya mencari pencarian yang sudah dihitung ke kueri populer
+			`CREATE INDEX IF NOT EXISTS idx_log_pencarian_pencari ON log_pencarian(pencari_hash, kueri, created_at) WHERE dihitung;`,
+		},
+	},
+}
+
+// applySchemaUpdates menjalankan perubahan skema yang belum pernah dijalankan
+func applySchemaUpdates(db *gorm.DB) error {
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_versi (
+			versi VARCHAR(100) PRIMARY KEY,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create schema_versi table: %w", err)
+	}
+
+	for _, update := range schemaUpdates {
+		var count int64
+		if err := db.Raw("SELECT COUNT(*) FROM schema_versi WHERE versi = ?", update.Version).Scan(&count).Error; err != nil {
+			return fmt.Errorf("failed to check schema version %s: %w", update.Version, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		log.Printf("Applying schema update %s...", update.Version)
+
+		apply := func(tx *gorm.DB) error {
+			for _, statement := range update.Statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Exec("INSERT INTO schema_versi (versi) VALUES (?)", update.Version).Error
+		}
+
+		var err error
+		if update.NoTransaction {
+			err = apply(db)
+		} else {
+			err = db.Transaction(apply)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to apply schema update %s: %w", update.Version, err)
+		}
+	}
+
+	return nil
+}
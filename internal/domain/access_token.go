@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Scope adalah hak akses yang bisa diberikan ke personal access token
+type Scope string
+
+const (
+	ScopeItemsRead         Scope = "items:read"
+	ScopeItemsWrite        Scope = "items:write"
+	ScopeChatsRead         Scope = "chats:read"
+	ScopeChatsWrite        Scope = "chats:write"
+	ScopeTransactionsRead  Scope = "transactions:read"
+	ScopeTransactionsWrite Scope = "transactions:write"
+	ScopeProfileRead       Scope = "profile:read"
+)
+
+// AccessTokenPrefix adalah awalan personal access token agar mudah dibedakan dari JWT
+// dan mudah dideteksi jika tidak sengaja dipublikasikan
+const AccessTokenPrefix = "jbl_pat_"
+
+// ValidScopes berisi semua scope yang dikenal
+var ValidScopes = []Scope{
+	ScopeItemsRead,
+	ScopeItemsWrite,
+	ScopeChatsRead,
+	ScopeChatsWrite,
+	ScopeTransactionsRead,
+	ScopeTransactionsWrite,
+	ScopeProfileRead,
+}
+
+// IsValid memeriksa apakah scope dikenal
+func (s Scope) IsValid() bool {
+	for _, scope := range ValidScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessToken merepresentasikan personal access token untuk integrasi dan bot.
+// Token disimpan dalam bentuk hash; hanya awalannya yang disimpan apa adanya untuk ditampilkan.
+type AccessToken struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	PenggunaID uint   `gorm:"column:pengguna_id;not null" json:"pengguna_id"`
+	Name       string `gorm:"column:name;size:100;not null" json:"name"`
+	TokenHash  string `gorm:"column:token_hash;size:64;not null;uniqueIndex" json:"-"`
+	// TokenPrefix adalah beberapa karakter pertama token, untuk membantu pengguna mengenali token
+	TokenPrefix string `gorm:"column:token_prefix;size:16;not null" json:"token_prefix"`
+	// Scopes disimpan sebagai daftar scope yang dipisahkan spasi
+	Scopes     string     `gorm:"column:scopes;type:text;not null" json:"-"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at" json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at" json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (AccessToken) TableName() string {
+	return "token_akses"
+}
+
+// ScopeList mengembalikan daftar scope token
+func (t *AccessToken) ScopeList() []Scope {
+	fields := strings.Fields(t.Scopes)
+	scopes := make([]Scope, 0, len(fields))
+	for _, field := range fields {
+		scopes = append(scopes, Scope(field))
+	}
+	return scopes
+}
+
+// HasScope memeriksa apakah token memiliki scope tertentu
+func (t *AccessToken) HasScope(scope Scope) bool {
+	for _, s := range t.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive memeriksa apakah token belum dicabut dan belum kedaluwarsa
+func (t *AccessToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// AccessTokenResponse adalah data personal access token yang dikirim ke client
+type AccessTokenResponse struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"token_prefix"`
+	Scopes      []Scope    `json:"scopes"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	// Token hanya diisi satu kali saat token dibuat
+	Token string `json:"token,omitempty"`
+}
+
+// ToResponse mengubah AccessToken ke AccessTokenResponse
+func (t *AccessToken) ToResponse() AccessTokenResponse {
+	return AccessTokenResponse{
+		ID:          t.ID,
+		Name:        t.Name,
+		TokenPrefix: t.TokenPrefix,
+		Scopes:      t.ScopeList(),
+		ExpiresAt:   t.ExpiresAt,
+		LastUsedAt:  t.LastUsedAt,
+		CreatedAt:   t.CreatedAt,
+	}
+}
+
+// CreateAccessTokenRequest adalah data untuk membuat personal access token
+type CreateAccessTokenRequest struct {
+	Name   string  `json:"name" example:"Bot Telegram"`
+	Scopes []Scope `json:"scopes" example:"items:read,chats:write"`
+	// ExpiresInDays adalah masa berlaku token dalam hari, 0 berarti tidak kedaluwarsa
+	ExpiresInDays int `json:"expires_in_days" example:"90"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "testing"
+
+func TestAccessTokenHasScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes string
+		scope  Scope
+		want   bool
+	}{
+		{"scope ada", "items:read chats:read", ScopeChatsRead, true},
+		{"scope tidak ada", "items:read chats:read", ScopeItemsWrite, false},
+		{"scope baca tidak memberi akses tulis", "chats:read", ScopeChatsWrite, false},
+		{"spasi berlebih", "  items:read   profile:read ", ScopeProfileRead, true},
+		{"tanpa scope", "", ScopeItemsRead, false},
+		{"scope kosong", "items:read", "", false},
+		{"awalan scope tidak cukup", "items:readwrite", ScopeItemsRead, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &AccessToken{Scopes: tt.scopes}
+			if got := token.HasScope(tt.scope); got != tt.want {
+				t.Errorf("HasScope(%q) dengan scopes %q = %v, want %v", tt.scope, tt.scopes, got, tt.want)
+			}
+		})
+	}
+}
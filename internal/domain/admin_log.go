@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+)
+
+// AdminAction adalah jenis tindakan admin terhadap pengguna
+type AdminAction string
+
+const (
+	AdminActionChangeRole AdminAction = "change_role"
+	AdminActionSuspend    AdminAction = "suspend"
+	AdminActionUnsuspend  AdminAction = "unsuspend"
+	AdminActionHideReview AdminAction = "hide_review"
+	AdminActionShowReview AdminAction = "show_review"
+	// Tindakan terhadap laporan dan konten yang dilaporkan
+	AdminActionHideContent   AdminAction = "hide_content"
+	AdminActionShowContent   AdminAction = "show_content"
+	AdminActionResolveReport AdminAction = "resolve_report"
+)
+
+// AdminLog merepresentasikan catatan tindakan admin terhadap pengguna
+type AdminLog struct {
+	ID      uint `gorm:"primaryKey" json:"id"`
+	AdminID uint `gorm:"column:admin_id;not null" json:"admin_id"`
+	// TargetID adalah ID pengguna yang dikenai tindakan (untuk moderasi ulasan: penulis ulasan)
+	TargetID uint        `gorm:"column:target_id;not null" json:"target_id"`
+	Action   AdminAction `gorm:"column:action;size:50;not null" json:"action"`
+	// Detail berisi ringkasan perubahan, misalnya "user -> moderator"
+	Detail string `gorm:"column:detail;type:text" json:"detail"`
+	Reason string `gorm:"column:reason;type:text" json:"reason"`
+	// LaporanID menghubungkan tindakan dengan laporan yang ditindaklanjuti, nil jika tidak terkait laporan
+	LaporanID *uint     `gorm:"column:laporan_id" json:"laporan_id,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (AdminLog) TableName() string {
+	return "log_admin"
+}
+
+// ChangeRoleRequest adalah body request untuk mengubah role pengguna
+type ChangeRoleRequest struct {
+	Role   Role   `json:"role" example:"moderator"`
+	Reason string `json:"reason" example:"Membantu moderasi kategori Elektronik"`
+}
+
+// SuspendUserRequest adalah body request untuk menangguhkan pengguna
+type SuspendUserRequest struct {
+	Reason string `json:"reason" example:"Spam listing berulang"`
+	// Until adalah akhir masa penangguhan (RFC3339), kosong berarti permanen
+	Until *time.Time `json:"until" example:"2026-12-31T00:00:00Z"`
+}
+
+// UnsuspendUserRequest adalah body request untuk mencabut penangguhan pengguna
+type UnsuspendUserRequest struct {
+	Reason string `json:"reason" example:"Banding diterima"`
+}
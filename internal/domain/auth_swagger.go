@@ -8,8 +8,24 @@ type LoginRequestSwagger struct {
 
 // LoginResponseSwagger model for Swagger documentation
 type LoginResponseSwagger struct {
-	Token string            `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	User  UserResponseSwagger `json:"user"`
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string `json:"refresh_token" example:"q4f2N0w8dC1mX3p6Zr9tYb7uKe5sHj2aLv0oWi8gQc4"`
+	ExpiresIn    int64  `json:"expires_in" example:"900"`
+	// MFAEnrollmentRequired bernilai true jika pengguna (admin) wajib mengaktifkan 2FA
+	MFAEnrollmentRequired bool                `json:"mfa_enrollment_required" example:"false"`
+	User                  UserResponseSwagger `json:"user"`
+}
+
+// LoginMFARequiredResponseSwagger model for Swagger documentation
+type LoginMFARequiredResponseSwagger struct {
+	MFARequired bool   `json:"mfa_required" example:"true"`
+	MFAToken    string `json:"mfa_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+// LoginMFARequestSwagger model for Swagger documentation
+type LoginMFARequestSwagger struct {
+	MFAToken string `json:"mfa_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." binding:"required"`
+	Code     string `json:"code" example:"123456" binding:"required"`
 }
 
 // RegisterRequestSwagger model for Swagger documentation
@@ -28,16 +44,55 @@ type RegisterResponseSwagger struct {
 
 // RefreshTokenRequestSwagger model for Swagger documentation
 type RefreshTokenRequestSwagger struct {
-	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..." binding:"required"`
+	RefreshToken string `json:"refresh_token" example:"q4f2N0w8dC1mX3p6Zr9tYb7uKe5sHj2aLv0oWi8gQc4" binding:"required"`
 }
 
 // RefreshTokenResponseSwagger model for Swagger documentation
 type RefreshTokenResponseSwagger struct {
 	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string `json:"refresh_token" example:"Xo3kV9bR1nT6yQ2wE8cZ5mA0sD4fG7hJ1lP6uI3oK9e"`
+	ExpiresIn    int64  `json:"expires_in" example:"900"`
+}
+
+// LogoutRequestSwagger model for Swagger documentation
+type LogoutRequestSwagger struct {
+	RefreshToken string `json:"refresh_token,omitempty" example:"q4f2N0w8dC1mX3p6Zr9tYb7uKe5sHj2aLv0oWi8gQc4"`
 }
 
 // ResetPasswordRequestSwagger model for Swagger documentation
 type ResetPasswordRequestSwagger struct {
 	Email string `json:"email" example:"user@example.com" binding:"required,email"`
-} 
\ No newline at end of file
+}
+
+// ConfirmResetPasswordRequestSwagger model for Swagger documentation
+type ConfirmResetPasswordRequestSwagger struct {
+	Token    string `json:"token" example:"k2Jd9sLq0Xv4Tn7Rb1Wc8Ym5Pa3Hg6Ue0Zo2Fi9Qe1" binding:"required"`
+	Password string `json:"password" example:"passwordbaru123" binding:"required,min=8"`
+}
+
+// VerifyEmailRequestSwagger model for Swagger documentation
+type VerifyEmailRequestSwagger struct {
+	Token string `json:"token" example:"12.1767225600.Jx0cQ2kR9vH4mT1bN7sW3yL6pA8dF5gE0uZ2oI9qC1w" binding:"required"`
+}
+
+// MFAEnrollResponseSwagger model for Swagger documentation
+type MFAEnrollResponseSwagger struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	OTPAuthURI string `json:"otpauth_uri" example:"otpauth://totp/Jubel:user@example.com?algorithm=SHA1&digits=6&issuer=Jubel&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+}
+
+// MFACodeRequestSwagger model for Swagger documentation
+type MFACodeRequestSwagger struct {
+	Code string `json:"code" example:"123456" binding:"required"`
+}
+
+// DisableMFARequestSwagger model for Swagger documentation
+type DisableMFARequestSwagger struct {
+	Password string `json:"password" example:"password123" binding:"required"`
+	Code     string `json:"code" example:"123456" binding:"required"`
+}
+
+// RecoveryCodesResponseSwagger model for Swagger documentation
+type RecoveryCodesResponseSwagger struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"k3j9d-x8q2m,p0w7r-t5n1c"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+)
+
+// Block merepresentasikan pemblokiran satu pengguna oleh pengguna lain.
+// Pengguna yang diblokir tidak diberi tahu; permintaannya hanya ditolak dengan error umum.
+type Block struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	PemblokirID uint      `gorm:"column:pemblokir_id;not null" json:"pemblokir_id"`
+	DiblokirID  uint      `gorm:"column:diblokir_id;not null" json:"diblokir_id"`
+	Diblokir    User      `gorm:"foreignKey:DiblokirID" json:"-"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (Block) TableName() string {
+	return "blokir"
+}
+
+// BlockResponse adalah format respons untuk daftar pengguna yang diblokir
+type BlockResponse struct {
+	Pengguna  PublicUserResponse `json:"pengguna"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// ToResponse mengubah Block ke BlockResponse. Relasi Diblokir harus sudah dimuat.
+func (b *Block) ToResponse() BlockResponse {
+	return BlockResponse{
+		Pengguna:  b.Diblokir.ToPublicResponse(),
+		CreatedAt: b.CreatedAt,
+	}
+}
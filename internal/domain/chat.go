@@ -13,6 +13,8 @@ type Chat struct {
 	Pesan      string    `gorm:"type:text;not null" json:"pesan" validate:"required"`
 	Timestamp  time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"timestamp"`
 	Dibaca     bool      `gorm:"default:false" json:"dibaca"`
+	// HiddenAt: waktu pesan disembunyikan karena laporan pengguna, nil jika tampil
+	HiddenAt   *time.Time `gorm:"column:hidden_at" json:"-"`
 	
 	// Relasi
 	Pengirim   User      `gorm:"foreignKey:PengirimID" json:"pengirim,omitempty"`
@@ -34,8 +36,11 @@ type ChatResponse struct {
 	Pesan      string        `json:"pesan"`
 	Timestamp  time.Time     `json:"timestamp"`
 	Dibaca     bool          `json:"dibaca"`
-	Pengirim   UserResponse  `json:"pengirim,omitempty"`
-	Penerima   UserResponse  `json:"penerima,omitempty"`
+	// Disembunyikan berarti pesan disembunyikan karena laporan dan isinya dikosongkan
+	Disembunyikan bool       `json:"disembunyikan,omitempty"`
+	// Pengirim dan Penerima hanya berisi data publik, tanpa email, nomor HP dan alamat
+	Pengirim   PublicUserResponse `json:"pengirim,omitempty"`
+	Penerima   PublicUserResponse `json:"penerima,omitempty"`
 	Barang     ItemResponse  `json:"barang,omitempty"`
 }
 
@@ -51,12 +56,18 @@ func (c *Chat) ToResponse(includePengirim, includePenerima, includeBarang bool)
 		Dibaca:     c.Dibaca,
 	}
 
+	// Isi pesan yang disembunyikan tidak dikirim ke client; moderator melihatnya dari cuplikan laporan
+	if c.HiddenAt != nil {
+		response.Pesan = ""
+		response.Disembunyikan = true
+	}
+
 	if includePengirim {
-		response.Pengirim = c.Pengirim.ToResponse()
+		response.Pengirim = c.Pengirim.ToPublicResponse()
 	}
 
 	if includePenerima {
-		response.Penerima = c.Penerima.ToResponse()
+		response.Penerima = c.Penerima.ToPublicResponse()
 	}
 
 	if includeBarang {
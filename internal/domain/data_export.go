@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"time"
+)
+
+// DataExportStatus adalah status pembuatan arsip data pribadi
+type DataExportStatus string
+
+const (
+	DataExportPending    DataExportStatus = "pending"
+	DataExportProcessing DataExportStatus = "processing"
+	DataExportReady      DataExportStatus = "ready"
+	DataExportFailed     DataExportStatus = "failed"
+	// DataExportExpired berarti arsip sudah melewati masa simpan dan file-nya dihapus
+	DataExportExpired DataExportStatus = "expired"
+)
+
+// DataExport merepresentasikan permintaan ekspor data pribadi pengguna
+type DataExport struct {
+	ID         uint             `gorm:"primaryKey" json:"id"`
+	PenggunaID uint             `gorm:"column:pengguna_id;not null" json:"pengguna_id"`
+	Status     DataExportStatus `gorm:"column:status;size:20;not null;default:pending" json:"status"`
+	// FilePath adalah lokasi arsip ZIP di server, tidak pernah ditampilkan ke client
+	FilePath string `gorm:"column:file_path;size:255" json:"-"`
+	// Error berisi penyebab kegagalan untuk keperluan debugging
+	Error string `gorm:"column:error;type:text" json:"-"`
+	// CompletedAt adalah waktu arsip selesai dibuat
+	CompletedAt *time.Time `gorm:"column:completed_at" json:"completed_at"`
+	// ExpiresAt adalah waktu arsip dihapus dari server
+	ExpiresAt *time.Time `gorm:"column:expires_at" json:"expires_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (DataExport) TableName() string {
+	return "ekspor_data"
+}
+
+// IsActive memeriksa apakah ekspor masih menunggu atau sedang diproses
+func (e *DataExport) IsActive() bool {
+	return e.Status == DataExportPending || e.Status == DataExportProcessing
+}
+
+// IsDownloadable memeriksa apakah arsip siap dan belum melewati masa simpan
+func (e *DataExport) IsDownloadable(now time.Time) bool {
+	return e.Status == DataExportReady && e.FilePath != "" && e.ExpiresAt != nil && now.Before(*e.ExpiresAt)
+}
+
+// DataExportResponse adalah format respons untuk status ekspor data
+type DataExportResponse struct {
+	ID          uint             `json:"id"`
+	Status      DataExportStatus `json:"status"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty"`
+	// DownloadURL adalah tautan unduhan bertanda tangan, hanya terisi jika status ready
+	DownloadURL string `json:"download_url,omitempty"`
+	// DownloadURLExpiresAt adalah batas waktu tautan unduhan dapat dipakai
+	DownloadURLExpiresAt *time.Time `json:"download_url_expires_at,omitempty"`
+}
+
+// ToResponse mengubah DataExport ke DataExportResponse tanpa tautan unduhan
+func (e *DataExport) ToResponse() DataExportResponse {
+	return DataExportResponse{
+		ID:          e.ID,
+		Status:      e.Status,
+		CreatedAt:   e.CreatedAt,
+		CompletedAt: e.CompletedAt,
+		ExpiresAt:   e.ExpiresAt,
+	}
+}
+
+// ExportedItem adalah data barang di dalam arsip ekspor, termasuk barang yang sudah dihapus
+type ExportedItem struct {
+	ItemResponse
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+// NewExportedItem membuat ExportedItem dari data barang
+func NewExportedItem(item *Item) ExportedItem {
+	exported := ExportedItem{
+		ItemResponse: item.ToResponse(false),
+		UpdatedAt:    item.UpdatedAt,
+	}
+	if item.DeletedAt.Valid {
+		deletedAt := item.DeletedAt.Time
+		exported.DeletedAt = &deletedAt
+	}
+	return exported
+}
+
+// ExportedTransaction adalah data transaksi di dalam arsip ekspor
+type ExportedTransaction struct {
+	ID               uint              `json:"id"`
+	BarangID         uint              `json:"barang_id"`
+	NamaBarang       string            `json:"nama_barang,omitempty"`
+	Harga            float64           `json:"harga,omitempty"`
+	PembeliID        uint              `json:"pembeli_id"`
+	PenjualID        uint              `json:"penjual_id,omitempty"`
+	TanggalTransaksi time.Time         `json:"tanggal_transaksi"`
+	StatusTransaksi  TransactionStatus `json:"status_transaksi"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// NewExportedTransaction membuat ExportedTransaction dari data transaksi.
+// Data barang hanya terisi jika relasi Barang sudah dimuat.
+func NewExportedTransaction(t *Transaction) ExportedTransaction {
+	return ExportedTransaction{
+		ID:               t.ID,
+		BarangID:         t.BarangID,
+		NamaBarang:       t.Barang.NamaBarang,
+		Harga:            t.Barang.Harga,
+		PembeliID:        t.PembeliID,
+		PenjualID:        t.Barang.PenjualID,
+		TanggalTransaksi: t.TanggalTransaksi,
+		StatusTransaksi:  t.StatusTransaksi,
+		CreatedAt:        t.CreatedAt,
+		UpdatedAt:        t.UpdatedAt,
+	}
+}
+
+// ExportedChat adalah pesan chat di dalam arsip ekspor
+type ExportedChat struct {
+	ID         uint      `json:"id"`
+	BarangID   uint      `json:"barang_id"`
+	PengirimID uint      `json:"pengirim_id"`
+	PenerimaID uint      `json:"penerima_id"`
+	Pesan      string    `json:"pesan"`
+	Timestamp  time.Time `json:"timestamp"`
+	Dibaca     bool      `json:"dibaca"`
+}
+
+// NewExportedChat membuat ExportedChat dari data chat
+func NewExportedChat(chat *Chat) ExportedChat {
+	return ExportedChat{
+		ID:         chat.ID,
+		BarangID:   chat.BarangID,
+		PengirimID: chat.PengirimID,
+		PenerimaID: chat.PenerimaID,
+		Pesan:      chat.Pesan,
+		Timestamp:  chat.Timestamp,
+		Dibaca:     chat.Dibaca,
+	}
+}
+
+// ExportedImage adalah referensi gambar yang pernah diunggah pengguna
+type ExportedImage struct {
+	// Jenis adalah "barang" untuk gambar barang atau "avatar" untuk foto profil
+	Jenis    string `json:"jenis"`
+	BarangID uint   `json:"barang_id,omitempty"`
+	URL      string `json:"url"`
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Follow merepresentasikan pengguna yang mengikuti penjual
+type Follow struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	PengikutID uint      `gorm:"column:pengikut_id;not null" json:"pengikut_id"`
+	DiikutiID  uint      `gorm:"column:diikuti_id;not null" json:"diikuti_id"`
+	Pengikut   User      `gorm:"foreignKey:PengikutID" json:"-"`
+	Diikuti    User      `gorm:"foreignKey:DiikutiID" json:"-"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (Follow) TableName() string {
+	return "mengikuti"
+}
+
+// FollowResponse adalah format respons untuk daftar pengikut dan pengguna yang diikuti
+type FollowResponse struct {
+	Pengguna  PublicUserResponse `json:"pengguna"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// NewFollowResponse membuat FollowResponse untuk pengguna di sisi lain hubungan mengikuti
+func NewFollowResponse(user *User, createdAt time.Time) FollowResponse {
+	return FollowResponse{
+		Pengguna:  user.ToPublicResponse(),
+		CreatedAt: createdAt,
+	}
+}
+
+// FeedCursor menandai posisi barang terakhir yang sudah ditampilkan di feed.
+// Barang diurutkan dari yang terbaru, sehingga halaman berikutnya dimulai dari barang
+// yang lebih lama dari cursor.
+type FeedCursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// Encode mengubah cursor menjadi string yang aman dipakai di query parameter
+func (c FeedCursor) Encode() string {
+	raw := fmt.Sprintf("%d:%d", c.CreatedAt.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseFeedCursor membaca cursor yang dibuat oleh FeedCursor.Encode
+func ParseFeedCursor(value string) (*FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("cursor tidak valid: %w", err)
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("cursor tidak valid")
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("cursor tidak valid: %w", err)
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("cursor tidak valid: %w", err)
+	}
+
+	return &FeedCursor{
+		CreatedAt: time.Unix(0, nanos).UTC(),
+		ID:        uint(id),
+	}, nil
+}
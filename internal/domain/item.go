@@ -27,6 +27,9 @@ const (
 	CategoryLainnya    ItemCategory = "Lainnya"
 )
 
+// ItemCategories berisi semua kategori barang
+var ItemCategories = []ItemCategory{CategoryBuku, CategoryElektronik, CategoryPerabotan, CategoryKosKosan, CategoryLainnya}
+
 // Item merepresentasikan barang yang dijual
 type Item struct {
 	ID         uint           `gorm:"primaryKey" json:"id"`
@@ -37,10 +40,14 @@ type Item struct {
 	Deskripsi  string         `gorm:"type:text" json:"deskripsi"`
 	Gambar     string         `gorm:"size:255" json:"gambar"`
 	Status     ItemStatus     `gorm:"type:item_status;default:Tersedia" json:"status"`
+	// HiddenAt: waktu barang disembunyikan karena laporan pengguna, nil jika tampil
+	HiddenAt   *time.Time     `gorm:"column:hidden_at" json:"-"`
 	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `json:"-"`
 	Penjual    User           `gorm:"foreignKey:PenjualID" json:"penjual,omitempty"`
+	// Images adalah galeri gambar barang, diurutkan berdasarkan Urutan
+	Images     []ItemImage    `gorm:"foreignKey:BarangID" json:"images,omitempty"`
 }
 
 // TableName mengatur nama tabel di database
@@ -56,17 +63,60 @@ type ItemResponse struct {
 	Harga      float64      `json:"harga"`
 	Kategori   ItemCategory `json:"kategori"`
 	Deskripsi  string       `json:"deskripsi"`
+	// Gambar adalah URL gambar sampul, sama dengan gambar di Images yang ditandai sampul
 	Gambar     string       `json:"gambar"`
+	// GambarThumb dan GambarMedium adalah varian kecil gambar sampul untuk daftar dan detail barang
+	GambarThumb  string     `json:"gambar_thumb"`
+	GambarMedium string     `json:"gambar_medium"`
+	Images     []ItemImageResponse `json:"images"`
 	Status     ItemStatus   `json:"status"`
 	CreatedAt  string       `json:"created_at"`
-	Penjual    *UserResponse `json:"penjual,omitempty"`
+	Penjual    *PublicUserResponse `json:"penjual,omitempty"`
+	// Disembunyikan hanya terlihat oleh pemilik dan moderator, karena barang tersembunyi tidak tampil untuk publik
+	Disembunyikan bool `json:"disembunyikan,omitempty"`
+	// Sorotan hanya ada di hasil pencarian
+	Sorotan *ItemHighlight `json:"sorotan,omitempty"`
+}
+
+// ItemSort adalah urutan daftar barang
+type ItemSort string
+
+const (
+	ItemSortNewest    ItemSort = "newest"
+	ItemSortRelevance ItemSort = "relevance"
+)
+
+// IsValid memeriksa apakah urutan dikenal oleh sistem. Kosong berarti urutan default (newest).
+func (s ItemSort) IsValid() bool {
+	return s == "" || s == ItemSortNewest || s == ItemSortRelevance
+}
+
+// ItemFilter adalah filter daftar barang
+type ItemFilter struct {
+	// Search dicocokkan dengan nama, deskripsi dan kategori barang
+	Search   string
+	Kategori string
+	// Status kosong berarti hanya barang Tersedia
+	Status string
+	// ViewerID tidak 0 berarti barang milik pengguna yang diblokir viewer tidak ditampilkan
+	ViewerID uint
+	// Sort relevance hanya berlaku jika Search diisi
+	Sort ItemSort
+}
+
+// ItemHighlight berisi cuplikan hasil pencarian dengan kata yang cocok diapit <mark></mark>.
+// Teks barang sudah di-escape sebagai HTML sehingga aman ditampilkan sebagai HTML.
+type ItemHighlight struct {
+	NamaBarang string `json:"nama_barang"`
+	Deskripsi  string `json:"deskripsi"`
 }
 
 // ToResponse mengkonversi model Item ke respons API
 func (i *Item) ToResponse(withPenjual bool) ItemResponse {
-	var penjualResponse *UserResponse
+	// Listing bersifat publik, sehingga penjual hanya ditampilkan dengan profil publiknya
+	var penjualResponse *PublicUserResponse
 	if withPenjual {
-		resp := i.Penjual.ToResponse()
+		resp := i.Penjual.ToPublicResponse()
 		penjualResponse = &resp
 	}
 
@@ -77,6 +127,16 @@ func (i *Item) ToResponse(withPenjual bool) ItemResponse {
 		gambarURL = strings.Replace(gambarURL, "/download", "/view", 1)
 	}
 
+	// Varian gambar sampul; gambar tanpa varian memakai ukuran penuh
+	gambarThumb, gambarMedium := gambarURL, gambarURL
+	for _, image := range i.Images {
+		if image.Sampul {
+			response := image.ToResponse()
+			gambarThumb, gambarMedium = response.ThumbURL, response.MediumURL
+			break
+		}
+	}
+
 	return ItemResponse{
 		ID:         i.ID,
 		NamaBarang: i.NamaBarang,
@@ -85,8 +145,17 @@ func (i *Item) ToResponse(withPenjual bool) ItemResponse {
 		Deskripsi:  i.Deskripsi,
 		Status:     i.Status,
 		Gambar:     gambarURL,
+		GambarThumb:  gambarThumb,
+		GambarMedium: gambarMedium,
+		Images:     NewItemImageResponses(i.Images),
 		PenjualID:  i.PenjualID,
 		Penjual:    penjualResponse,
 		CreatedAt:  i.CreatedAt.Format(time.RFC3339),
+		Disembunyikan: i.IsHidden(),
 	}
+}
+
+// IsHidden memeriksa apakah barang sedang disembunyikan karena laporan
+func (i *Item) IsHidden() bool {
+	return i.HiddenAt != nil
 }
\ No newline at end of file
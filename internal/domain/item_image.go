@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxItemImages adalah jumlah maksimal gambar dalam galeri satu barang
+const MaxItemImages = 10
+
+// ItemImageVariant adalah ukuran standar gambar barang yang dibuat saat upload
+type ItemImageVariant struct {
+	// Suffix adalah akhiran key file varian sebelum ekstensi, kosong untuk ukuran penuh
+	Suffix string
+	// MaxSide adalah panjang maksimal sisi terpanjang gambar dalam piksel
+	MaxSide int
+}
+
+var (
+	// ItemImageThumb dipakai di daftar barang
+	ItemImageThumb = ItemImageVariant{Suffix: "_t", MaxSide: 320}
+	// ItemImageMedium dipakai di halaman detail barang
+	ItemImageMedium = ItemImageVariant{Suffix: "_m", MaxSide: 800}
+	// ItemImageFull adalah gambar ukuran penuh, key-nya disimpan di ItemImage.FileKey
+	ItemImageFull = ItemImageVariant{Suffix: "", MaxSide: 1600}
+)
+
+// ItemImageVariants berisi semua varian gambar barang
+var ItemImageVariants = []ItemImageVariant{ItemImageThumb, ItemImageMedium, ItemImageFull}
+
+// NewItemImageKey membuat key file ukuran penuh untuk gambar ke-index dari satu upload barang.
+// ID barang dan waktu upload ditulis dalam basis 36 sehingga key varian terpanjang tetap
+// paling banyak 36 karakter, batas file ID Appwrite, misalnya "item_1_lun2elv9_0.jpg".
+func NewItemImageKey(itemID uint, uploadedAt time.Time, index int, ext string) string {
+	return "item_" + strconv.FormatUint(uint64(itemID), 36) +
+		"_" + strconv.FormatInt(uploadedAt.UnixMilli(), 36) +
+		"_" + strconv.FormatInt(int64(index), 36) + ext
+}
+
+// Key mengembalikan key file varian dari key file ukuran penuh,
+// misalnya "item_1_lun2elv9_0.jpg" menjadi "item_1_lun2elv9_0_t.jpg"
+func (v ItemImageVariant) Key(key string) string {
+	ext := filepath.Ext(key)
+	return strings.TrimSuffix(key, ext) + v.Suffix + ext
+}
+
+// ItemImage merepresentasikan satu gambar dalam galeri barang
+type ItemImage struct {
+	ID       uint `gorm:"primaryKey" json:"id"`
+	BarangID uint `gorm:"column:barang_id;not null" json:"barang_id"`
+	// FileKey adalah key file ukuran penuh di storage, kosong untuk gambar lama yang disimpan sebelum galeri ada.
+	// Key varian lainnya didapat dari ItemImageVariant.Key.
+	FileKey string `gorm:"column:file_key;size:255" json:"-"`
+	// URL adalah URL gambar ukuran penuh, ThumbURL dan MediumURL adalah URL variannya.
+	// Gambar lama tidak memiliki varian sehingga ThumbURL dan MediumURL kosong.
+	URL        string `gorm:"column:url;size:255;not null" json:"url"`
+	ThumbURL   string `gorm:"column:thumb_url;size:255" json:"thumb_url"`
+	MediumURL  string `gorm:"column:medium_url;size:255" json:"medium_url"`
+	Keterangan string `gorm:"column:keterangan;size:255" json:"keterangan"`
+	// Urutan menentukan posisi gambar di galeri, dimulai dari 0
+	Urutan int `gorm:"column:urutan;not null;default:0" json:"urutan"`
+	// Sampul menandai gambar yang ditampilkan sebagai gambar utama barang (kolom gambar)
+	Sampul    bool      `gorm:"column:sampul;not null;default:false" json:"sampul"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (ItemImage) TableName() string {
+	return "item_images"
+}
+
+// ItemImageResponse adalah format respons untuk gambar barang
+type ItemImageResponse struct {
+	ID         uint   `json:"id"`
+	URL        string `json:"url"`
+	ThumbURL   string `json:"thumb_url"`
+	MediumURL  string `json:"medium_url"`
+	Keterangan string `json:"keterangan"`
+	Urutan     int    `json:"urutan"`
+	Sampul     bool   `json:"sampul"`
+}
+
+// ToResponse mengubah ItemImage ke ItemImageResponse
+func (i *ItemImage) ToResponse() ItemImageResponse {
+	return ItemImageResponse{
+		ID:         i.ID,
+		URL:        i.URL,
+		ThumbURL:   fallbackURL(i.ThumbURL, i.URL),
+		MediumURL:  fallbackURL(i.MediumURL, i.URL),
+		Keterangan: i.Keterangan,
+		Urutan:     i.Urutan,
+		Sampul:     i.Sampul,
+	}
+}
+
+// fallbackURL mengembalikan url, atau fallback jika url kosong
+func fallbackURL(url, fallback string) string {
+	if url == "" {
+		return fallback
+	}
+	return url
+}
+
+// NewItemImageResponses mengubah daftar ItemImage ke ItemImageResponse, selalu berupa array (bukan null)
+func NewItemImageResponses(images []ItemImage) []ItemImageResponse {
+	responses := make([]ItemImageResponse, 0, len(images))
+	for i := range images {
+		responses = append(responses, images[i].ToResponse())
+	}
+	return responses
+}
+
+// ReorderItemImagesRequest adalah body request untuk mengubah urutan galeri barang
+type ReorderItemImagesRequest struct {
+	// ImageIDs berisi semua ID gambar barang dalam urutan baru
+	ImageIDs []uint `json:"image_ids" example:"7,5,6"`
+}
+
+// UpdateItemImageRequest adalah body request untuk mengubah keterangan atau sampul gambar
+type UpdateItemImageRequest struct {
+	Keterangan *string `json:"keterangan" example:"Tampak samping, ada lecet kecil"`
+	// Sampul true menjadikan gambar ini sampul barang. Sampul tidak dapat dilepas tanpa memilih gambar lain.
+	Sampul *bool `json:"sampul" example:"true"`
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStorageKeysFitAppwriteFileID(t *testing.T) {
+	// Batas panjang file ID Appwrite
+	const maxKeyLength = 36
+
+	// ID terbesar dan waktu upload yang jauh di masa depan menghasilkan key terpanjang
+	maxID := uint(math.MaxUint)
+	farFuture := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	keys := []string{AvatarThumbKey(NewAvatarKey(maxID, farFuture, ".jpg"))}
+	for index := 0; index < MaxItemImages; index++ {
+		key := NewItemImageKey(maxID, farFuture, index, ".jpg")
+		for _, variant := range ItemImageVariants {
+			keys = append(keys, variant.Key(key))
+		}
+	}
+	for _, key := range keys {
+		if len(key) > maxKeyLength {
+			t.Errorf("key %q berisi %d karakter, maksimal %d", key, len(key), maxKeyLength)
+		}
+	}
+}
+
+func TestNewItemImageKey(t *testing.T) {
+	uploadedAt := time.UnixMilli(1711194000000)
+	key := NewItemImageKey(1, uploadedAt, 0, ".jpg")
+	if key != "item_1_lu40q4qo_0.jpg" {
+		t.Errorf("NewItemImageKey = %q, want item_1_lu40q4qo_0.jpg", key)
+	}
+	if got := ItemImageThumb.Key(key); got != "item_1_lu40q4qo_0_t.jpg" {
+		t.Errorf("ItemImageThumb.Key = %q", got)
+	}
+	if got := ItemImageMedium.Key(key); got != "item_1_lu40q4qo_0_m.jpg" {
+		t.Errorf("ItemImageMedium.Key = %q", got)
+	}
+	if got := ItemImageFull.Key(key); got != key {
+		t.Errorf("ItemImageFull.Key = %q, want %q", got, key)
+	}
+}
@@ -23,7 +23,7 @@ type UpdateItemStatusRequest struct {
 
 // UploadImageResponse model untuk keperluan dokumentasi Swagger
 type UploadImageResponse struct {
-	FileName string `json:"file_name" example:"item_1_1620000000.jpg"`
-	FileID   string `json:"file_id" example:"item_1_1620000000"`
-	ViewURL  string `json:"view_url" example:"http://endpoint.com/storage/buckets/bucket-id/files/file-id/view?project=project-id"`
+	FileName string `json:"file_name" example:"item_1_ko7u6800_0.jpg"`
+	FileID   string `json:"file_id" example:"item_1_ko7u6800_0.jpg"`
+	ViewURL  string `json:"view_url" example:"/uploads/item_1_ko7u6800_0.jpg"`
 } 
\ No newline at end of file
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+)
+
+// ClientInfo berisi informasi perangkat yang melakukan request
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// LoginAttempt menyimpan jumlah percobaan login gagal untuk satu kunci
+// (misalnya email atau alamat IP) beserta status pengunciannya
+type LoginAttempt struct {
+	Key           string     `gorm:"column:attempt_key;primaryKey;size:255" json:"key"`
+	Failures      int        `gorm:"column:failures;not null" json:"failures"`
+	LastFailureAt time.Time  `gorm:"column:last_failure_at;not null" json:"last_failure_at"`
+	LockedUntil   *time.Time `gorm:"column:locked_until" json:"locked_until,omitempty"`
+}
+
+// TableName mengatur nama tabel di database
+func (LoginAttempt) TableName() string {
+	return "percobaan_login"
+}
+
+// RetryAfter mengembalikan sisa waktu penguncian, atau 0 jika tidak sedang dikunci
+func (a *LoginAttempt) RetryAfter(now time.Time) time.Duration {
+	if a == nil || a.LockedUntil == nil || !now.Before(*a.LockedUntil) {
+		return 0
+	}
+	return a.LockedUntil.Sub(now)
+}
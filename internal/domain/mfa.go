@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+)
+
+// RecoveryCode merepresentasikan kode pemulihan 2FA sekali pakai yang disimpan dalam bentuk hash
+type RecoveryCode struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	PenggunaID uint       `gorm:"column:pengguna_id;not null" json:"pengguna_id"`
+	CodeHash   string     `gorm:"column:code_hash;size:64;not null" json:"-"`
+	UsedAt     *time.Time `gorm:"column:used_at" json:"used_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (RecoveryCode) TableName() string {
+	return "kode_pemulihan"
+}
+
+// MFAEnrollment berisi data yang dibutuhkan aplikasi authenticator untuk mendaftarkan 2FA
+type MFAEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// LoginResult adalah hasil login dengan password.
+// Jika MFARequired bernilai true, Tokens kosong dan MFAToken harus ditukar
+// melalui langkah kedua login bersama kode TOTP atau kode pemulihan.
+type LoginResult struct {
+	Tokens      *AuthTokens
+	User        *UserResponse
+	MFARequired bool
+	MFAToken    string
+	// MFAEnrollmentRequired bernilai true jika pengguna wajib 2FA tetapi belum mendaftarkannya
+	MFAEnrollmentRequired bool
+}
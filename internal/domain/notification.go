@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+)
+
+// NotificationType adalah jenis notifikasi dalam aplikasi
+type NotificationType string
+
+const (
+	// NotificationNewListing dikirim ke pengikut saat penjual yang diikuti memasang barang baru
+	NotificationNewListing NotificationType = "new_listing"
+)
+
+// Notification merepresentasikan notifikasi dalam aplikasi untuk seorang pengguna
+type Notification struct {
+	ID         uint             `gorm:"primaryKey" json:"id"`
+	PenggunaID uint             `gorm:"column:pengguna_id;not null" json:"-"`
+	Jenis      NotificationType `gorm:"column:jenis;size:30;not null" json:"jenis"`
+	Judul      string           `gorm:"column:judul;size:200;not null" json:"judul"`
+	Pesan      string           `gorm:"column:pesan;type:text" json:"pesan"`
+	// BarangID adalah barang yang terkait dengan notifikasi, nil jika tidak ada
+	BarangID  *uint      `gorm:"column:barang_id" json:"barang_id,omitempty"`
+	ReadAt    *time.Time `gorm:"column:read_at" json:"read_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (Notification) TableName() string {
+	return "notifikasi"
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+)
+
+// OIDCState menyimpan data login OpenID Connect yang sedang berlangsung.
+// State disimpan dalam bentuk hash dan hanya bisa dipakai sekali saat callback.
+type OIDCState struct {
+	StateHash    string    `gorm:"column:state_hash;primaryKey;size:64" json:"-"`
+	Provider     string    `gorm:"column:provider;size:50;not null" json:"provider"`
+	Nonce        string    `gorm:"column:nonce;size:64;not null" json:"-"`
+	CodeVerifier string    `gorm:"column:code_verifier;size:128;not null" json:"-"`
+	ExpiresAt    time.Time `gorm:"column:expires_at;not null" json:"expires_at"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (OIDCState) TableName() string {
+	return "oidc_state"
+}
+
+// OIDCIdentity menghubungkan akun di penyedia identitas (issuer + subject) dengan pengguna Jubel
+type OIDCIdentity struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	PenggunaID uint   `gorm:"column:pengguna_id;not null" json:"pengguna_id"`
+	Provider   string `gorm:"column:provider;size:50;not null" json:"provider"`
+	Subject    string `gorm:"column:subject;size:255;not null" json:"subject"`
+	// Email adalah email dari penyedia saat akun pertama kali dihubungkan
+	Email       string     `gorm:"column:email;size:100" json:"email"`
+	LastLoginAt *time.Time `gorm:"column:last_login_at" json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (OIDCIdentity) TableName() string {
+	return "identitas_oidc"
+}
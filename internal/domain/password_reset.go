@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+)
+
+// PasswordReset merepresentasikan token reset password yang hanya bisa dipakai sekali
+type PasswordReset struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	PenggunaID uint       `gorm:"column:pengguna_id;not null" json:"pengguna_id"`
+	TokenHash  string     `gorm:"column:token_hash;size:64;not null;uniqueIndex" json:"-"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	UsedAt     *time.Time `gorm:"column:used_at" json:"used_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (PasswordReset) TableName() string {
+	return "reset_password"
+}
+
+// IsActive memeriksa apakah token reset belum dipakai dan belum kedaluwarsa
+func (r *PasswordReset) IsActive(now time.Time) bool {
+	return r.UsedAt == nil && now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// PublicUserResponse adalah data pengguna yang aman ditampilkan kepada siapa saja,
+// tanpa email, nomor HP dan alamat
+type PublicUserResponse struct {
+	ID             uint      `json:"id"`
+	Nama           string    `json:"nama"`
+	AvatarURL      string    `json:"avatar_url,omitempty"`
+	AvatarThumbURL string    `json:"avatar_thumb_url,omitempty"`
+	BergabungSejak time.Time `json:"bergabung_sejak"`
+	// RataRataRating adalah rata-rata rating ulasan, null jika belum ada ulasan
+	RataRataRating *float64 `json:"rata_rata_rating"`
+	JumlahUlasan   int64    `json:"jumlah_ulasan"`
+}
+
+// ToPublicResponse mengubah User ke PublicUserResponse
+func (u *User) ToPublicResponse() PublicUserResponse {
+	return PublicUserResponse{
+		ID:             u.ID,
+		Nama:           u.Nama,
+		AvatarURL:      u.AvatarURL,
+		AvatarThumbURL: u.AvatarThumbURL,
+		BergabungSejak: u.CreatedAt,
+		RataRataRating: u.RatingRataRata,
+		JumlahUlasan:   u.JumlahUlasan,
+	}
+}
+
+// UserStats adalah statistik reputasi pengguna sebagai penjual
+type UserStats struct {
+	// JumlahBarang adalah jumlah barang yang sedang dijual (status Tersedia)
+	JumlahBarang int64
+	// JumlahTerjual adalah jumlah transaksi penjualan yang sudah selesai
+	JumlahTerjual int64
+	// PercakapanMasuk adalah jumlah percakapan yang dimulai pembeli tentang barang pengguna
+	PercakapanMasuk int64
+	// PercakapanDibalas adalah jumlah percakapan masuk yang pernah dibalas pengguna
+	PercakapanDibalas int64
+	// JumlahPengikut adalah jumlah pengguna yang mengikuti pengguna ini
+	JumlahPengikut int64
+	// JumlahMengikuti adalah jumlah penjual yang diikuti pengguna ini
+	JumlahMengikuti int64
+}
+
+// ResponseRate menghitung rasio percakapan masuk yang dibalas (0 sampai 1).
+// Mengembalikan nil jika belum ada percakapan masuk.
+func (s UserStats) ResponseRate() *float64 {
+	if s.PercakapanMasuk == 0 {
+		return nil
+	}
+	rate := math.Round(float64(s.PercakapanDibalas)/float64(s.PercakapanMasuk)*100) / 100
+	return &rate
+}
+
+// PublicProfile adalah profil publik penjual beserta statistik reputasinya
+type PublicProfile struct {
+	PublicUserResponse
+	JumlahBarang  int64 `json:"jumlah_barang"`
+	JumlahTerjual int64 `json:"jumlah_terjual"`
+	// TingkatRespons adalah rasio percakapan dari pembeli yang dibalas, null jika belum ada percakapan
+	TingkatRespons  *float64 `json:"tingkat_respons"`
+	JumlahPengikut  int64    `json:"jumlah_pengikut"`
+	JumlahMengikuti int64    `json:"jumlah_mengikuti"`
+}
+
+// NewPublicProfile membuat profil publik dari data pengguna dan statistiknya
+func NewPublicProfile(user *User, stats UserStats) PublicProfile {
+	return PublicProfile{
+		PublicUserResponse: user.ToPublicResponse(),
+		JumlahBarang:       stats.JumlahBarang,
+		JumlahTerjual:      stats.JumlahTerjual,
+		TingkatRespons:     stats.ResponseRate(),
+		JumlahPengikut:     stats.JumlahPengikut,
+		JumlahMengikuti:    stats.JumlahMengikuti,
+	}
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+)
+
+// RefreshToken merepresentasikan refresh token yang disimpan dalam bentuk hash.
+// Token yang dirotasi dari token yang sama memiliki FamilyID yang sama.
+type RefreshToken struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	PenggunaID uint   `gorm:"column:pengguna_id;not null" json:"pengguna_id"`
+	TokenHash  string `gorm:"column:token_hash;size:64;not null;uniqueIndex" json:"-"`
+	FamilyID   string `gorm:"column:family_id;size:36;not null" json:"family_id"`
+	// MFA menandai family yang berasal dari login dengan verifikasi dua langkah
+	MFA       bool       `gorm:"column:mfa;not null;default:false" json:"mfa"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at" json:"used_at,omitempty"`
+	RevokedAt *time.Time `gorm:"column:revoked_at" json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (RefreshToken) TableName() string {
+	return "refresh_token"
+}
+
+// IsActive memeriksa apakah refresh token belum dipakai, belum dicabut, dan belum kedaluwarsa
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.UsedAt == nil && t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// AuthTokens berisi token akses dan refresh token yang diberikan saat login atau refresh
+type AuthTokens struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"time"
+)
+
+// ReportTargetType adalah jenis konten yang dilaporkan
+type ReportTargetType string
+
+const (
+	ReportTargetItem ReportTargetType = "item"
+	ReportTargetChat ReportTargetType = "chat"
+	ReportTargetUser ReportTargetType = "user"
+)
+
+// IsValid memeriksa apakah jenis target dikenal oleh sistem
+func (t ReportTargetType) IsValid() bool {
+	return t == ReportTargetItem || t == ReportTargetChat || t == ReportTargetUser
+}
+
+// ReportReason adalah kategori alasan laporan
+type ReportReason string
+
+const (
+	ReportReasonSpam       ReportReason = "spam"
+	ReportReasonScam       ReportReason = "scam"
+	ReportReasonProhibited ReportReason = "prohibited"
+	ReportReasonHarassment ReportReason = "harassment"
+	ReportReasonOther      ReportReason = "other"
+)
+
+// IsValid memeriksa apakah kategori alasan dikenal oleh sistem
+func (r ReportReason) IsValid() bool {
+	switch r {
+	case ReportReasonSpam, ReportReasonScam, ReportReasonProhibited, ReportReasonHarassment, ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+// ReportStatus adalah status laporan dalam antrean moderasi
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"
+	ReportReviewing ReportStatus = "reviewing"
+	ReportActioned  ReportStatus = "actioned"
+	ReportDismissed ReportStatus = "dismissed"
+)
+
+// IsValid memeriksa apakah status dikenal oleh sistem
+func (s ReportStatus) IsValid() bool {
+	return s == ReportOpen || s == ReportReviewing || s == ReportActioned || s == ReportDismissed
+}
+
+// IsPending memeriksa apakah laporan masih menunggu keputusan moderator
+func (s ReportStatus) IsPending() bool {
+	return s == ReportOpen || s == ReportReviewing
+}
+
+// Report merepresentasikan laporan pengguna terhadap barang, pesan chat atau pengguna lain
+type Report struct {
+	ID         uint             `gorm:"primaryKey" json:"id"`
+	PelaporID  uint             `gorm:"column:pelapor_id;not null" json:"pelapor_id"`
+	TargetType ReportTargetType `gorm:"column:target_type;size:20;not null" json:"target_type"`
+	TargetID   uint             `gorm:"column:target_id;not null" json:"target_id"`
+	// PemilikTargetID adalah pengguna yang bertanggung jawab atas konten: penjual barang,
+	// pengirim pesan atau pengguna yang dilaporkan
+	PemilikTargetID uint         `gorm:"column:pemilik_target_id;not null" json:"pemilik_target_id"`
+	Alasan          ReportReason `gorm:"column:alasan;size:30;not null" json:"alasan"`
+	Deskripsi       string       `gorm:"column:deskripsi;type:text" json:"deskripsi"`
+	// Cuplikan adalah salinan konten saat dilaporkan, sebagai bukti jika konten diubah atau dihapus
+	Cuplikan string       `gorm:"column:cuplikan;type:text" json:"cuplikan"`
+	Status   ReportStatus `gorm:"column:status;size:20;not null;default:open" json:"status"`
+	// ModeratorID adalah moderator yang ditugaskan menangani laporan
+	ModeratorID         *uint      `gorm:"column:moderator_id" json:"moderator_id"`
+	CatatanPenyelesaian string     `gorm:"column:catatan_penyelesaian;type:text" json:"catatan_penyelesaian"`
+	ResolvedAt          *time.Time `gorm:"column:resolved_at" json:"resolved_at"`
+	CreatedAt           time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relasi
+	Pelapor   User       `gorm:"foreignKey:PelaporID" json:"-"`
+	Moderator *User      `gorm:"foreignKey:ModeratorID" json:"-"`
+	Tindakan  []AdminLog `gorm:"foreignKey:LaporanID" json:"-"`
+}
+
+// TableName mengatur nama tabel di database
+func (Report) TableName() string {
+	return "laporan"
+}
+
+// CreateReportRequest adalah body request untuk membuat laporan
+type CreateReportRequest struct {
+	TargetType ReportTargetType `json:"target_type" example:"item"`
+	TargetID   uint             `json:"target_id" example:"12"`
+	Alasan     ReportReason     `json:"alasan" example:"scam"`
+	Deskripsi  string           `json:"deskripsi" example:"Penjual meminta transfer di luar aplikasi"`
+}
+
+// AssignReportRequest adalah body request untuk menugaskan laporan ke moderator
+type AssignReportRequest struct {
+	// ModeratorID kosong berarti laporan ditugaskan ke diri sendiri
+	ModeratorID uint `json:"moderator_id" example:"3"`
+}
+
+// ResolveReportRequest adalah body request untuk menyelesaikan laporan
+type ResolveReportRequest struct {
+	// Status harus actioned atau dismissed
+	Status  ReportStatus `json:"status" example:"actioned"`
+	Catatan string       `json:"catatan" example:"Barang melanggar aturan, penjual ditangguhkan 7 hari"`
+	// AdminLogIDs adalah ID catatan tindakan admin (misalnya penangguhan) yang dilakukan untuk laporan ini
+	AdminLogIDs []uint `json:"admin_log_ids" example:"41"`
+}
+
+// ReportFilter adalah filter antrean laporan
+type ReportFilter struct {
+	Status     ReportStatus
+	TargetType ReportTargetType
+	// ModeratorID 0 berarti semua laporan
+	ModeratorID uint
+	// Unassigned hanya menampilkan laporan yang belum ditugaskan
+	Unassigned bool
+}
+
+// ReportResponse adalah format respons untuk data laporan
+type ReportResponse struct {
+	ID                  uint             `json:"id"`
+	TargetType          ReportTargetType `json:"target_type"`
+	TargetID            uint             `json:"target_id"`
+	Alasan              ReportReason     `json:"alasan"`
+	Deskripsi           string           `json:"deskripsi"`
+	Status              ReportStatus     `json:"status"`
+	CatatanPenyelesaian string           `json:"catatan_penyelesaian,omitempty"`
+	ResolvedAt          *time.Time       `json:"resolved_at,omitempty"`
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+}
+
+// ToResponse mengubah Report ke ReportResponse untuk pelapor
+func (r *Report) ToResponse() ReportResponse {
+	return ReportResponse{
+		ID:                  r.ID,
+		TargetType:          r.TargetType,
+		TargetID:            r.TargetID,
+		Alasan:              r.Alasan,
+		Deskripsi:           r.Deskripsi,
+		Status:              r.Status,
+		CatatanPenyelesaian: r.CatatanPenyelesaian,
+		ResolvedAt:          r.ResolvedAt,
+		CreatedAt:           r.CreatedAt,
+		UpdatedAt:           r.UpdatedAt,
+	}
+}
+
+// AdminReportResponse adalah format respons laporan di antrean moderasi
+type AdminReportResponse struct {
+	ReportResponse
+	PemilikTargetID uint                `json:"pemilik_target_id"`
+	Cuplikan        string              `json:"cuplikan"`
+	Pelapor         PublicUserResponse  `json:"pelapor"`
+	Moderator       *PublicUserResponse `json:"moderator,omitempty"`
+	// TargetDisembunyikan berarti target sedang disembunyikan sampai laporan ditinjau
+	TargetDisembunyikan bool `json:"target_disembunyikan"`
+	// JumlahLaporanTarget adalah jumlah pelapor berbeda yang laporannya terhadap target masih terbuka
+	JumlahLaporanTarget int64 `json:"jumlah_laporan_target"`
+	// Tindakan adalah catatan tindakan admin yang terkait dengan laporan
+	Tindakan []AdminLog `json:"tindakan"`
+}
+
+// NewAdminReportResponse membuat AdminReportResponse. Relasi Pelapor, Moderator dan Tindakan harus sudah dimuat.
+func NewAdminReportResponse(r *Report, targetHidden bool, openReports int64) AdminReportResponse {
+	response := AdminReportResponse{
+		ReportResponse:      r.ToResponse(),
+		PemilikTargetID:     r.PemilikTargetID,
+		Cuplikan:            r.Cuplikan,
+		Pelapor:             r.Pelapor.ToPublicResponse(),
+		TargetDisembunyikan: targetHidden,
+		JumlahLaporanTarget: openReports,
+		Tindakan:            r.Tindakan,
+	}
+	if r.Moderator != nil {
+		moderator := r.Moderator.ToPublicResponse()
+		response.Moderator = &moderator
+	}
+	if response.Tindakan == nil {
+		response.Tindakan = []AdminLog{}
+	}
+	return response
+}
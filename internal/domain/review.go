@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"time"
+)
+
+// ReviewerRole adalah peran penulis ulasan dalam transaksi
+type ReviewerRole string
+
+const (
+	ReviewerPembeli ReviewerRole = "pembeli"
+	ReviewerPenjual ReviewerRole = "penjual"
+)
+
+// Review merepresentasikan ulasan satu pihak terhadap pihak lain dalam transaksi yang sudah selesai
+type Review struct {
+	ID          uint `gorm:"primaryKey" json:"id"`
+	TransaksiID uint `gorm:"column:transaksi_id;not null" json:"transaksi_id"`
+	PenulisID   uint `gorm:"column:penulis_id;not null" json:"penulis_id"`
+	// TargetID adalah pengguna yang diulas
+	TargetID uint         `gorm:"column:target_id;not null" json:"target_id"`
+	Peran    ReviewerRole `gorm:"column:peran;size:10;not null" json:"peran"`
+	Rating   int          `gorm:"column:rating;not null" json:"rating"`
+	Komentar string       `gorm:"column:komentar;type:text" json:"komentar"`
+	// Balasan adalah tanggapan dari pengguna yang diulas
+	Balasan   string     `gorm:"column:balasan;type:text" json:"balasan"`
+	BalasanAt *time.Time `gorm:"column:balasan_at" json:"balasan_at"`
+	// HiddenAt terisi jika ulasan disembunyikan oleh admin atau moderator
+	HiddenAt     *time.Time `gorm:"column:hidden_at" json:"-"`
+	HiddenReason string     `gorm:"column:hidden_reason;type:text" json:"-"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relasi
+	Penulis User `gorm:"foreignKey:PenulisID" json:"penulis,omitempty"`
+}
+
+// TableName mengatur nama tabel di database
+func (Review) TableName() string {
+	return "ulasan"
+}
+
+// IsHidden memeriksa apakah ulasan disembunyikan oleh moderasi
+func (r *Review) IsHidden() bool {
+	return r.HiddenAt != nil
+}
+
+// ReviewResponse adalah format respons untuk data ulasan
+type ReviewResponse struct {
+	ID            uint               `json:"id"`
+	TransaksiID   uint               `json:"transaksi_id"`
+	TargetID      uint               `json:"target_id"`
+	Peran         ReviewerRole       `json:"peran"`
+	Rating        int                `json:"rating"`
+	Komentar      string             `json:"komentar"`
+	Balasan       string             `json:"balasan,omitempty"`
+	BalasanAt     *time.Time         `json:"balasan_at,omitempty"`
+	Disembunyikan bool               `json:"disembunyikan"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	Penulis       PublicUserResponse `json:"penulis"`
+}
+
+// ToResponse mengubah Review ke ReviewResponse
+func (r *Review) ToResponse() ReviewResponse {
+	return ReviewResponse{
+		ID:            r.ID,
+		TransaksiID:   r.TransaksiID,
+		TargetID:      r.TargetID,
+		Peran:         r.Peran,
+		Rating:        r.Rating,
+		Komentar:      r.Komentar,
+		Balasan:       r.Balasan,
+		BalasanAt:     r.BalasanAt,
+		Disembunyikan: r.IsHidden(),
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+		Penulis:       r.Penulis.ToPublicResponse(),
+	}
+}
+
+// ReviewRequest adalah body request untuk membuat atau mengubah ulasan
+type ReviewRequest struct {
+	Rating   int    `json:"rating" example:"5"`
+	Komentar string `json:"komentar" example:"Barang sesuai deskripsi, penjual ramah"`
+}
+
+// ReplyReviewRequest adalah body request untuk membalas ulasan
+type ReplyReviewRequest struct {
+	Balasan string `json:"balasan" example:"Terima kasih sudah membeli!"`
+}
+
+// HideReviewRequest adalah body request untuk menyembunyikan ulasan
+type HideReviewRequest struct {
+	Reason string `json:"reason" example:"Mengandung kata-kata kasar"`
+}
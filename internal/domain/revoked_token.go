@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+)
+
+// RevokedToken merepresentasikan token akses (JWT) yang dicabut sebelum kedaluwarsa
+type RevokedToken struct {
+	JTI        string    `gorm:"column:jti;primaryKey;size:36" json:"jti"`
+	PenggunaID uint      `gorm:"column:pengguna_id;not null" json:"pengguna_id"`
+	ExpiresAt  time.Time `gorm:"column:expires_at;not null" json:"expires_at"`
+	RevokedAt  time.Time `gorm:"column:revoked_at;default:CURRENT_TIMESTAMP" json:"revoked_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (RevokedToken) TableName() string {
+	return "token_dicabut"
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxSearchQueryLength adalah panjang maksimal kueri pencarian yang dicatat dan dipakai untuk saran
+const MaxSearchQueryLength = 100
+
+// SearchMatch menunjukkan cara pencarian teks di daftar barang menemukan barang
+type SearchMatch string
+
+const (
+	// SearchMatchNone berarti tidak ada pencarian teks atau tidak ada barang yang ditemukan
+	SearchMatchNone SearchMatch = ""
+	// SearchMatchFullText berarti barang ditemukan oleh full-text search
+	SearchMatchFullText SearchMatch = "full_text"
+	// SearchMatchSimilar berarti full-text search tidak menemukan barang dan barang ditemukan
+	// dari kemiripan nama, misalnya karena salah ketik
+	SearchMatchSimilar SearchMatch = "similar"
+)
+
+// SearchLog mencatat satu pencarian barang yang dilakukan melalui GET /items.
+// Log tidak menyimpan identitas pengguna, hanya hash pencari.
+type SearchLog struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+	// Kueri adalah teks pencarian yang sudah dinormalisasi dengan NormalizeSearchQuery
+	Kueri       string `gorm:"column:kueri;size:100;not null" json:"kueri"`
+	JumlahHasil int64  `gorm:"column:jumlah_hasil;not null" json:"jumlah_hasil"`
+	// PencariHash adalah HMAC dari ID pengguna atau alamat IP pencari, dipakai agar pencari yang sama
+	// hanya dihitung sekali di kueri populer dalam jendela deduplikasi
+	PencariHash string `gorm:"column:pencari_hash;size:64" json:"-"`
+	// Dihitung bernilai true jika pencarian menambah jumlah pencarian di kueri populer
+	Dihitung  bool      `gorm:"column:dihitung;not null;default:false" json:"dihitung"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName mengatur nama tabel di database
+func (SearchLog) TableName() string {
+	return "log_pencarian"
+}
+
+// PopularQuery adalah agregasi jumlah pencarian per kueri, dipakai untuk saran kueri populer.
+// Hanya kueri yang menemukan barang melalui full-text search dan tidak mengandung kata terlarang
+// yang dihitung, sekali per pencari dalam jendela deduplikasi.
+type PopularQuery struct {
+	Kueri          string    `gorm:"column:kueri;primaryKey;size:100" json:"kueri"`
+	Jumlah         int64     `gorm:"column:jumlah;not null" json:"jumlah"`
+	TerakhirDicari time.Time `gorm:"column:terakhir_dicari;not null" json:"terakhir_dicari"`
+}
+
+// TableName mengatur nama tabel di database
+func (PopularQuery) TableName() string {
+	return "kueri_populer"
+}
+
+// ItemSuggestion adalah barang yang namanya cocok dengan teks yang sedang diketik
+type ItemSuggestion struct {
+	ID         uint         `json:"id"`
+	NamaBarang string       `json:"nama_barang"`
+	Kategori   ItemCategory `json:"kategori"`
+}
+
+// SearchSuggestionResponse adalah format respons saran pencarian.
+// Setiap daftar selalu berupa array (bukan null).
+type SearchSuggestionResponse struct {
+	// Kueri berisi kueri populer yang diawali teks yang diketik
+	Kueri []string `json:"kueri"`
+	// Barang berisi barang yang namanya cocok
+	Barang []ItemSuggestion `json:"barang"`
+	// Kategori berisi kategori yang namanya cocok
+	Kategori []ItemCategory `json:"kategori"`
+}
+
+// NewSearchSuggestionResponse membuat SearchSuggestionResponse kosong
+func NewSearchSuggestionResponse() *SearchSuggestionResponse {
+	return &SearchSuggestionResponse{
+		Kueri:    []string{},
+		Barang:   []ItemSuggestion{},
+		Kategori: []ItemCategory{},
+	}
+}
+
+// NormalizeSearchQuery menyeragamkan kueri pencarian agar "Laptop  ASUS " dan "laptop asus"
+// dihitung sebagai kueri yang sama: huruf kecil, spasi dirapikan dan panjangnya dibatasi
+// MaxSearchQueryLength karakter
+func NormalizeSearchQuery(query string) string {
+	query = strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	if utf8.RuneCountInString(query) > MaxSearchQueryLength {
+		query = strings.TrimSpace(string([]rune(query)[:MaxSearchQueryLength]))
+	}
+	return query
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+)
+
+// Session merepresentasikan satu sesi login pada satu perangkat.
+// ID sesi sama dengan FamilyID refresh token yang diterbitkan saat login.
+type Session struct {
+	ID         string     `gorm:"primaryKey;size:36" json:"id"`
+	PenggunaID uint       `gorm:"column:pengguna_id;not null" json:"pengguna_id"`
+	UserAgent  string     `gorm:"column:user_agent;type:text" json:"user_agent"`
+	IPAddress  string     `gorm:"column:ip_address;size:45" json:"ip_address"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	LastSeenAt time.Time  `gorm:"column:last_seen_at;not null" json:"last_seen_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at,omitempty"`
+}
+
+// TableName mengatur nama tabel di database
+func (Session) TableName() string {
+	return "sesi"
+}
+
+// IsRevoked memeriksa apakah sesi sudah diakhiri
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// SessionResponse adalah data sesi yang dikirim ke client
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	// Current bernilai true untuk sesi yang sedang dipakai untuk request ini
+	Current bool `json:"current"`
+}
+
+// ToResponse mengubah Session ke SessionResponse
+func (s *Session) ToResponse(currentSessionID string) SessionResponse {
+	return SessionResponse{
+		ID:         s.ID,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		Current:    s.ID == currentSessionID,
+	}
+}
@@ -33,6 +33,15 @@ func (Transaction) TableName() string {
 	return "transaksi"
 }
 
+// IsFrozen memeriksa apakah transaksi pending dibekukan karena pembeli atau penjual sedang ditangguhkan.
+// Relasi Pembeli dan Barang.Penjual harus sudah dimuat agar hasilnya akurat.
+func (t *Transaction) IsFrozen(now time.Time) bool {
+	if t.StatusTransaksi != StatusPending {
+		return false
+	}
+	return t.Pembeli.IsSuspended(now) || t.Barang.Penjual.IsSuspended(now)
+}
+
 // TransactionResponse adalah format respons untuk data transaksi
 type TransactionResponse struct {
 	ID              uint              `json:"id"`
@@ -40,6 +49,8 @@ type TransactionResponse struct {
 	PembeliID       uint              `json:"pembeli_id"`
 	TanggalTransaksi time.Time         `json:"tanggal_transaksi"`
 	StatusTransaksi TransactionStatus `json:"status_transaksi"`
+	// Dibekukan bernilai true jika transaksi pending tidak dapat diproses karena salah satu pihak ditangguhkan
+	Dibekukan       bool              `json:"dibekukan"`
 	CreatedAt       time.Time         `json:"created_at"`
 	Barang          ItemResponse      `json:"barang,omitempty"`
 	Pembeli         UserResponse      `json:"pembeli,omitempty"`
@@ -53,6 +64,7 @@ func (t *Transaction) ToResponse(includeBarang, includePembeli bool) Transaction
 		PembeliID:       t.PembeliID,
 		TanggalTransaksi: t.TanggalTransaksi,
 		StatusTransaksi: t.StatusTransaksi,
+		Dibekukan:       t.IsFrozen(time.Now()),
 		CreatedAt:       t.CreatedAt,
 	}
 
@@ -43,4 +43,6 @@ type UserResponseSwagger struct {
 	NoHP   string `json:"no_hp" example:"081234567890"`
 	Alamat string `json:"alamat" example:"Jl. Contoh No. 123, Jakarta"`
 	Role   string `json:"role" example:"user"`
+	EmailVerified bool `json:"email_verified" example:"true"`
+	MFAEnabled    bool `json:"mfa_enabled" example:"false"`
 } 
\ No newline at end of file
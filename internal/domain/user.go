@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -11,10 +14,41 @@ import (
 type Role string
 
 const (
-	RoleUser  Role = "user"
-	RoleAdmin Role = "admin"
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
 )
 
+// AnonymizedUserName adalah nama yang menggantikan nama pengguna yang akunnya sudah dihapus
+const AnonymizedUserName = "Pengguna terhapus"
+
+// AvatarThumbSuffix adalah akhiran key file thumbnail foto profil, sebelum ekstensi
+const AvatarThumbSuffix = "_t"
+
+// NewAvatarKey membuat key file foto profil. ID pengguna dan waktu upload ditulis dalam basis 36
+// sehingga key thumbnail tetap paling banyak 36 karakter, batas file ID Appwrite,
+// misalnya "avatar_1_lun2elv9.jpg".
+func NewAvatarKey(userID uint, uploadedAt time.Time, ext string) string {
+	return "avatar_" + strconv.FormatUint(uint64(userID), 36) + "_" + strconv.FormatInt(uploadedAt.UnixMilli(), 36) + ext
+}
+
+// AvatarThumbKey mengembalikan key file thumbnail dari key file foto profil,
+// misalnya "avatar_1_lun2elv9.jpg" menjadi "avatar_1_lun2elv9_t.jpg"
+func AvatarThumbKey(key string) string {
+	ext := filepath.Ext(key)
+	return strings.TrimSuffix(key, ext) + AvatarThumbSuffix + ext
+}
+
+// RequiresMFA memeriksa apakah role wajib menggunakan verifikasi dua langkah
+func (r Role) RequiresMFA() bool {
+	return r == RoleAdmin || r == RoleModerator
+}
+
+// IsValid memeriksa apakah role dikenal oleh sistem
+func (r Role) IsValid() bool {
+	return r == RoleUser || r == RoleModerator || r == RoleAdmin
+}
+
 // User merepresentasikan pengguna dalam sistem
 type User struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
@@ -24,6 +58,39 @@ type User struct {
 	NoHP      string         `gorm:"column:no_hp;size:15;not null" json:"no_hp" validate:"required"`
 	Alamat    string         `gorm:"type:text" json:"alamat"`
 	Role      Role           `gorm:"type:user_role;default:user" json:"role"`
+	// TokenValidAfter: token yang diterbitkan sebelum waktu ini dianggap tidak berlaku
+	TokenValidAfter *time.Time `gorm:"column:token_valid_after" json:"-"`
+	// EmailVerifiedAt: waktu email pengguna diverifikasi, nil jika belum
+	EmailVerifiedAt *time.Time `gorm:"column:email_verified_at" json:"-"`
+	// TOTPSecret: secret TOTP (base32), terisi sejak pendaftaran 2FA dimulai
+	TOTPSecret string `gorm:"column:totp_secret;size:64" json:"-"`
+	// TOTPEnabledAt: waktu 2FA diaktifkan, nil jika 2FA belum aktif
+	TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at" json:"-"`
+	// TOTPLastStep: langkah waktu kode TOTP terakhir yang dipakai, untuk menolak kode yang dipakai ulang
+	TOTPLastStep int64 `gorm:"column:totp_last_step;not null;default:0" json:"-"`
+	// SuspendedAt: waktu akun ditangguhkan oleh admin, nil jika tidak ditangguhkan
+	SuspendedAt *time.Time `gorm:"column:suspended_at" json:"-"`
+	// SuspendedUntil: akhir masa penangguhan, nil berarti permanen (banned)
+	SuspendedUntil *time.Time `gorm:"column:suspended_until" json:"-"`
+	// SuspendReason: alasan penangguhan yang ditampilkan ke pengguna
+	SuspendReason string `gorm:"column:suspend_reason;type:text" json:"-"`
+	// RatingRataRata dan JumlahUlasan adalah agregat ulasan yang tidak disembunyikan.
+	// Hanya dibaca; nilainya dihitung ulang oleh ReviewRepository.RefreshUserRating.
+	RatingRataRata *float64 `gorm:"column:rating_rata_rata;<-:false" json:"-"`
+	JumlahUlasan   int64    `gorm:"column:jumlah_ulasan;<-:false" json:"-"`
+	// DeletionScheduledAt: waktu akun akan dianonimkan setelah pengguna meminta penghapusan, nil jika tidak dihapus
+	DeletionScheduledAt *time.Time `gorm:"column:deletion_scheduled_at" json:"-"`
+	// AnonymizedAt: waktu data pribadi pengguna dihapus. Baris pengguna tetap ada agar riwayat
+	// transaksi dan chat pihak lain tidak ikut hilang.
+	AnonymizedAt *time.Time `gorm:"column:anonymized_at" json:"-"`
+	// AvatarFileID: key file foto profil di storage, dipakai untuk menghapus file lama saat diganti.
+	// Key thumbnail-nya didapat dari AvatarThumbKey.
+	AvatarFileID string `gorm:"column:avatar_file_id;size:64" json:"-"`
+	// AvatarURL dan AvatarThumbURL adalah URL foto profil persegi dan thumbnail-nya
+	AvatarURL      string `gorm:"column:avatar_url;type:text" json:"-"`
+	AvatarThumbURL string `gorm:"column:avatar_thumb_url;type:text" json:"-"`
+	// HiddenAt: waktu profil dan barang pengguna disembunyikan karena laporan, nil jika tampil
+	HiddenAt *time.Time `gorm:"column:hidden_at" json:"-"`
 	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-"`
@@ -50,6 +117,61 @@ func (u *User) CheckPassword(password string) bool {
 	return err == nil
 }
 
+// InvalidateTokens membuat semua token yang sudah diterbitkan untuk pengguna ini tidak berlaku.
+// Waktu dibulatkan ke detik karena claim iat pada JWT hanya memiliki presisi detik.
+func (u *User) InvalidateTokens(now time.Time) {
+	validAfter := now.Truncate(time.Second)
+	u.TokenValidAfter = &validAfter
+}
+
+// IsEmailVerified memeriksa apakah email pengguna sudah diverifikasi
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsMFAEnabled memeriksa apakah verifikasi dua langkah pengguna sudah aktif
+func (u *User) IsMFAEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// IsSuspended memeriksa apakah akun pengguna sedang ditangguhkan pada waktu now
+func (u *User) IsSuspended(now time.Time) bool {
+	if u.SuspendedAt == nil {
+		return false
+	}
+	return u.SuspendedUntil == nil || now.Before(*u.SuspendedUntil)
+}
+
+// IsPendingDeletion memeriksa apakah akun sedang dalam masa tenggang sebelum dianonimkan
+func (u *User) IsPendingDeletion() bool {
+	return u.DeletionScheduledAt != nil && u.AnonymizedAt == nil
+}
+
+// IsAnonymized memeriksa apakah data pribadi pengguna sudah dihapus
+func (u *User) IsAnonymized() bool {
+	return u.AnonymizedAt != nil
+}
+
+// IsDeleted memeriksa apakah akun sudah dihapus atau sedang menunggu penghapusan
+func (u *User) IsDeleted() bool {
+	return u.DeletionScheduledAt != nil
+}
+
+// IsHidden memeriksa apakah profil pengguna sedang disembunyikan karena laporan
+func (u *User) IsHidden() bool {
+	return u.HiddenAt != nil
+}
+
+// RequiresMFA memeriksa apakah pengguna wajib menggunakan verifikasi dua langkah
+func (u *User) RequiresMFA() bool {
+	return u.Role.RequiresMFA()
+}
+
+// RestoreAccountRequest adalah body request untuk membatalkan penghapusan akun
+type RestoreAccountRequest struct {
+	Token string `json:"token" example:"12.1767225600.Jx0cQ2kR9vH4mT1bN7sW3yL6pA8dF5gE0uZ2oI9qC1w"`
+}
+
 // Untuk keamanan, hapus password saat mengembalikan data ke client
 type UserResponse struct {
 	ID        uint      `json:"id"`
@@ -58,6 +180,15 @@ type UserResponse struct {
 	NoHP      string    `json:"no_hp"`
 	Alamat    string    `json:"alamat"`
 	Role      Role      `json:"role"`
+	EmailVerified bool  `json:"email_verified"`
+	MFAEnabled    bool  `json:"mfa_enabled"`
+	Suspended      bool       `json:"suspended"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	// DeletionScheduledAt adalah waktu akun akan dianonimkan jika penghapusan tidak dibatalkan
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	// AvatarURL dan AvatarThumbURL kosong jika pengguna belum mengunggah foto profil
+	AvatarURL      string `json:"avatar_url,omitempty"`
+	AvatarThumbURL string `json:"avatar_thumb_url,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -71,6 +202,13 @@ func (u *User) ToResponse() UserResponse {
 		NoHP:      u.NoHP,
 		Alamat:    u.Alamat,
 		Role:      u.Role,
+		EmailVerified: u.IsEmailVerified(),
+		MFAEnabled:    u.IsMFAEnabled(),
+		Suspended:     u.IsSuspended(time.Now()),
+		SuspendedUntil: u.SuspendedUntil,
+		DeletionScheduledAt: u.DeletionScheduledAt,
+		AvatarURL:      u.AvatarURL,
+		AvatarThumbURL: u.AvatarThumbURL,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
 	}
@@ -3,7 +3,9 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"time"
 )
 
 // StandardError represents a standardized error with type, code and metadata
@@ -86,6 +88,37 @@ func ForbiddenError(message string, err error) *StandardError {
 	}
 }
 
+// ConflictError creates a new conflict error
+func ConflictError(message string, err error) *StandardError {
+	return &StandardError{
+		Err:     err,
+		Type:    "conflict_error",
+		Message: message,
+		Code:    http.StatusConflict,
+	}
+}
+
+// TooManyRequestsError creates a new too many requests error.
+// retryAfter is stored in the metadata so the response can carry a Retry-After header.
+func TooManyRequestsError(message string, retryAfter time.Duration, err error) *StandardError {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return (&StandardError{
+		Err:     err,
+		Type:    "too_many_requests_error",
+		Message: message,
+		Code:    http.StatusTooManyRequests,
+	}).WithMetadata("retryAfter", seconds)
+}
+
+// RetryAfter returns the number of seconds the client should wait before retrying, if any
+func (e *StandardError) RetryAfter() (int, bool) {
+	seconds, ok := e.Metadata["retryAfter"].(int)
+	return seconds, ok
+}
+
 // InternalError creates a new internal server error
 func InternalError(message string, err error) *StandardError {
 	return &StandardError{
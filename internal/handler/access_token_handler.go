@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/service"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+// AccessTokenHandler menangani endpoint personal access token pengguna
+type AccessTokenHandler struct {
+	accessTokenService service.AccessTokenService
+}
+
+// NewAccessTokenHandler membuat instance baru AccessTokenHandler
+func NewAccessTokenHandler(accessTokenService service.AccessTokenService) *AccessTokenHandler {
+	return &AccessTokenHandler{
+		accessTokenService: accessTokenService,
+	}
+}
+
+// CreateToken membuat personal access token baru
+// @Summary      Create personal access token
+// @Description  Membuat personal access token untuk integrasi dan bot. Token hanya ditampilkan sekali pada response ini.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.CreateAccessTokenRequest  true  "Nama, scope dan masa berlaku token"
+// @Security     BearerAuth
+// @Success      201  {object}  utils.StandardResponse{data=domain.AccessTokenResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Router       /users/me/tokens [post]
+func (h *AccessTokenHandler) CreateToken(c *gin.Context) {
+	// Dapatkan user ID dari context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req domain.CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Gagal membaca data: "+err.Error(), nil)
+		return
+	}
+
+	token, err := h.accessTokenService.Create(c.Request.Context(), userID.(uint), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Token berhasil dibuat, simpan token ini karena tidak akan ditampilkan lagi", token)
+}
+
+// GetMyTokens mendapatkan daftar personal access token pengguna
+// @Summary      List personal access tokens
+// @Description  Mendapatkan daftar personal access token yang belum dicabut (tanpa nilai token)
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=[]domain.AccessTokenResponse}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /users/me/tokens [get]
+func (h *AccessTokenHandler) GetMyTokens(c *gin.Context) {
+	// Dapatkan user ID dari context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	tokens, err := h.accessTokenService.List(c.Request.Context(), userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data token berhasil diambil", tokens)
+}
+
+// RevokeToken mencabut personal access token
+// @Summary      Revoke personal access token
+// @Description  Mencabut personal access token sehingga tidak bisa dipakai lagi
+// @Tags         users
+// @Produce      json
+// @Param        id   path      int  true  "Token ID"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /users/me/tokens/{id} [delete]
+func (h *AccessTokenHandler) RevokeToken(c *gin.Context) {
+	// Dapatkan user ID dari context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID tidak valid", nil)
+		return
+	}
+
+	if err := h.accessTokenService.Revoke(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token berhasil dicabut", nil)
+}
+
+// RegisterRoutes mendaftarkan route untuk AccessTokenHandler.
+// Personal access token hanya bisa dikelola dengan token login, bukan dengan personal access token lain.
+func (h *AccessTokenHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	tokens := router.Group("/users/me/tokens")
+	tokens.Use(authMiddleware)
+	{
+		tokens.POST("", h.CreateToken)
+		tokens.GET("", h.GetMyTokens)
+		tokens.DELETE("/:id", h.RevokeToken)
+	}
+}
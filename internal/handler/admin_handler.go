@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
+	"github.com/mfuadfakhruzzaki/jubel/internal/service"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+// AdminHandler menangani endpoint manajemen pengguna oleh admin
+type AdminHandler struct {
+	adminService service.AdminService
+}
+
+// NewAdminHandler membuat instance baru AdminHandler
+func NewAdminHandler(adminService service.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// ChangeRole mengubah role pengguna
+// @Summary      Change user role
+// @Description  Menaikkan atau menurunkan role pengguna (admin only). Semua sesi pengguna diakhiri agar role baru langsung berlaku.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                       true  "User ID"
+// @Param        request  body      domain.ChangeRoleRequest  true  "Role baru dan alasan"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.UserResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /admin/users/{id}/role [patch]
+func (h *AdminHandler) ChangeRole(c *gin.Context) {
+	id, ok := parseIDParam(c, "ID pengguna tidak valid")
+	if !ok {
+		return
+	}
+
+	var req domain.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.ValidationError("Gagal membaca data", err))
+		return
+	}
+
+	user, err := h.adminService.ChangeRole(c.Request.Context(), id, req)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengubah role pengguna")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Role pengguna berhasil diubah", user)
+}
+
+// SuspendUser menangguhkan pengguna
+// @Summary      Suspend user
+// @Description  Menangguhkan pengguna dengan alasan dan waktu berakhir opsional (tanpa waktu berakhir berarti permanen). Pengguna tidak bisa login, barangnya disembunyikan dan transaksi pending-nya dibekukan.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "User ID"
+// @Param        request  body      domain.SuspendUserRequest  true  "Alasan dan akhir penangguhan"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.UserResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /admin/users/{id}/suspension [post]
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	id, ok := parseIDParam(c, "ID pengguna tidak valid")
+	if !ok {
+		return
+	}
+
+	var req domain.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.ValidationError("Gagal membaca data", err))
+		return
+	}
+
+	user, err := h.adminService.Suspend(c.Request.Context(), id, req)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal menangguhkan pengguna")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pengguna berhasil ditangguhkan", user)
+}
+
+// UnsuspendUser mencabut penangguhan pengguna
+// @Summary      Unsuspend user
+// @Description  Mencabut penangguhan pengguna sebelum masa berlakunya habis
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                          true   "User ID"
+// @Param        request  body      domain.UnsuspendUserRequest  false  "Alasan pencabutan"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.UserResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /admin/users/{id}/suspension [delete]
+func (h *AdminHandler) UnsuspendUser(c *gin.Context) {
+	id, ok := parseIDParam(c, "ID pengguna tidak valid")
+	if !ok {
+		return
+	}
+
+	// Body bersifat opsional
+	var req domain.UnsuspendUserRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(errors.ValidationError("Gagal membaca data", err))
+			return
+		}
+	}
+
+	user, err := h.adminService.Unsuspend(c.Request.Context(), id, req)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mencabut penangguhan pengguna")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Penangguhan pengguna berhasil dicabut", user)
+}
+
+// GetLogs mendapatkan catatan tindakan admin
+// @Summary      List admin actions
+// @Description  Mendapatkan catatan tindakan admin terhadap pengguna beserta admin yang melakukannya (admin only)
+// @Tags         admin
+// @Produce      json
+// @Param        page       query    int  false  "Page number (default: 1)"
+// @Param        limit      query    int  false  "Items per page (default: 10)"
+// @Param        target_id  query    int  false  "Filter berdasarkan ID pengguna target"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.PaginatedResponse{data=[]domain.AdminLog}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /admin/logs [get]
+func (h *AdminHandler) GetLogs(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	var targetID uint64
+	if target := c.Query("target_id"); target != "" {
+		targetID, err = strconv.ParseUint(target, 10, 32)
+		if err != nil {
+			_ = c.Error(errors.ValidationError("target_id tidak valid", err))
+			return
+		}
+	}
+
+	logs, totalPages, totalItems, err := h.adminService.GetLogs(c.Request.Context(), page, limit, uint(targetID))
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil catatan admin")
+		return
+	}
+
+	utils.SuccessPaginatedResponse(c, http.StatusOK, "Catatan admin berhasil diambil", logs, utils.Meta{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	})
+}
+
+// parseIDParam membaca parameter id dari URL. Mengembalikan false jika ID tidak valid.
+func parseIDParam(c *gin.Context, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.Error(errors.ValidationError(message, err))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// abortWithServiceError meneruskan error service ke ErrorMiddleware.
+// Error selain StandardError dianggap sebagai kesalahan internal.
+func abortWithServiceError(c *gin.Context, err error, message string) {
+	if _, ok := errors.AsStandardError(err); !ok {
+		err = errors.InternalError(message, err)
+	}
+	_ = c.Error(err)
+}
+
+// RegisterRoutes mendaftarkan route untuk AdminHandler
+func (h *AdminHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, requirePermission func(policy.Action) gin.HandlerFunc) {
+	admin := router.Group("/admin")
+	admin.Use(authMiddleware)
+	{
+		admin.PATCH("/users/:id/role", requirePermission(policy.UserChangeRole), h.ChangeRole)
+		admin.POST("/users/:id/suspension", requirePermission(policy.UserSuspend), h.SuspendUser)
+		admin.DELETE("/users/:id/suspension", requirePermission(policy.UserSuspend), h.UnsuspendUser)
+		admin.GET("/logs", requirePermission(policy.AdminLogRead), h.GetLogs)
+	}
+}
@@ -2,9 +2,11 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
 	"github.com/mfuadfakhruzzaki/jubel/internal/service"
 	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
 )
@@ -99,7 +101,7 @@ func (h *AuthHandler) RegisterUser(c *gin.Context) {
 
 // LoginUser menangani login pengguna
 // @Summary      Login user
-// @Description  Melakukan login dan mendapatkan token JWT
+// @Description  Melakukan login dan mendapatkan token JWT. Jika verifikasi dua langkah aktif, response berisi mfa_token yang harus ditukar melalui /auth/login/mfa.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -107,6 +109,7 @@ func (h *AuthHandler) RegisterUser(c *gin.Context) {
 // @Success      200      {object}  utils.StandardResponse{data=domain.LoginResponseSwagger}
 // @Failure      400      {object}  utils.StandardResponse
 // @Failure      401      {object}  utils.StandardResponse
+// @Failure      429      {object}  utils.StandardResponse
 // @Failure      500      {object}  utils.StandardResponse
 // @Router       /auth/login [post]
 func (h *AuthHandler) LoginUser(c *gin.Context) {
@@ -134,15 +137,92 @@ func (h *AuthHandler) LoginUser(c *gin.Context) {
 	}
 
 	// Login
-	token, user, err := h.authService.Login(c.Request.Context(), loginData.Email, loginData.Password)
+	result, err := h.authService.Login(c.Request.Context(), loginData.Email, loginData.Password, clientInfo(c))
 	if err != nil {
+		// Penguncian login dikembalikan sebagai StandardError beserta Retry-After
+		if _, ok := errors.AsStandardError(err); ok {
+			_ = c.Error(err)
+			return
+		}
 		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error(), nil)
 		return
 	}
 
+	loginResponse(c, result)
+}
+
+// loginResponse mengirim hasil login, atau mfa_token jika langkah kedua (2FA) masih diperlukan
+func loginResponse(c *gin.Context, result *domain.LoginResult) {
+	// Lanjutkan ke langkah kedua jika 2FA aktif
+	if result.MFARequired {
+		utils.SuccessResponse(c, http.StatusOK, "Verifikasi dua langkah diperlukan", gin.H{
+			"mfa_required": true,
+			"mfa_token":    result.MFAToken,
+		})
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Login berhasil", gin.H{
-		"token": token,
-		"user":  user,
+		"token":                   result.Tokens.AccessToken,
+		"refresh_token":           result.Tokens.RefreshToken,
+		"expires_in":              result.Tokens.ExpiresIn,
+		"mfa_enrollment_required": result.MFAEnrollmentRequired && !result.User.MFAEnabled,
+		"user":                    result.User,
+	})
+}
+
+// LoginMFA menyelesaikan login dengan kode verifikasi dua langkah
+// @Summary      Login MFA step
+// @Description  Menukar mfa_token dari /auth/login dengan token JWT menggunakan kode TOTP atau kode pemulihan
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.LoginMFARequestSwagger  true  "Token sementara dan kode verifikasi"
+// @Success      200      {object}  utils.StandardResponse{data=domain.LoginResponseSwagger}
+// @Failure      400      {object}  utils.StandardResponse
+// @Failure      401      {object}  utils.StandardResponse
+// @Failure      429      {object}  utils.StandardResponse
+// @Router       /auth/login/mfa [post]
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	// Struct untuk data login langkah kedua
+	var mfaData struct {
+		MFAToken string `json:"mfa_token"`
+		Code     string `json:"code"`
+	}
+
+	// Binding JSON
+	if err := c.ShouldBindJSON(&mfaData); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Gagal membaca data: "+err.Error(), nil)
+		return
+	}
+
+	// Validasi manual
+	if mfaData.MFAToken == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "MFA token tidak boleh kosong", nil)
+		return
+	}
+
+	if mfaData.Code == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Kode verifikasi tidak boleh kosong", nil)
+		return
+	}
+
+	// Selesaikan login
+	tokens, user, err := h.authService.CompleteMFALogin(c.Request.Context(), mfaData.MFAToken, mfaData.Code, clientInfo(c))
+	if err != nil {
+		if _, ok := errors.AsStandardError(err); ok {
+			_ = c.Error(err)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login berhasil", gin.H{
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"user":          user,
 	})
 }
 
@@ -159,7 +239,36 @@ func (h *AuthHandler) LoginUser(c *gin.Context) {
 // @Failure      500      {object}  utils.StandardResponse
 // @Router       /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	// ... existing code ...
+	// Struct untuk data refresh token
+	var refreshData struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	// Binding JSON
+	if err := c.ShouldBindJSON(&refreshData); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Gagal membaca data: "+err.Error(), nil)
+		return
+	}
+
+	// Validasi manual
+	if refreshData.RefreshToken == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Refresh token tidak boleh kosong", nil)
+		return
+	}
+
+	// Rotasi refresh token
+	tokens, err := h.authService.RefreshToken(c.Request.Context(), refreshData.RefreshToken, clientInfo(c))
+	if err != nil {
+		// Akun yang ditangguhkan dikembalikan sebagai StandardError (403)
+		if _, ok := errors.AsStandardError(err); ok {
+			_ = c.Error(err)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token berhasil diperbarui", tokens)
 }
 
 // Logout menangani proses logout pengguna
@@ -168,38 +277,377 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 // @Tags         auth
 // @Accept       json
 // @Produce      json
+// @Param        request  body      domain.LogoutRequestSwagger  false  "Refresh token yang ikut dicabut"
 // @Security     BearerAuth
 // @Success      200  {object}  utils.StandardResponse
 // @Failure      401  {object}  utils.StandardResponse
 // @Failure      500  {object}  utils.StandardResponse
 // @Router       /auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// ... existing code ...
+	// Dapatkan user ID dan token dari context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	tokenID := c.GetString("tokenID")
+	sessionID := c.GetString("sessionID")
+	tokenExpiresAt, _ := c.Get("tokenExpiresAt")
+
+	// Body bersifat opsional
+	var logoutData struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&logoutData); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Gagal membaca data: "+err.Error(), nil)
+			return
+		}
+	}
+
+	// Cabut token
+	if err := h.authService.Logout(c.Request.Context(), userID.(uint), tokenID, sessionID, tokenExpiresAt.(time.Time), logoutData.RefreshToken); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logout berhasil", nil)
 }
 
 // ResetPassword mengirim email untuk reset password
 // @Summary      Reset password
-// @Description  Mengirim email untuk reset password
+// @Description  Mengirim email berisi tautan reset password. Response selalu sukses walaupun email tidak terdaftar.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        request  body      domain.ResetPasswordRequestSwagger  true  "Reset password data"
 // @Success      200      {object}  utils.StandardResponse
 // @Failure      400      {object}  utils.StandardResponse
-// @Failure      404      {object}  utils.StandardResponse
 // @Failure      500      {object}  utils.StandardResponse
 // @Router       /auth/reset-password [post]
 func (h *AuthHandler) ResetPassword(c *gin.Context) {
-	// ... existing code ...
+	// Struct untuk data reset password
+	var resetData struct {
+		Email string `json:"email"`
+	}
+
+	// Binding JSON
+	if err := c.ShouldBindJSON(&resetData); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Gagal membaca data: "+err.Error(), nil)
+		return
+	}
+
+	// Validasi manual
+	if resetData.Email == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Email tidak boleh kosong", nil)
+		return
+	}
+
+	// Kirim email reset password
+	if err := h.authService.RequestPasswordReset(c.Request.Context(), resetData.Email); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal mengirim email reset password", nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Jika email terdaftar, tautan reset password telah dikirim", nil)
+}
+
+// ConfirmResetPassword mengganti password menggunakan token reset password
+// @Summary      Confirm reset password
+// @Description  Mengganti password menggunakan token dari email reset password
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.ConfirmResetPasswordRequestSwagger  true  "Token dan password baru"
+// @Success      200      {object}  utils.StandardResponse
+// @Failure      400      {object}  utils.StandardResponse
+// @Failure      500      {object}  utils.StandardResponse
+// @Router       /auth/reset-password/confirm [post]
+func (h *AuthHandler) ConfirmResetPassword(c *gin.Context) {
+	// Struct untuk data konfirmasi reset password
+	var confirmData struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	// Binding JSON
+	if err := c.ShouldBindJSON(&confirmData); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Gagal membaca data: "+err.Error(), nil)
+		return
+	}
+
+	// Validasi manual
+	if confirmData.Token == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Token tidak boleh kosong", nil)
+		return
+	}
+
+	if len(confirmData.Password) < 8 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Password minimal 8 karakter", nil)
+		return
+	}
+
+	// Ganti password
+	if err := h.authService.ResetPassword(c.Request.Context(), confirmData.Token, confirmData.Password); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password berhasil direset, silakan login kembali", nil)
+}
+
+// VerifyEmail memverifikasi email pengguna menggunakan token dari tautan verifikasi
+// @Summary      Verify email
+// @Description  Menandai email pengguna sebagai terverifikasi menggunakan token dari email verifikasi
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.VerifyEmailRequestSwagger  true  "Token verifikasi"
+// @Success      200      {object}  utils.StandardResponse
+// @Failure      400      {object}  utils.StandardResponse
+// @Router       /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	// Struct untuk data verifikasi email
+	var verifyData struct {
+		Token string `json:"token"`
+	}
+
+	// Binding JSON
+	if err := c.ShouldBindJSON(&verifyData); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Gagal membaca data: "+err.Error(), nil)
+		return
+	}
+
+	// Validasi manual
+	if verifyData.Token == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Token tidak boleh kosong", nil)
+		return
+	}
+
+	// Verifikasi email
+	if err := h.authService.VerifyEmail(c.Request.Context(), verifyData.Token); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email berhasil diverifikasi", nil)
+}
+
+// ResendVerificationEmail mengirim ulang email verifikasi
+// @Summary      Resend verification email
+// @Description  Mengirim ulang tautan verifikasi ke email pengguna yang sedang login
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Router       /auth/verify-email/resend [post]
+func (h *AuthHandler) ResendVerificationEmail(c *gin.Context) {
+	// Dapatkan user ID dari context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	// Kirim ulang email verifikasi
+	if err := h.authService.ResendVerificationEmail(c.Request.Context(), userID.(uint)); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email verifikasi telah dikirim", nil)
+}
+
+// EnrollMFA memulai pendaftaran verifikasi dua langkah
+// @Summary      Enroll MFA
+// @Description  Membuat secret TOTP baru dan URI otpauth:// untuk dipindai aplikasi authenticator
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.MFAEnrollResponseSwagger}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Router       /auth/mfa/enroll [post]
+func (h *AuthHandler) EnrollMFA(c *gin.Context) {
+	// Dapatkan user ID dari context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	enrollment, err := h.authService.EnrollMFA(c.Request.Context(), userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pindai kode QR lalu konfirmasi dengan kode dari aplikasi authenticator", enrollment)
+}
+
+// ConfirmMFA mengaktifkan verifikasi dua langkah
+// @Summary      Confirm MFA enrollment
+// @Description  Mengaktifkan verifikasi dua langkah dengan kode TOTP pertama dan mengembalikan kode pemulihan
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      domain.MFACodeRequestSwagger  true  "Kode TOTP"
+// @Success      200      {object}  utils.StandardResponse{data=domain.RecoveryCodesResponseSwagger}
+// @Failure      400      {object}  utils.StandardResponse
+// @Failure      401      {object}  utils.StandardResponse
+// @Router       /auth/mfa/confirm [post]
+func (h *AuthHandler) ConfirmMFA(c *gin.Context) {
+	// Dapatkan user ID dari context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var codeData struct {
+		Code string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&codeData); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Gagal membaca data: "+err.Error(), nil)
+		return
+	}
+
+	if codeData.Code == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Kode verifikasi tidak boleh kosong", nil)
+		return
+	}
+
+	codes, err := h.authService.ConfirmMFA(c.Request.Context(), userID.(uint), codeData.Code)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Verifikasi dua langkah berhasil diaktifkan, simpan kode pemulihan di tempat yang aman", gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+// DisableMFA menonaktifkan verifikasi dua langkah
+// @Summary      Disable MFA
+// @Description  Menonaktifkan verifikasi dua langkah. Admin tidak bisa menonaktifkan verifikasi dua langkah.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      domain.DisableMFARequestSwagger  true  "Password dan kode verifikasi"
+// @Success      200      {object}  utils.StandardResponse
+// @Failure      400      {object}  utils.StandardResponse
+// @Failure      401      {object}  utils.StandardResponse
+// @Router       /auth/mfa/disable [post]
+func (h *AuthHandler) DisableMFA(c *gin.Context) {
+	// Dapatkan user ID dari context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var disableData struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&disableData); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Gagal membaca data: "+err.Error(), nil)
+		return
+	}
+
+	if disableData.Password == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Password tidak boleh kosong", nil)
+		return
+	}
+
+	if disableData.Code == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Kode verifikasi tidak boleh kosong", nil)
+		return
+	}
+
+	if err := h.authService.DisableMFA(c.Request.Context(), userID.(uint), disableData.Password, disableData.Code); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Verifikasi dua langkah berhasil dinonaktifkan", nil)
+}
+
+// RegenerateRecoveryCodes membuat ulang kode pemulihan
+// @Summary      Regenerate recovery codes
+// @Description  Mengganti semua kode pemulihan dengan kode baru. Kode lama tidak berlaku lagi.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      domain.MFACodeRequestSwagger  true  "Kode TOTP"
+// @Success      200      {object}  utils.StandardResponse{data=domain.RecoveryCodesResponseSwagger}
+// @Failure      400      {object}  utils.StandardResponse
+// @Failure      401      {object}  utils.StandardResponse
+// @Router       /auth/mfa/recovery-codes [post]
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	// Dapatkan user ID dari context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var codeData struct {
+		Code string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&codeData); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Gagal membaca data: "+err.Error(), nil)
+		return
+	}
+
+	if codeData.Code == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Kode verifikasi tidak boleh kosong", nil)
+		return
+	}
+
+	codes, err := h.authService.RegenerateRecoveryCodes(c.Request.Context(), userID.(uint), codeData.Code)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Kode pemulihan berhasil dibuat ulang", gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+// clientInfo mengambil informasi perangkat dari request
+func clientInfo(c *gin.Context) domain.ClientInfo {
+	return domain.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
 }
 
 // RegisterRoutes mendaftarkan route untuk AuthHandler
 // @Summary      Register auth routes
 // @Description  Mendaftarkan semua route untuk autentikasi
-func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup) {
+func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
 	auth := router.Group("/auth")
 	{
 		auth.POST("/register", h.RegisterUser)
 		auth.POST("/login", h.LoginUser)
+		auth.POST("/login/mfa", h.LoginMFA)
+		auth.POST("/refresh", h.RefreshToken)
+		auth.POST("/logout", authMiddleware, h.Logout)
+		auth.POST("/reset-password", h.ResetPassword)
+		auth.POST("/reset-password/confirm", h.ConfirmResetPassword)
+		auth.POST("/verify-email", h.VerifyEmail)
+		auth.POST("/verify-email/resend", authMiddleware, h.ResendVerificationEmail)
+		auth.POST("/mfa/enroll", authMiddleware, h.EnrollMFA)
+		auth.POST("/mfa/confirm", authMiddleware, h.ConfirmMFA)
+		auth.POST("/mfa/disable", authMiddleware, h.DisableMFA)
+		auth.POST("/mfa/recovery-codes", authMiddleware, h.RegenerateRecoveryCodes)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/jubel/internal/service"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+// BlockHandler menangani endpoint pemblokiran pengguna
+type BlockHandler struct {
+	blockService service.BlockService
+}
+
+// NewBlockHandler membuat instance baru BlockHandler
+func NewBlockHandler(blockService service.BlockService) *BlockHandler {
+	return &BlockHandler{
+		blockService: blockService,
+	}
+}
+
+// BlockUser memblokir pengguna
+// @Summary      Block user
+// @Description  Memblokir pengguna. Pengguna yang diblokir tidak bisa mengirim pesan atau membeli barang Anda, dan barangnya tidak muncul di daftar barang Anda. Pengguna yang diblokir tidak diberi tahu.
+// @Tags         users
+// @Produce      json
+// @Param        id  path  int  true  "User ID"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.BlockResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /users/{id}/block [post]
+func (h *BlockHandler) BlockUser(c *gin.Context) {
+	blockedID, ok := parseIDParam(c, "ID pengguna tidak valid")
+	if !ok {
+		return
+	}
+
+	block, err := h.blockService.Block(c.Request.Context(), c.GetUint("userID"), blockedID)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal memblokir pengguna")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pengguna berhasil diblokir", block)
+}
+
+// UnblockUser membuka blokir pengguna
+// @Summary      Unblock user
+// @Description  Membuka blokir pengguna
+// @Tags         users
+// @Produce      json
+// @Param        id  path  int  true  "User ID"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /users/{id}/block [delete]
+func (h *BlockHandler) UnblockUser(c *gin.Context) {
+	blockedID, ok := parseIDParam(c, "ID pengguna tidak valid")
+	if !ok {
+		return
+	}
+
+	if err := h.blockService.Unblock(c.Request.Context(), c.GetUint("userID"), blockedID); err != nil {
+		abortWithServiceError(c, err, "Gagal membuka blokir pengguna")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Blokir pengguna berhasil dibuka", nil)
+}
+
+// GetBlockedUsers mendapatkan daftar pengguna yang diblokir
+// @Summary      Get blocked users
+// @Description  Mendapatkan daftar pengguna yang diblokir oleh pengguna yang sedang login
+// @Tags         users
+// @Produce      json
+// @Param        page   query  int  false  "Page number (default: 1)"
+// @Param        limit  query  int  false  "Items per page (default: 10)"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.PaginatedResponse{data=[]domain.BlockResponse}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /users/me/blocks [get]
+func (h *BlockHandler) GetBlockedUsers(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	blocks, totalPages, totalItems, err := h.blockService.GetBlocked(c.Request.Context(), c.GetUint("userID"), page, limit)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil daftar blokir")
+		return
+	}
+
+	utils.SuccessPaginatedResponse(c, http.StatusOK, "Daftar pengguna yang diblokir berhasil diambil", blocks, utils.Meta{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	})
+}
+
+// RegisterRoutes mendaftarkan route untuk BlockHandler
+func (h *BlockHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	users := router.Group("/users")
+	users.Use(authMiddleware)
+	{
+		users.GET("/me/blocks", h.GetBlockedUsers)
+		users.POST("/:id/block", h.BlockUser)
+		users.DELETE("/:id/block", h.UnblockUser)
+	}
+}
@@ -376,16 +376,15 @@ func (h *ChatHandler) DeleteChat(c *gin.Context) {
 }
 
 // RegisterRoutes mendaftarkan route untuk ChatHandler
-func (h *ChatHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+func (h *ChatHandler) RegisterRoutes(router *gin.RouterGroup, scopedAuth func(domain.Scope) gin.HandlerFunc, verifiedMiddleware gin.HandlerFunc) {
 	chats := router.Group("/chats")
-	chats.Use(authMiddleware)
 	{
-		chats.POST("", h.SendMessage)
-		chats.GET("/:id", h.GetChat)
-		chats.GET("/barang/:id", h.GetChatsByBarang)
-		chats.GET("/conversation", h.GetConversation)
-		chats.GET("/partners", h.GetChatPartners)
-		chats.PATCH("/:id/read", h.MarkAsRead)
-		chats.DELETE("/:id", h.DeleteChat)
+		chats.POST("", scopedAuth(domain.ScopeChatsWrite), verifiedMiddleware, h.SendMessage)
+		chats.GET("/:id", scopedAuth(domain.ScopeChatsRead), h.GetChat)
+		chats.GET("/barang/:id", scopedAuth(domain.ScopeChatsRead), h.GetChatsByBarang)
+		chats.GET("/conversation", scopedAuth(domain.ScopeChatsRead), h.GetConversation)
+		chats.GET("/partners", scopedAuth(domain.ScopeChatsRead), h.GetChatPartners)
+		chats.PATCH("/:id/read", scopedAuth(domain.ScopeChatsWrite), h.MarkAsRead)
+		chats.DELETE("/:id", scopedAuth(domain.ScopeChatsWrite), h.DeleteChat)
 	}
 }
\ No newline at end of file
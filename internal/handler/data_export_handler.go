@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/service"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+// DataExportHandler menangani endpoint ekspor data pribadi pengguna
+type DataExportHandler struct {
+	dataExportService service.DataExportService
+}
+
+// NewDataExportHandler membuat instance baru DataExportHandler
+func NewDataExportHandler(dataExportService service.DataExportService) *DataExportHandler {
+	return &DataExportHandler{
+		dataExportService: dataExportService,
+	}
+}
+
+// RequestExport meminta pembuatan arsip data pribadi
+// @Summary      Request data export
+// @Description  Meminta pembuatan arsip ZIP berisi profil, barang (termasuk yang sudah dihapus), transaksi, chat dan referensi gambar. Arsip dibuat di latar belakang; cek statusnya melalui GET /users/me/export.
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Success      202  {object}  utils.StandardResponse{data=domain.DataExportResponse}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /users/me/export [post]
+func (h *DataExportHandler) RequestExport(c *gin.Context) {
+	export, err := h.dataExportService.Request(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal meminta ekspor data")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusAccepted, "Permintaan ekspor data sedang diproses", export)
+}
+
+// GetExport mendapatkan status ekspor data terbaru
+// @Summary      Get data export status
+// @Description  Mendapatkan status ekspor data terbaru. Jika arsip sudah siap, respons berisi tautan unduhan yang berlaku sementara.
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.DataExportResponse}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /users/me/export [get]
+func (h *DataExportHandler) GetExport(c *gin.Context) {
+	export, err := h.dataExportService.GetLatest(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil status ekspor data")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Status ekspor data berhasil diambil", export)
+}
+
+// DownloadExport mengunduh arsip ekspor data
+// @Summary      Download data export
+// @Description  Mengunduh arsip ZIP ekspor data menggunakan tautan bertanda tangan dari GET /users/me/export. Tidak membutuhkan header Authorization.
+// @Tags         users
+// @Produce      application/zip
+// @Param        token  query  string  true  "Token tautan unduhan"
+// @Success      200  {file}    file
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /users/me/export/download [get]
+func (h *DataExportHandler) DownloadExport(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		_ = c.Error(errors.ValidationError("Token unduhan wajib diisi", nil))
+		return
+	}
+
+	filePath, fileName, err := h.dataExportService.OpenDownload(c.Request.Context(), token)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengunduh ekspor data")
+		return
+	}
+
+	c.FileAttachment(filePath, fileName)
+}
+
+// RegisterRoutes mendaftarkan route untuk DataExportHandler
+func (h *DataExportHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	export := router.Group("/users/me/export")
+	{
+		export.POST("", authMiddleware, h.RequestExport)
+		export.GET("", authMiddleware, h.GetExport)
+		// Tautan unduhan sudah ditandatangani sehingga bisa dibuka langsung dari browser
+		export.GET("/download", h.DownloadExport)
+	}
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/service"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+// FollowHandler menangani endpoint mengikuti penjual dan feed barang
+type FollowHandler struct {
+	followService service.FollowService
+}
+
+// NewFollowHandler membuat instance baru FollowHandler
+func NewFollowHandler(followService service.FollowService) *FollowHandler {
+	return &FollowHandler{
+		followService: followService,
+	}
+}
+
+// FollowUser mengikuti penjual
+// @Summary      Follow user
+// @Description  Mengikuti penjual. Barang baru dari penjual yang diikuti muncul di feed dan dikirim sebagai notifikasi.
+// @Tags         users
+// @Produce      json
+// @Param        id  path  int  true  "User ID"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.FollowResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /users/{id}/follow [post]
+func (h *FollowHandler) FollowUser(c *gin.Context) {
+	followedID, ok := parseIDParam(c, "ID pengguna tidak valid")
+	if !ok {
+		return
+	}
+
+	follow, err := h.followService.Follow(c.Request.Context(), c.GetUint("userID"), followedID)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengikuti pengguna")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pengguna berhasil diikuti", follow)
+}
+
+// UnfollowUser berhenti mengikuti penjual
+// @Summary      Unfollow user
+// @Description  Berhenti mengikuti penjual
+// @Tags         users
+// @Produce      json
+// @Param        id  path  int  true  "User ID"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /users/{id}/follow [delete]
+func (h *FollowHandler) UnfollowUser(c *gin.Context) {
+	followedID, ok := parseIDParam(c, "ID pengguna tidak valid")
+	if !ok {
+		return
+	}
+
+	if err := h.followService.Unfollow(c.Request.Context(), c.GetUint("userID"), followedID); err != nil {
+		abortWithServiceError(c, err, "Gagal berhenti mengikuti pengguna")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Berhasil berhenti mengikuti pengguna", nil)
+}
+
+// GetFollowers mendapatkan daftar pengikut
+// @Summary      Get my followers
+// @Description  Mendapatkan daftar pengguna yang mengikuti pengguna yang sedang login, terbaru lebih dulu
+// @Tags         users
+// @Produce      json
+// @Param        page   query  int  false  "Page number (default: 1)"
+// @Param        limit  query  int  false  "Items per page (default: 10)"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.PaginatedResponse{data=[]domain.FollowResponse}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /users/me/followers [get]
+func (h *FollowHandler) GetFollowers(c *gin.Context) {
+	page, limit := paginationParams(c)
+
+	followers, totalPages, totalItems, err := h.followService.GetFollowers(c.Request.Context(), c.GetUint("userID"), page, limit)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil daftar pengikut")
+		return
+	}
+
+	utils.SuccessPaginatedResponse(c, http.StatusOK, "Daftar pengikut berhasil diambil", followers, utils.Meta{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	})
+}
+
+// GetFollowing mendapatkan daftar penjual yang diikuti
+// @Summary      Get followed users
+// @Description  Mendapatkan daftar penjual yang diikuti pengguna yang sedang login, terbaru lebih dulu
+// @Tags         users
+// @Produce      json
+// @Param        page   query  int  false  "Page number (default: 1)"
+// @Param        limit  query  int  false  "Items per page (default: 10)"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.PaginatedResponse{data=[]domain.FollowResponse}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /users/me/following [get]
+func (h *FollowHandler) GetFollowing(c *gin.Context) {
+	page, limit := paginationParams(c)
+
+	following, totalPages, totalItems, err := h.followService.GetFollowing(c.Request.Context(), c.GetUint("userID"), page, limit)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil daftar pengguna yang diikuti")
+		return
+	}
+
+	utils.SuccessPaginatedResponse(c, http.StatusOK, "Daftar pengguna yang diikuti berhasil diambil", following, utils.Meta{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	})
+}
+
+// GetFeed mendapatkan feed barang dari penjual yang diikuti
+// @Summary      Get feed
+// @Description  Mendapatkan barang Tersedia terbaru dari penjual yang diikuti. Kirim meta.next_cursor sebagai parameter cursor untuk halaman berikutnya.
+// @Tags         items
+// @Produce      json
+// @Param        cursor  query  string  false  "Cursor dari meta.next_cursor halaman sebelumnya"
+// @Param        limit   query  int     false  "Items per page (default: 10, maksimal: 50)"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.CursorResponse{data=[]domain.ItemResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /feed [get]
+func (h *FollowHandler) GetFeed(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	items, nextCursor, err := h.followService.GetFeed(c.Request.Context(), c.GetUint("userID"), c.Query("cursor"), limit)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil feed")
+		return
+	}
+
+	utils.SuccessCursorResponse(c, http.StatusOK, "Feed berhasil diambil", items, utils.CursorMeta{
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	})
+}
+
+// RegisterRoutes mendaftarkan route untuk FollowHandler
+func (h *FollowHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, scopedAuth func(domain.Scope) gin.HandlerFunc) {
+	users := router.Group("/users")
+	users.Use(authMiddleware)
+	{
+		users.GET("/me/followers", h.GetFollowers)
+		users.GET("/me/following", h.GetFollowing)
+		users.POST("/:id/follow", h.FollowUser)
+		users.DELETE("/:id/follow", h.UnfollowUser)
+	}
+
+	router.GET("/feed", scopedAuth(domain.ScopeItemsRead), h.GetFeed)
+}
@@ -1,13 +1,11 @@
 package handler
 
 import (
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/mfuadfakhruzzaki/jubel/internal/config"
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
 	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
 	"github.com/mfuadfakhruzzaki/jubel/internal/service"
@@ -16,13 +14,15 @@ import (
 
 // ItemHandler menangani endpoint terkait barang
 type ItemHandler struct {
-	itemService service.ItemService
+	itemService   service.ItemService
+	searchService service.SearchService
 }
 
 // NewItemHandler membuat instance baru ItemHandler
-func NewItemHandler(itemService service.ItemService) *ItemHandler {
+func NewItemHandler(itemService service.ItemService, searchService service.SearchService) *ItemHandler {
 	return &ItemHandler{
-		itemService: itemService,
+		itemService:   itemService,
+		searchService: searchService,
 	}
 }
 
@@ -136,11 +136,6 @@ func (h *ItemHandler) CreateItem(c *gin.Context) {
 		parts := strings.Split(fileInfo, "|")
 		if len(parts) > 2 {
 			newItem.Gambar = parts[2] // URL gambar
-			
-			// Ensure the URL uses the view endpoint
-			if strings.Contains(newItem.Gambar, "/download") && !strings.Contains(newItem.Gambar, "/view") {
-				newItem.Gambar = strings.Replace(newItem.Gambar, "/download", "/view", 1)
-			}
 		}
 	}
 
@@ -185,7 +180,7 @@ func (h *ItemHandler) GetItem(c *gin.Context) {
 
 // GetAllItems mendapatkan daftar barang
 // @Summary      List all items
-// @Description  Mendapatkan daftar barang dengan paginasi dan filter
+// @Description  Mendapatkan daftar barang dengan paginasi dan filter. search mencocokkan awalan kata pada nama, deskripsi dan kategori; jika tidak ada yang cocok, nama barang yang mirip (misalnya salah ketik) ditampilkan. Hasil pencarian dilengkapi sorotan.
 // @Tags         items
 // @Accept       json
 // @Produce      json
@@ -194,7 +189,9 @@ func (h *ItemHandler) GetItem(c *gin.Context) {
 // @Param        search   query     string  false  "Search query"
 // @Param        kategori query     string  false  "Filter by category"
 // @Param        status   query     string  false  "Filter by status"
+// @Param        sort     query     string  false  "Urutan: newest (default) atau relevance (hanya berlaku dengan search)"
 // @Success      200      {object}  utils.PaginatedResponse{data=[]domain.ItemResponse}
+// @Failure      400      {object}  utils.StandardResponse
 // @Failure      500      {object}  utils.StandardResponse
 // @Router       /items [get]
 func (h *ItemHandler) GetAllItems(c *gin.Context) {
@@ -204,6 +201,7 @@ func (h *ItemHandler) GetAllItems(c *gin.Context) {
 	search := c.DefaultQuery("search", "")
 	kategori := c.DefaultQuery("kategori", "")
 	status := c.DefaultQuery("status", "")
+	sort := c.DefaultQuery("sort", "")
 
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
@@ -216,12 +214,24 @@ func (h *ItemHandler) GetAllItems(c *gin.Context) {
 	}
 
 	// Dapatkan daftar barang
-	items, totalPages, totalItems, err := h.itemService.GetAll(c.Request.Context(), page, limit, search, kategori, status)
+	filter := domain.ItemFilter{
+		Search:   search,
+		Kategori: kategori,
+		Status:   status,
+		Sort:     domain.ItemSort(sort),
+	}
+	items, totalPages, totalItems, match, err := h.itemService.GetAll(c.Request.Context(), filter, page, limit)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
+		abortWithServiceError(c, err, "Gagal mengambil daftar barang")
 		return
 	}
 
+	// Catat pencarian untuk saran pencarian di latar belakang. Hanya halaman pertama yang dicatat
+	// agar membuka halaman berikutnya tidak dihitung sebagai pencarian baru.
+	if search != "" && page == 1 {
+		h.searchService.RecordSearch(c.Request.Context(), search, totalItems, match, clientInfo(c))
+	}
+
 	// Buat response paginasi
 	utils.SuccessPaginatedResponse(c, http.StatusOK, "Daftar barang berhasil diambil", items, utils.Meta{
 		Page:       page,
@@ -231,6 +241,25 @@ func (h *ItemHandler) GetAllItems(c *gin.Context) {
 	})
 }
 
+// SuggestItems memberikan saran pencarian saat pengguna mengetik
+// @Summary      Search suggestions
+// @Description  Memberikan saran untuk kotak pencarian: kueri populer yang diawali q, barang yang namanya cocok dan kategori yang cocok. Teks kurang dari 2 karakter atau yang mengandung kata terlarang menghasilkan saran kosong.
+// @Tags         items
+// @Produce      json
+// @Param        q    query     string  true  "Teks yang sedang diketik"
+// @Success      200  {object}  utils.StandardResponse{data=domain.SearchSuggestionResponse}
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /items/suggest [get]
+func (h *ItemHandler) SuggestItems(c *gin.Context) {
+	suggestions, err := h.searchService.Suggest(c.Request.Context(), c.Query("q"))
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil saran pencarian")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Saran pencarian berhasil diambil", suggestions)
+}
+
 // GetItemsByPenjual mendapatkan daftar barang berdasarkan penjual
 // @Summary      Get items by seller
 // @Description  Mendapatkan daftar barang berdasarkan ID penjual
@@ -489,39 +518,10 @@ func (h *ItemHandler) DeleteItem(c *gin.Context) {
 		return
 	}
 
-	// Cek role
-	role, exists := c.Get("userRole")
-	if !exists {
-		// Use custom error types
-		err = errors.UnauthorizedError("User tidak diautentikasi", nil)
-		_ = c.Error(err)
-		return
-	}
-
-	// Jika admin, bisa hapus langsung
-	if role.(domain.Role) == domain.RoleAdmin {
-		if err := h.itemService.Delete(c.Request.Context(), uint(id), userID.(uint)); err != nil {
-			// Use custom error types
-			if strings.Contains(err.Error(), "not found") {
-				err = errors.NotFoundError("Barang tidak ditemukan", err).WithMetadata("itemID", id)
-			} else {
-				err = errors.InternalError("Gagal menghapus barang", err).WithMetadata("itemID", id)
-			}
-			_ = c.Error(err)
-			return
-		}
-		utils.SuccessResponse(c, http.StatusOK, "Barang berhasil dihapus", nil)
-		return
-	}
-
-	// Jika bukan admin, cek kepemilikan
+	// Pemilik, admin dan moderator boleh menghapus; izin diperiksa oleh policy di service
 	if err := h.itemService.Delete(c.Request.Context(), uint(id), userID.(uint)); err != nil {
-		// Use custom error types
-		if strings.Contains(err.Error(), "not found") {
-			err = errors.NotFoundError("Barang tidak ditemukan", err).WithMetadata("itemID", id)
-		} else if strings.Contains(err.Error(), "unauthorized") || strings.Contains(err.Error(), "not the owner") {
-			err = errors.ForbiddenError("Anda tidak memiliki akses untuk menghapus barang ini", err).WithMetadata("itemID", id)
-		} else {
+		// Service sudah mengembalikan StandardError (not found, forbidden, internal)
+		if _, ok := errors.AsStandardError(err); !ok {
 			err = errors.InternalError("Gagal menghapus barang", err).WithMetadata("itemID", id)
 		}
 		_ = c.Error(err)
@@ -533,7 +533,7 @@ func (h *ItemHandler) DeleteItem(c *gin.Context) {
 
 // UploadItemImage mengupload gambar barang
 // @Summary      Upload item image
-// @Description  Mengupload gambar untuk barang
+// @Description  Mengupload satu gambar ke galeri barang dan menjadikannya sampul (gambar). Gunakan POST /items/{id}/images untuk mengupload beberapa gambar sekaligus.
 // @Tags         items
 // @Accept       multipart/form-data
 // @Produce      json
@@ -566,7 +566,7 @@ func (h *ItemHandler) UploadItemImage(c *gin.Context) {
 	// Upload gambar
 	fileInfo, err := h.itemService.UploadImage(c, uint(id), userID.(uint))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
+		abortWithServiceError(c, err, "Gagal mengupload gambar")
 		return
 	}
 
@@ -582,30 +582,6 @@ func (h *ItemHandler) UploadItemImage(c *gin.Context) {
 	
 	if len(parts) > 2 {
 		viewURL = parts[2]
-	} else {
-		// Get Appwrite config for creating URLs if not included in response
-		appwriteConfig, err := config.LoadConfig()
-		if err != nil {
-			utils.ErrorResponse(c, http.StatusInternalServerError, "Gagal memuat konfigurasi", nil)
-			return
-		}
-
-		// Create file view URL
-		appwriteEndpoint := appwriteConfig.Appwrite.Endpoint
-		projectID := appwriteConfig.Appwrite.ProjectID
-		bucketID := appwriteConfig.Appwrite.BucketID
-		
-		// Debug logs for troubleshooting
-		fmt.Printf("Appwrite View URL: %s\n", appwriteEndpoint)
-		fmt.Printf("Project ID: %s\n", projectID)
-		fmt.Printf("Bucket ID: %s\n", bucketID)
-		fmt.Printf("File ID: %s\n", fileID)
-		
-		viewURL = fmt.Sprintf("%s/storage/buckets/%s/files/%s/view?project=%s", 
-			appwriteEndpoint,
-			bucketID,
-			fileID,
-			projectID)
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Gambar berhasil diupload", gin.H{
@@ -615,24 +591,188 @@ func (h *ItemHandler) UploadItemImage(c *gin.Context) {
 	})
 }
 
-// RegisterRoutes mendaftarkan route untuk ItemHandler
-func (h *ItemHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
+// UploadItemImages mengupload beberapa gambar ke galeri barang
+// @Summary      Upload item images
+// @Description  Mengupload satu atau beberapa gambar sekaligus ke galeri barang. Gambar ditambahkan setelah gambar terakhir; gambar pertama di galeri kosong menjadi sampul. Tipe file ditentukan dari isi file; gambar di-encode ulang tanpa metadata EXIF dan dibuatkan varian thumb, medium dan full.
+// @Tags         items
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id          path      int     true   "Item ID"
+// @Param        gambar      formData  file    true   "Image files (boleh diulang)"
+// @Param        keterangan  formData  string  false  "Keterangan untuk gambar pada posisi yang sama (boleh diulang)"
+// @Security     BearerAuth
+// @Success      201  {object}  utils.StandardResponse{data=[]domain.ItemImageResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /items/{id}/images [post]
+func (h *ItemHandler) UploadItemImages(c *gin.Context) {
+	itemID, ok := parseIDParam(c, "ID barang tidak valid")
+	if !ok {
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		_ = c.Error(errors.ValidationError("Gagal membaca form gambar", err))
+		return
+	}
+
+	images, err := h.itemService.UploadImages(c.Request.Context(), itemID, c.GetUint("userID"), form.File["gambar"], form.Value["keterangan"])
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengupload gambar")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Gambar berhasil diupload", images)
+}
+
+// ReorderItemImages mengubah urutan galeri barang
+// @Summary      Reorder item images
+// @Description  Mengubah urutan galeri barang. image_ids harus berisi semua ID gambar barang tepat satu kali dalam urutan baru.
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int                              true  "Item ID"
+// @Param        request  body  domain.ReorderItemImagesRequest  true  "Urutan gambar"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=[]domain.ItemImageResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /items/{id}/images/order [put]
+func (h *ItemHandler) ReorderItemImages(c *gin.Context) {
+	itemID, ok := parseIDParam(c, "ID barang tidak valid")
+	if !ok {
+		return
+	}
+
+	var req domain.ReorderItemImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.ValidationError("Gagal membaca data", err))
+		return
+	}
+
+	images, err := h.itemService.ReorderImages(c.Request.Context(), itemID, c.GetUint("userID"), req.ImageIDs)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengubah urutan gambar")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Urutan gambar berhasil diubah", images)
+}
+
+// UpdateItemImage mengubah keterangan atau sampul gambar barang
+// @Summary      Update item image
+// @Description  Mengubah keterangan gambar atau menjadikannya sampul barang. Sampul lama otomatis dilepas dan gambar barang diperbarui.
+// @Tags         items
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int                            true  "Item ID"
+// @Param        imageId  path  int                            true  "Image ID"
+// @Param        request  body  domain.UpdateItemImageRequest  true  "Perubahan gambar"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=[]domain.ItemImageResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /items/{id}/images/{imageId} [patch]
+func (h *ItemHandler) UpdateItemImage(c *gin.Context) {
+	itemID, ok := parseIDParam(c, "ID barang tidak valid")
+	if !ok {
+		return
+	}
+	imageID, ok := parseImageIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateItemImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.ValidationError("Gagal membaca data", err))
+		return
+	}
+
+	images, err := h.itemService.UpdateImage(c.Request.Context(), itemID, imageID, c.GetUint("userID"), req)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengubah gambar")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Gambar berhasil diubah", images)
+}
+
+// DeleteItemImage menghapus gambar dari galeri barang
+// @Summary      Delete item image
+// @Description  Menghapus gambar dari galeri barang dan storage. Jika gambar adalah sampul, gambar pertama yang tersisa menjadi sampul.
+// @Tags         items
+// @Produce      json
+// @Param        id       path  int  true  "Item ID"
+// @Param        imageId  path  int  true  "Image ID"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=[]domain.ItemImageResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /items/{id}/images/{imageId} [delete]
+func (h *ItemHandler) DeleteItemImage(c *gin.Context) {
+	itemID, ok := parseIDParam(c, "ID barang tidak valid")
+	if !ok {
+		return
+	}
+	imageID, ok := parseImageIDParam(c)
+	if !ok {
+		return
+	}
+
+	images, err := h.itemService.DeleteImage(c.Request.Context(), itemID, imageID, c.GetUint("userID"))
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal menghapus gambar")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Gambar berhasil dihapus", images)
+}
+
+// parseImageIDParam membaca parameter imageId dari URL
+func parseImageIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		_ = c.Error(errors.ValidationError("ID gambar tidak valid", err))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// RegisterRoutes mendaftarkan route untuk ItemHandler.
+// scopedAuth memverifikasi token JWT atau personal access token dengan scope yang diminta,
+// sedangkan optionalAuth hanya memverifikasi token jika ada.
+func (h *ItemHandler) RegisterRoutes(router *gin.RouterGroup, scopedAuth func(domain.Scope) gin.HandlerFunc, optionalAuth gin.HandlerFunc, moderatorMiddleware gin.HandlerFunc, verifiedMiddleware gin.HandlerFunc) {
 	items := router.Group("/items")
 	{
-		items.GET("", h.GetAllItems)
-		items.GET("/:id", h.GetItem)
-		items.GET("/penjual/:id", h.GetItemsByPenjual)
-		items.GET("/my", authMiddleware, h.GetMyItems)
-		items.POST("", authMiddleware, h.CreateItem)
-		items.PATCH("/:id", authMiddleware, h.UpdateItem)
-		items.PATCH("/:id/status", authMiddleware, h.UpdateItemStatus)
-		items.DELETE("/:id", authMiddleware, h.DeleteItem)
-		items.POST("/:id/upload", authMiddleware, h.UploadItemImage)
-	}
-
-	// Admin routes
+		items.GET("", optionalAuth, h.GetAllItems)
+		items.GET("/suggest", h.SuggestItems)
+		items.GET("/:id", optionalAuth, h.GetItem)
+		items.GET("/penjual/:id", optionalAuth, h.GetItemsByPenjual)
+		items.GET("/my", scopedAuth(domain.ScopeItemsRead), h.GetMyItems)
+		items.POST("", scopedAuth(domain.ScopeItemsWrite), verifiedMiddleware, h.CreateItem)
+		items.PATCH("/:id", scopedAuth(domain.ScopeItemsWrite), h.UpdateItem)
+		items.PATCH("/:id/status", scopedAuth(domain.ScopeItemsWrite), h.UpdateItemStatus)
+		items.DELETE("/:id", scopedAuth(domain.ScopeItemsWrite), h.DeleteItem)
+		items.POST("/:id/upload", scopedAuth(domain.ScopeItemsWrite), h.UploadItemImage)
+		items.POST("/:id/images", scopedAuth(domain.ScopeItemsWrite), h.UploadItemImages)
+		items.PUT("/:id/images/order", scopedAuth(domain.ScopeItemsWrite), h.ReorderItemImages)
+		items.PATCH("/:id/images/:imageId", scopedAuth(domain.ScopeItemsWrite), h.UpdateItemImage)
+		items.DELETE("/:id/images/:imageId", scopedAuth(domain.ScopeItemsWrite), h.DeleteItemImage)
+	}
+
+	// Admin routes (admin dan moderator)
 	admin := router.Group("/admin")
 	{
-		admin.DELETE("/items/:id", authMiddleware, adminMiddleware, h.DeleteItem)
+		admin.DELETE("/items/:id", scopedAuth(domain.ScopeItemsWrite), moderatorMiddleware, h.DeleteItem)
 	}
 }
\ No newline at end of file
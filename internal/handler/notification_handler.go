@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/jubel/internal/service"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+// NotificationHandler menangani endpoint notifikasi dalam aplikasi
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+// NewNotificationHandler membuat instance baru NotificationHandler
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications mendapatkan notifikasi pengguna
+// @Summary      Get notifications
+// @Description  Mendapatkan notifikasi pengguna yang sedang login, terbaru lebih dulu
+// @Tags         notifications
+// @Produce      json
+// @Param        page    query  int   false  "Page number (default: 1)"
+// @Param        limit   query  int   false  "Items per page (default: 10)"
+// @Param        unread  query  bool  false  "Hanya notifikasi yang belum dibaca"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.PaginatedResponse{data=[]domain.Notification}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	page, limit := paginationParams(c)
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, totalPages, totalItems, err := h.notificationService.GetMine(c.Request.Context(), c.GetUint("userID"), unreadOnly, page, limit)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil notifikasi")
+		return
+	}
+
+	utils.SuccessPaginatedResponse(c, http.StatusOK, "Notifikasi berhasil diambil", notifications, utils.Meta{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	})
+}
+
+// GetUnreadCount menghitung notifikasi yang belum dibaca
+// @Summary      Get unread notification count
+// @Description  Menghitung notifikasi pengguna yang sedang login yang belum dibaca
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=map[string]int64}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	count, err := h.notificationService.CountUnread(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal menghitung notifikasi")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Jumlah notifikasi berhasil diambil", gin.H{"belum_dibaca": count})
+}
+
+// MarkAsRead menandai notifikasi sebagai sudah dibaca
+// @Summary      Mark notification as read
+// @Description  Menandai notifikasi sebagai sudah dibaca
+// @Tags         notifications
+// @Produce      json
+// @Param        id  path  int  true  "Notification ID"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /notifications/{id}/read [patch]
+func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
+	id, ok := parseIDParam(c, "ID notifikasi tidak valid")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkAsRead(c.Request.Context(), id, c.GetUint("userID")); err != nil {
+		abortWithServiceError(c, err, "Gagal menandai notifikasi")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notifikasi ditandai sudah dibaca", nil)
+}
+
+// MarkAllAsRead menandai semua notifikasi sebagai sudah dibaca
+// @Summary      Mark all notifications as read
+// @Description  Menandai semua notifikasi pengguna yang sedang login sebagai sudah dibaca
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=map[string]int64}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /notifications/read-all [patch]
+func (h *NotificationHandler) MarkAllAsRead(c *gin.Context) {
+	count, err := h.notificationService.MarkAllAsRead(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal menandai notifikasi")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Semua notifikasi ditandai sudah dibaca", gin.H{"ditandai": count})
+}
+
+// RegisterRoutes mendaftarkan route untuk NotificationHandler
+func (h *NotificationHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	notifications := router.Group("/notifications")
+	notifications.Use(authMiddleware)
+	{
+		notifications.GET("", h.GetNotifications)
+		notifications.GET("/unread-count", h.GetUnreadCount)
+		notifications.PATCH("/read-all", h.MarkAllAsRead)
+		notifications.PATCH("/:id/read", h.MarkAsRead)
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/jubel/internal/oidc"
+	"github.com/mfuadfakhruzzaki/jubel/internal/service"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+// OIDCHandler menangani endpoint login dengan penyedia identitas OpenID Connect
+type OIDCHandler struct {
+	oidcService service.OIDCService
+}
+
+// NewOIDCHandler membuat instance baru OIDCHandler
+func NewOIDCHandler(oidcService service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+	}
+}
+
+// GetProviders mendapatkan daftar penyedia identitas yang bisa dipakai untuk login
+// @Summary      List OIDC providers
+// @Description  Mendapatkan daftar penyedia identitas (misalnya SSO kampus) yang bisa dipakai untuk login
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  utils.StandardResponse{data=[]string}
+// @Router       /auth/oidc [get]
+func (h *OIDCHandler) GetProviders(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Data penyedia login berhasil diambil", h.oidcService.Providers())
+}
+
+// Authorize mengarahkan pengguna ke halaman login penyedia identitas
+// @Summary      Start OIDC login
+// @Description  Mengarahkan (302) ke halaman login penyedia identitas menggunakan authorization code + PKCE
+// @Tags         auth
+// @Param        provider  path  string  true  "Nama penyedia"
+// @Success      302
+// @Failure      404  {object}  utils.StandardResponse
+// @Failure      502  {object}  utils.StandardResponse
+// @Router       /auth/oidc/{provider} [get]
+func (h *OIDCHandler) Authorize(c *gin.Context) {
+	authURL, err := h.oidcService.AuthorizationURL(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		if errors.Is(err, oidc.ErrUnknownProvider) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusBadGateway, "Penyedia identitas tidak dapat dihubungi", nil)
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback menyelesaikan login setelah pengguna kembali dari penyedia identitas
+// @Summary      OIDC login callback
+// @Description  Menukar authorization code dari penyedia identitas dengan token JWT. Akun dihubungkan berdasarkan email yang sudah diverifikasi penyedia, atau dibuat baru jika belum ada.
+// @Tags         auth
+// @Produce      json
+// @Param        provider  path   string  true  "Nama penyedia"
+// @Param        code      query  string  true  "Authorization code"
+// @Param        state     query  string  true  "State dari langkah pertama"
+// @Success      200  {object}  utils.StandardResponse{data=domain.LoginResponseSwagger}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	// Penyedia mengirim parameter error jika pengguna menolak atau login gagal
+	if errorCode := c.Query("error"); errorCode != "" {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Login dibatalkan oleh penyedia identitas: "+errorCode, nil)
+		return
+	}
+
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || state == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Code dan state tidak boleh kosong", nil)
+		return
+	}
+
+	result, err := h.oidcService.Callback(c.Request.Context(), c.Param("provider"), code, state, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, oidc.ErrUnknownProvider) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	loginResponse(c, result)
+}
+
+// RegisterRoutes mendaftarkan route untuk OIDCHandler
+func (h *OIDCHandler) RegisterRoutes(router *gin.RouterGroup) {
+	oidcRoutes := router.Group("/auth/oidc")
+	{
+		oidcRoutes.GET("", h.GetProviders)
+		oidcRoutes.GET("/:provider", h.Authorize)
+		oidcRoutes.GET("/:provider/callback", h.Callback)
+	}
+}
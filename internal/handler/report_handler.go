@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
+	"github.com/mfuadfakhruzzaki/jubel/internal/service"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+// ReportHandler menangani endpoint laporan pengguna dan antrean moderasinya
+type ReportHandler struct {
+	reportService service.ReportService
+}
+
+// NewReportHandler membuat instance baru ReportHandler
+func NewReportHandler(reportService service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// CreateReport membuat laporan
+// @Summary      Create report
+// @Description  Melaporkan barang, pesan chat yang diterima, atau pengguna lain. Jika jumlah pelapor berbeda mencapai batas, target disembunyikan sampai ditinjau moderator.
+// @Tags         reports
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.CreateReportRequest  true  "Data laporan"
+// @Security     BearerAuth
+// @Success      201  {object}  utils.StandardResponse{data=domain.ReportResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Failure      409  {object}  utils.StandardResponse
+// @Router       /reports [post]
+func (h *ReportHandler) CreateReport(c *gin.Context) {
+	var req domain.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.ValidationError("Gagal membaca data", err))
+		return
+	}
+
+	report, err := h.reportService.Create(c.Request.Context(), c.GetUint("userID"), req)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal membuat laporan")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Laporan berhasil dikirim", report)
+}
+
+// GetMyReports mendapatkan laporan yang dibuat pengguna
+// @Summary      Get my reports
+// @Description  Mendapatkan laporan yang dibuat pengguna yang sedang login beserta statusnya
+// @Tags         reports
+// @Produce      json
+// @Param        page   query  int  false  "Page number (default: 1)"
+// @Param        limit  query  int  false  "Items per page (default: 10)"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.PaginatedResponse{data=[]domain.ReportResponse}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /reports/me [get]
+func (h *ReportHandler) GetMyReports(c *gin.Context) {
+	page, limit := paginationParams(c)
+
+	reports, totalPages, totalItems, err := h.reportService.GetMine(c.Request.Context(), c.GetUint("userID"), page, limit)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil laporan")
+		return
+	}
+
+	utils.SuccessPaginatedResponse(c, http.StatusOK, "Data laporan berhasil diambil", reports, utils.Meta{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	})
+}
+
+// GetReportQueue mendapatkan antrean laporan
+// @Summary      List reports
+// @Description  Mendapatkan antrean laporan, laporan terlama lebih dulu (admin dan moderator)
+// @Tags         admin
+// @Produce      json
+// @Param        page         query  int     false  "Page number (default: 1)"
+// @Param        limit        query  int     false  "Items per page (default: 10)"
+// @Param        status       query  string  false  "Filter status (open, reviewing, actioned, dismissed)"
+// @Param        target_type  query  string  false  "Filter jenis target (item, chat, user)"
+// @Param        assigned     query  string  false  "me untuk laporan yang ditugaskan ke diri sendiri, none untuk yang belum ditugaskan"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.PaginatedResponse{data=[]domain.AdminReportResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Router       /admin/reports [get]
+func (h *ReportHandler) GetReportQueue(c *gin.Context) {
+	page, limit := paginationParams(c)
+
+	filter := domain.ReportFilter{
+		Status:     domain.ReportStatus(c.Query("status")),
+		TargetType: domain.ReportTargetType(c.Query("target_type")),
+	}
+	switch c.Query("assigned") {
+	case "":
+	case "me":
+		filter.ModeratorID = c.GetUint("userID")
+	case "none":
+		filter.Unassigned = true
+	default:
+		_ = c.Error(errors.ValidationError("assigned harus me atau none", nil))
+		return
+	}
+
+	reports, totalPages, totalItems, err := h.reportService.GetQueue(c.Request.Context(), filter, page, limit)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil antrean laporan")
+		return
+	}
+
+	utils.SuccessPaginatedResponse(c, http.StatusOK, "Antrean laporan berhasil diambil", reports, utils.Meta{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	})
+}
+
+// GetReport mendapatkan detail laporan
+// @Summary      Get report
+// @Description  Mendapatkan detail laporan beserta cuplikan konten dan tindakan yang terkait (admin dan moderator)
+// @Tags         admin
+// @Produce      json
+// @Param        id  path  int  true  "Report ID"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.AdminReportResponse}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /admin/reports/{id} [get]
+func (h *ReportHandler) GetReport(c *gin.Context) {
+	reportID, ok := parseIDParam(c, "ID laporan tidak valid")
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GetByID(c.Request.Context(), reportID)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil laporan")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data laporan berhasil diambil", report)
+}
+
+// AssignReport menugaskan laporan ke moderator
+// @Summary      Assign report
+// @Description  Menugaskan laporan ke moderator atau admin dan mengubah statusnya menjadi reviewing. Tanpa moderator_id, laporan ditugaskan ke diri sendiri.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int                         true   "Report ID"
+// @Param        request  body  domain.AssignReportRequest  false  "Moderator"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.AdminReportResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Failure      409  {object}  utils.StandardResponse
+// @Router       /admin/reports/{id}/assign [post]
+func (h *ReportHandler) AssignReport(c *gin.Context) {
+	reportID, ok := parseIDParam(c, "ID laporan tidak valid")
+	if !ok {
+		return
+	}
+
+	// Body boleh kosong untuk menugaskan laporan ke diri sendiri
+	var req domain.AssignReportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(errors.ValidationError("Gagal membaca data", err))
+			return
+		}
+	}
+
+	report, err := h.reportService.Assign(c.Request.Context(), reportID, req)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal menugaskan laporan")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Laporan berhasil ditugaskan", report)
+}
+
+// ResolveReport menyelesaikan laporan
+// @Summary      Resolve report
+// @Description  Menyelesaikan laporan sebagai actioned (target tetap disembunyikan) atau dismissed (target ditampilkan kembali). Laporan terbuka lain terhadap target yang sama ikut diselesaikan. admin_log_ids menghubungkan tindakan lain, misalnya penangguhan, dengan laporan ini.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int                          true  "Report ID"
+// @Param        request  body  domain.ResolveReportRequest  true  "Keputusan"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.AdminReportResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Failure      409  {object}  utils.StandardResponse
+// @Router       /admin/reports/{id}/resolve [post]
+func (h *ReportHandler) ResolveReport(c *gin.Context) {
+	reportID, ok := parseIDParam(c, "ID laporan tidak valid")
+	if !ok {
+		return
+	}
+
+	var req domain.ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.ValidationError("Gagal membaca data", err))
+		return
+	}
+
+	report, err := h.reportService.Resolve(c.Request.Context(), reportID, req)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal menyelesaikan laporan")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Laporan berhasil diselesaikan", report)
+}
+
+// paginationParams membaca parameter page dan limit dengan nilai default 1 dan 10
+func paginationParams(c *gin.Context) (int, int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	return page, limit
+}
+
+// RegisterRoutes mendaftarkan route untuk ReportHandler
+func (h *ReportHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, requirePermission func(policy.Action) gin.HandlerFunc) {
+	reports := router.Group("/reports")
+	reports.Use(authMiddleware)
+	{
+		reports.POST("", h.CreateReport)
+		reports.GET("/me", h.GetMyReports)
+	}
+
+	admin := router.Group("/admin/reports")
+	admin.Use(authMiddleware, requirePermission(policy.ReportModerate))
+	{
+		admin.GET("", h.GetReportQueue)
+		admin.GET("/:id", h.GetReport)
+		admin.POST("/:id/assign", h.AssignReport)
+		admin.POST("/:id/resolve", h.ResolveReport)
+	}
+}
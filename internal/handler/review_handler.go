@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
+	"github.com/mfuadfakhruzzaki/jubel/internal/service"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+// ReviewHandler menangani endpoint ulasan transaksi
+type ReviewHandler struct {
+	reviewService service.ReviewService
+}
+
+// NewReviewHandler membuat instance baru ReviewHandler
+func NewReviewHandler(reviewService service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// CreateReview membuat ulasan untuk transaksi yang sudah selesai
+// @Summary      Create review
+// @Description  Memberi rating 1-5 dan komentar untuk pihak lain dalam transaksi yang sudah selesai. Pembeli dan penjual masing-masing hanya dapat memberi satu ulasan per transaksi.
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "Transaction ID"
+// @Param        request  body      domain.ReviewRequest  true  "Rating dan komentar"
+// @Security     BearerAuth
+// @Success      201  {object}  utils.StandardResponse{data=domain.ReviewResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Failure      409  {object}  utils.StandardResponse
+// @Router       /transactions/{id}/reviews [post]
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	transactionID, ok := parseIDParam(c, "ID transaksi tidak valid")
+	if !ok {
+		return
+	}
+
+	var req domain.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.ValidationError("Gagal membaca data", err))
+		return
+	}
+
+	review, err := h.reviewService.Create(c.Request.Context(), transactionID, c.GetUint("userID"), req)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal membuat ulasan")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Ulasan berhasil dibuat", review)
+}
+
+// GetTransactionReviews mendapatkan ulasan untuk satu transaksi
+// @Summary      Get transaction reviews
+// @Description  Mendapatkan ulasan pembeli dan penjual untuk transaksi (hanya pihak dalam transaksi dan admin)
+// @Tags         reviews
+// @Produce      json
+// @Param        id   path      int  true  "Transaction ID"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=[]domain.ReviewResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /transactions/{id}/reviews [get]
+func (h *ReviewHandler) GetTransactionReviews(c *gin.Context) {
+	transactionID, ok := parseIDParam(c, "ID transaksi tidak valid")
+	if !ok {
+		return
+	}
+
+	reviews, err := h.reviewService.GetByTransactionID(c.Request.Context(), transactionID, c.GetUint("userID"))
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil ulasan")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data ulasan berhasil diambil", reviews)
+}
+
+// GetUserReviews mendapatkan ulasan untuk pengguna
+// @Summary      Get user reviews
+// @Description  Mendapatkan ulasan yang diterima pengguna (tanpa ulasan yang disembunyikan). Tidak membutuhkan autentikasi.
+// @Tags         reviews
+// @Produce      json
+// @Param        id     path      int  true   "User ID"
+// @Param        page   query     int  false  "Page number (default: 1)"
+// @Param        limit  query     int  false  "Items per page (default: 10)"
+// @Success      200  {object}  utils.PaginatedResponse{data=[]domain.ReviewResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /users/{id}/reviews [get]
+func (h *ReviewHandler) GetUserReviews(c *gin.Context) {
+	userID, ok := parseIDParam(c, "ID pengguna tidak valid")
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	reviews, totalPages, totalItems, err := h.reviewService.GetByUserID(c.Request.Context(), userID, page, limit)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengambil ulasan")
+		return
+	}
+
+	utils.SuccessPaginatedResponse(c, http.StatusOK, "Data ulasan berhasil diambil", reviews, utils.Meta{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	})
+}
+
+// UpdateReview mengubah ulasan
+// @Summary      Update review
+// @Description  Mengubah rating dan komentar ulasan milik sendiri selama masih dalam batas waktu edit
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "Review ID"
+// @Param        request  body      domain.ReviewRequest  true  "Rating dan komentar"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.ReviewResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /reviews/{id} [patch]
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	reviewID, ok := parseIDParam(c, "ID ulasan tidak valid")
+	if !ok {
+		return
+	}
+
+	var req domain.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.ValidationError("Gagal membaca data", err))
+		return
+	}
+
+	review, err := h.reviewService.Update(c.Request.Context(), reviewID, c.GetUint("userID"), req)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal mengubah ulasan")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ulasan berhasil diperbarui", review)
+}
+
+// ReplyReview membalas ulasan
+// @Summary      Reply to review
+// @Description  Menambahkan balasan dari pengguna yang diulas. Balasan dapat diubah selama masih dalam batas waktu edit.
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "Review ID"
+// @Param        request  body      domain.ReplyReviewRequest  true  "Balasan"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.ReviewResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /reviews/{id}/reply [put]
+func (h *ReviewHandler) ReplyReview(c *gin.Context) {
+	reviewID, ok := parseIDParam(c, "ID ulasan tidak valid")
+	if !ok {
+		return
+	}
+
+	var req domain.ReplyReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.ValidationError("Gagal membaca data", err))
+		return
+	}
+
+	review, err := h.reviewService.Reply(c.Request.Context(), reviewID, c.GetUint("userID"), req)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal membalas ulasan")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Balasan berhasil disimpan", review)
+}
+
+// HideReview menyembunyikan ulasan
+// @Summary      Hide review
+// @Description  Menyembunyikan ulasan yang melanggar aturan (admin dan moderator). Ulasan tersembunyi tidak ditampilkan dan tidak dihitung dalam rating.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                       true  "Review ID"
+// @Param        request  body      domain.HideReviewRequest  true  "Alasan"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.ReviewResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Failure      409  {object}  utils.StandardResponse
+// @Router       /admin/reviews/{id}/hidden [post]
+func (h *ReviewHandler) HideReview(c *gin.Context) {
+	reviewID, ok := parseIDParam(c, "ID ulasan tidak valid")
+	if !ok {
+		return
+	}
+
+	var req domain.HideReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.ValidationError("Gagal membaca data", err))
+		return
+	}
+
+	review, err := h.reviewService.Hide(c.Request.Context(), reviewID, req)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal menyembunyikan ulasan")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ulasan berhasil disembunyikan", review)
+}
+
+// UnhideReview menampilkan kembali ulasan
+// @Summary      Unhide review
+// @Description  Menampilkan kembali ulasan yang disembunyikan (admin dan moderator)
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      int  true  "Review ID"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.ReviewResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      403  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Failure      409  {object}  utils.StandardResponse
+// @Router       /admin/reviews/{id}/hidden [delete]
+func (h *ReviewHandler) UnhideReview(c *gin.Context) {
+	reviewID, ok := parseIDParam(c, "ID ulasan tidak valid")
+	if !ok {
+		return
+	}
+
+	review, err := h.reviewService.Unhide(c.Request.Context(), reviewID)
+	if err != nil {
+		abortWithServiceError(c, err, "Gagal menampilkan ulasan")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ulasan berhasil ditampilkan kembali", review)
+}
+
+// RegisterRoutes mendaftarkan route untuk ReviewHandler
+func (h *ReviewHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, requirePermission func(policy.Action) gin.HandlerFunc) {
+	router.POST("/transactions/:id/reviews", authMiddleware, h.CreateReview)
+	router.GET("/transactions/:id/reviews", authMiddleware, h.GetTransactionReviews)
+	router.GET("/users/:id/reviews", h.GetUserReviews)
+
+	reviews := router.Group("/reviews")
+	reviews.Use(authMiddleware)
+	{
+		reviews.PATCH("/:id", h.UpdateReview)
+		reviews.PUT("/:id/reply", h.ReplyReview)
+	}
+
+	admin := router.Group("/admin/reviews")
+	admin.Use(authMiddleware, requirePermission(policy.ReviewModerate))
+	{
+		admin.POST("/:id/hidden", h.HideReview)
+		admin.DELETE("/:id/hidden", h.UnhideReview)
+	}
+}
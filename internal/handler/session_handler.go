@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/jubel/internal/service"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+// SessionHandler menangani endpoint pengelolaan sesi login pengguna
+type SessionHandler struct {
+	sessionService service.SessionService
+}
+
+// NewSessionHandler membuat instance baru SessionHandler
+func NewSessionHandler(sessionService service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// GetMySessions mendapatkan daftar sesi aktif pengguna yang sedang login
+// @Summary      List my sessions
+// @Description  Mendapatkan daftar perangkat tempat pengguna sedang login
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=[]domain.SessionResponse}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /users/me/sessions [get]
+func (h *SessionHandler) GetMySessions(c *gin.Context) {
+	// Dapatkan user ID dari context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	sessions, err := h.sessionService.List(c.Request.Context(), userID.(uint), c.GetString("sessionID"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data sesi berhasil diambil", sessions)
+}
+
+// RevokeSession mengakhiri satu sesi milik pengguna
+// @Summary      Revoke session
+// @Description  Mengakhiri sesi pada satu perangkat. Token dari sesi tersebut langsung tidak berlaku.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "Session ID"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /users/me/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	// Dapatkan user ID dari context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	if err := h.sessionService.Revoke(c.Request.Context(), userID.(uint), c.Param("id")); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sesi berhasil diakhiri", nil)
+}
+
+// RevokeOtherSessions mengakhiri semua sesi selain sesi yang sedang dipakai
+// @Summary      Log out everywhere else
+// @Description  Mengakhiri semua sesi pengguna kecuali sesi yang sedang dipakai
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /users/me/sessions [delete]
+func (h *SessionHandler) RevokeOtherSessions(c *gin.Context) {
+	// Dapatkan user ID dari context
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	count, err := h.sessionService.RevokeOthers(c.Request.Context(), userID.(uint), c.GetString("sessionID"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sesi lain berhasil diakhiri", gin.H{
+		"revoked": count,
+	})
+}
+
+// RegisterRoutes mendaftarkan route untuk SessionHandler
+func (h *SessionHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	sessions := router.Group("/users/me/sessions")
+	sessions.Use(authMiddleware)
+	{
+		sessions.GET("", h.GetMySessions)
+		sessions.DELETE("", h.RevokeOtherSessions)
+		sessions.DELETE("/:id", h.RevokeSession)
+	}
+}
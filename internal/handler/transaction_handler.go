@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
 	"github.com/mfuadfakhruzzaki/jubel/internal/service"
 	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
 )
@@ -132,13 +134,6 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 // @Failure      500    {object}  utils.StandardResponse
 // @Router       /transactions [get]
 func (h *TransactionHandler) GetAllTransactions(c *gin.Context) {
-	// Cek role dari context
-	role, exists := c.Get("userRole")
-	if !exists || role.(domain.Role) != domain.RoleAdmin {
-		utils.ErrorResponse(c, http.StatusForbidden, "Akses ditolak: memerlukan hak admin", nil)
-		return
-	}
-
 	// Dapatkan parameter paginasi
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "10")
@@ -156,6 +151,11 @@ func (h *TransactionHandler) GetAllTransactions(c *gin.Context) {
 	// Dapatkan daftar transaksi
 	transactions, totalPages, totalItems, err := h.transactionService.GetAll(c.Request.Context(), page, limit)
 	if err != nil {
+		// Izin melihat semua transaksi diperiksa oleh policy di service
+		if errors.Is(err, policy.ErrForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error(), nil)
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
 		return
 	}
@@ -285,6 +285,7 @@ func (h *TransactionHandler) GetMyTransactionsAsPenjual(c *gin.Context) {
 // @Failure      401      {object}  utils.StandardResponse
 // @Failure      403      {object}  utils.StandardResponse
 // @Failure      404      {object}  utils.StandardResponse
+// @Failure      409      {object}  utils.StandardResponse
 // @Failure      500      {object}  utils.StandardResponse
 // @Router       /transactions/{id}/status [patch]
 func (h *TransactionHandler) UpdateTransactionStatus(c *gin.Context) {
@@ -322,6 +323,10 @@ func (h *TransactionHandler) UpdateTransactionStatus(c *gin.Context) {
 
 	// Update status transaksi
 	if err := h.transactionService.UpdateStatus(c.Request.Context(), uint(id), statusData.Status, userID.(uint)); err != nil {
+		if errors.Is(err, service.ErrTransactionFrozen) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
 		return
 	}
@@ -342,6 +347,7 @@ func (h *TransactionHandler) UpdateTransactionStatus(c *gin.Context) {
 // @Failure      401  {object}  utils.StandardResponse
 // @Failure      403  {object}  utils.StandardResponse
 // @Failure      404  {object}  utils.StandardResponse
+// @Failure      409  {object}  utils.StandardResponse
 // @Failure      500  {object}  utils.StandardResponse
 // @Router       /transactions/{id} [delete]
 func (h *TransactionHandler) DeleteTransaction(c *gin.Context) {
@@ -362,6 +368,10 @@ func (h *TransactionHandler) DeleteTransaction(c *gin.Context) {
 
 	// Hapus transaksi
 	if err := h.transactionService.Delete(c.Request.Context(), uint(id), userID.(uint)); err != nil {
+		if errors.Is(err, service.ErrTransactionFrozen) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error(), nil)
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
 		return
 	}
@@ -370,16 +380,15 @@ func (h *TransactionHandler) DeleteTransaction(c *gin.Context) {
 }
 
 // RegisterRoutes mendaftarkan route untuk TransactionHandler
-func (h *TransactionHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+func (h *TransactionHandler) RegisterRoutes(router *gin.RouterGroup, scopedAuth func(domain.Scope) gin.HandlerFunc, verifiedMiddleware gin.HandlerFunc) {
 	transactions := router.Group("/transactions")
-	transactions.Use(authMiddleware)
 	{
-		transactions.POST("", h.CreateTransaction)
-		transactions.GET("", h.GetAllTransactions)
-		transactions.GET("/:id", h.GetTransaction)
-		transactions.GET("/as-pembeli", h.GetMyTransactionsAsPembeli)
-		transactions.GET("/as-penjual", h.GetMyTransactionsAsPenjual)
-		transactions.PATCH("/:id/status", h.UpdateTransactionStatus)
-		transactions.DELETE("/:id", h.DeleteTransaction)
+		transactions.POST("", scopedAuth(domain.ScopeTransactionsWrite), verifiedMiddleware, h.CreateTransaction)
+		transactions.GET("", scopedAuth(domain.ScopeTransactionsRead), h.GetAllTransactions)
+		transactions.GET("/:id", scopedAuth(domain.ScopeTransactionsRead), h.GetTransaction)
+		transactions.GET("/as-pembeli", scopedAuth(domain.ScopeTransactionsRead), h.GetMyTransactionsAsPembeli)
+		transactions.GET("/as-penjual", scopedAuth(domain.ScopeTransactionsRead), h.GetMyTransactionsAsPenjual)
+		transactions.PATCH("/:id/status", scopedAuth(domain.ScopeTransactionsWrite), h.UpdateTransactionStatus)
+		transactions.DELETE("/:id", scopedAuth(domain.ScopeTransactionsWrite), h.DeleteTransaction)
 	}
 }
\ No newline at end of file
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	apperrors "github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
 	"github.com/mfuadfakhruzzaki/jubel/internal/service"
 	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
 )
@@ -24,7 +27,7 @@ func NewUserHandler(userService service.UserService) *UserHandler {
 
 // GetUser mendapatkan data pengguna berdasarkan ID
 // @Summary      Get user by ID
-// @Description  Mendapatkan detail pengguna berdasarkan ID
+// @Description  Mendapatkan detail pengguna berdasarkan ID. Pemilik akun dan admin mendapatkan data lengkap (domain.UserResponse), pengguna lain hanya mendapatkan profil publik (domain.PublicProfile).
 // @Tags         users
 // @Accept       json
 // @Produce      json
@@ -47,6 +50,11 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	// Dapatkan data pengguna
 	user, err := h.userService.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
+		// Selain pemilik akun dan admin hanya boleh melihat profil publik
+		if errors.Is(err, policy.ErrForbidden) {
+			h.respondPublicProfile(c, uint(id))
+			return
+		}
 		utils.ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
 		return
 	}
@@ -54,6 +62,38 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Data pengguna berhasil diambil", user)
 }
 
+// GetUserProfile mendapatkan profil publik pengguna
+// @Summary      Get public user profile
+// @Description  Mendapatkan profil publik penjual (nama, tanggal bergabung, jumlah barang, penjualan selesai, tingkat respons dan rating) tanpa data kontak. Tidak membutuhkan autentikasi.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  utils.StandardResponse{data=domain.PublicProfile}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /users/{id}/profile [get]
+func (h *UserHandler) GetUserProfile(c *gin.Context) {
+	// Dapatkan ID dari URL
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ID pengguna tidak valid", nil)
+		return
+	}
+
+	h.respondPublicProfile(c, uint(id))
+}
+
+// respondPublicProfile mengirim profil publik pengguna
+func (h *UserHandler) respondPublicProfile(c *gin.Context, id uint) {
+	profile, err := h.userService.GetPublicProfile(c.Request.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Profil pengguna berhasil diambil", profile)
+}
+
 // GetCurrentUser mendapatkan data pengguna yang sedang login
 // @Summary      Get current user
 // @Description  Mendapatkan detail pengguna yang sedang login
@@ -155,19 +195,6 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	// Dapatkan user ID dari context
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
-		return
-	}
-
-	// Validasi akses
-	if uint(id) != userID.(uint) {
-		utils.ErrorResponse(c, http.StatusForbidden, "Anda hanya dapat mengubah data diri sendiri", nil)
-		return
-	}
-
 	// Bind data menggunakan struct khusus untuk update
 	var userData struct {
 		Nama     string `json:"nama"`
@@ -193,6 +220,10 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	// Update pengguna
 	updatedUser, err := h.userService.Update(c.Request.Context(), uint(id), user)
 	if err != nil {
+		if errors.Is(err, policy.ErrForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, "Anda hanya dapat mengubah data diri sendiri", nil)
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
 		return
 	}
@@ -200,18 +231,19 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Data pengguna berhasil diperbarui", updatedUser)
 }
 
-// DeleteUser menghapus pengguna
+// DeleteUser menjadwalkan penghapusan pengguna
 // @Summary      Delete user
-// @Description  Menghapus pengguna berdasarkan ID (self atau admin)
+// @Description  Menjadwalkan penghapusan akun (self atau admin). Akun langsung dinonaktifkan dan data pribadinya dianonimkan setelah masa tenggang; riwayat transaksi dan chat pihak lain tetap disimpan. Tautan pemulihan dikirim ke email pengguna.
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Param        id  path      int  true  "User ID"
 // @Security     BearerAuth
-// @Success      200  {object}  utils.StandardResponse
+// @Success      200  {object}  utils.StandardResponse{data=domain.UserResponse}
 // @Failure      400  {object}  utils.StandardResponse
 // @Failure      401  {object}  utils.StandardResponse
 // @Failure      403  {object}  utils.StandardResponse
+// @Failure      409  {object}  utils.StandardResponse
 // @Failure      500  {object}  utils.StandardResponse
 // @Router       /users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
@@ -223,42 +255,132 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	// Dapatkan user ID dari context
-	userID, exists := c.Get("userID")
-	if !exists {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+	// Jadwalkan penghapusan (izin diperiksa oleh policy: diri sendiri atau admin)
+	user, err := h.userService.Delete(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, policy.ErrForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, "Anda tidak memiliki izin untuk menghapus pengguna ini", nil)
+			return
+		}
+		if _, ok := apperrors.AsStandardError(err); ok {
+			_ = c.Error(err)
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
 		return
 	}
 
-	// Dapatkan role dari context
-	role, exists := c.Get("userRole")
-	if !exists {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+	utils.SuccessResponse(c, http.StatusOK, "Akun dijadwalkan untuk dihapus", user)
+}
+
+// RestoreUser membatalkan penghapusan akun
+// @Summary      Restore deleted account
+// @Description  Membatalkan penghapusan akun selama masa tenggang menggunakan token dari email pemulihan. Setelah dipulihkan, pengguna perlu login kembali.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        request  body      domain.RestoreAccountRequest  true  "Token pemulihan"
+// @Success      200  {object}  utils.StandardResponse{data=domain.UserResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Router       /users/restore [post]
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	var req domain.RestoreAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Gagal membaca data: "+err.Error(), nil)
 		return
 	}
-
-	// Validasi akses
-	if uint(id) != userID.(uint) && role.(domain.Role) != domain.RoleAdmin {
-		utils.ErrorResponse(c, http.StatusForbidden, "Anda tidak memiliki izin untuk menghapus pengguna ini", nil)
+	if req.Token == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Token tidak boleh kosong", nil)
 		return
 	}
 
-	// Hapus pengguna
-	if err := h.userService.Delete(c.Request.Context(), uint(id)); err != nil {
+	user, err := h.userService.Restore(c.Request.Context(), req.Token)
+	if err != nil {
+		if _, ok := apperrors.AsStandardError(err); ok {
+			_ = c.Error(err)
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Pengguna berhasil dihapus", nil)
+	utils.SuccessResponse(c, http.StatusOK, "Akun berhasil dipulihkan, silakan login kembali", user)
+}
+
+// UploadAvatar mengganti foto profil pengguna yang sedang login
+// @Summary      Upload avatar
+// @Description  Mengunggah foto profil (JPEG, PNG atau GIF). Gambar dipotong menjadi persegi dan dibuatkan thumbnail; foto profil lama dihapus.
+// @Tags         users
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        avatar  formData  file  true  "Image file"
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.UserResponse}
+// @Failure      400  {object}  utils.StandardResponse
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      500  {object}  utils.StandardResponse
+// @Router       /users/me/avatar [put]
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "File foto profil tidak boleh kosong", nil)
+		return
+	}
+
+	user, err := h.userService.UploadAvatar(c.Request.Context(), c.GetUint("userID"), file)
+	if err != nil {
+		h.handleAvatarError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Foto profil berhasil diperbarui", user)
+}
+
+// DeleteAvatar menghapus foto profil pengguna yang sedang login
+// @Summary      Delete avatar
+// @Description  Menghapus foto profil beserta thumbnail-nya
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.StandardResponse{data=domain.UserResponse}
+// @Failure      401  {object}  utils.StandardResponse
+// @Failure      404  {object}  utils.StandardResponse
+// @Router       /users/me/avatar [delete]
+func (h *UserHandler) DeleteAvatar(c *gin.Context) {
+	user, err := h.userService.DeleteAvatar(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		h.handleAvatarError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Foto profil berhasil dihapus", user)
+}
+
+// handleAvatarError menerjemahkan error dari layanan foto profil ke respons HTTP
+func (h *UserHandler) handleAvatarError(c *gin.Context, err error) {
+	if errors.Is(err, policy.ErrForbidden) {
+		utils.ErrorResponse(c, http.StatusForbidden, "Anda tidak memiliki izin untuk mengubah foto profil ini", nil)
+		return
+	}
+	if _, ok := apperrors.AsStandardError(err); ok {
+		_ = c.Error(err)
+		return
+	}
+	utils.ErrorResponse(c, http.StatusInternalServerError, err.Error(), nil)
 }
 
-// RegisterRoutes mendaftarkan route untuk UserHandler
-func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc) {
+// RegisterRoutes mendaftarkan route untuk UserHandler. optionalAuth hanya memverifikasi token jika ada,
+// sehingga pemilik dan moderator tetap dapat melihat profil yang disembunyikan.
+func (h *UserHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware gin.HandlerFunc, adminMiddleware gin.HandlerFunc, scopedAuth func(domain.Scope) gin.HandlerFunc, optionalAuth gin.HandlerFunc) {
 	users := router.Group("/users")
 	{
 		users.GET("", authMiddleware, adminMiddleware, h.GetAllUsers)
-		users.GET("/me", authMiddleware, h.GetCurrentUser)
+		users.GET("/me", scopedAuth(domain.ScopeProfileRead), h.GetCurrentUser)
 		users.GET("/:id", authMiddleware, h.GetUser)
+		users.GET("/:id/profile", optionalAuth, h.GetUserProfile)
+		users.POST("/restore", h.RestoreUser)
+		users.PUT("/me/avatar", authMiddleware, h.UploadAvatar)
+		users.DELETE("/me/avatar", authMiddleware, h.DeleteAvatar)
 		users.PATCH("/:id", authMiddleware, h.UpdateUser)
 		users.DELETE("/:id", authMiddleware, h.DeleteUser)
 	}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+// WellKnownHandler menangani endpoint /.well-known yang dibaca oleh layanan lain
+type WellKnownHandler struct {
+	keys *utils.KeyRing
+}
+
+// NewWellKnownHandler membuat instance baru WellKnownHandler
+func NewWellKnownHandler(keys *utils.KeyRing) *WellKnownHandler {
+	return &WellKnownHandler{
+		keys: keys,
+	}
+}
+
+// GetJWKS mengembalikan kunci publik untuk memverifikasi token JWT Jubel
+// @Summary      JSON Web Key Set
+// @Description  Kunci publik (RS256/EdDSA) untuk memverifikasi token JWT yang diterbitkan Jubel. Kunci yang sudah dipensiunkan tetap dipublikasikan selama token lama masih bisa berlaku.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  utils.JWKS
+// @Router       /.well-known/jwks.json [get]
+func (h *WellKnownHandler) GetJWKS(c *gin.Context) {
+	// Layanan lain boleh menyimpan kunci sebentar, kunci baru akan terlihat setelah cache habis
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
+
+// RegisterRoutes mendaftarkan route untuk WellKnownHandler.
+// Route ini didaftarkan di root, bukan di bawah /api/v1.
+func (h *WellKnownHandler) RegisterRoutes(router *gin.RouterGroup) {
+	wellKnown := router.Group("/.well-known")
+	{
+		wellKnown.GET("/jwks.json", h.GetJWKS)
+	}
+}
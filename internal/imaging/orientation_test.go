@@ -0,0 +1,242 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// exifTIFF membuat data TIFF EXIF dengan satu entri Orientation pada IFD0
+func exifTIFF(order binary.ByteOrder, orientation uint16) []byte {
+	buf := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(buf, "II")
+	} else {
+		copy(buf, "MM")
+	}
+	order.PutUint16(buf[2:], 42)
+	order.PutUint32(buf[4:], 8)
+	order.PutUint16(buf[8:], 1)
+	order.PutUint16(buf[10:], exifOrientationTag)
+	order.PutUint16(buf[12:], 3) // SHORT
+	order.PutUint32(buf[14:], 1)
+	order.PutUint16(buf[18:], orientation)
+	// Offset IFD berikutnya (buf[22:26]) bernilai 0
+	return buf
+}
+
+// app1Segment membungkus payload menjadi segmen APP1 JPEG dengan panjang segmen yang benar
+func app1Segment(payload []byte) []byte {
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// withSegment menyisipkan segmen tepat setelah marker SOI JPEG
+func withSegment(jpegData, segment []byte) []byte {
+	out := append([]byte{}, jpegData[:2]...)
+	out = append(out, segment...)
+	return append(out, jpegData[2:]...)
+}
+
+// exifJPEG membuat JPEG width x height dengan segmen EXIF berisi orientation
+func exifJPEG(t *testing.T, width, height int, order binary.ByteOrder, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatalf("encode JPEG: %v", err)
+	}
+	payload := append([]byte("Exif\x00\x00"), exifTIFF(order, orientation)...)
+	return withSegment(buf.Bytes(), app1Segment(payload))
+}
+
+// hasExifSegment memeriksa apakah JPEG memiliki segmen APP1 Exif sebelum data gambar
+func hasExifSegment(data []byte) bool {
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return false
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			return false
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xE1 && i+4+6 <= len(data) && string(data[i+4:i+10]) == "Exif\x00\x00" {
+			return true
+		}
+		i += 2 + size
+	}
+	return false
+}
+
+func TestJPEGOrientationByteOrders(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for orientation := uint16(1); orientation <= 8; orientation++ {
+			data := exifJPEG(t, 4, 2, order, orientation)
+			if got := jpegOrientation(data); got != int(orientation) {
+				t.Errorf("%v orientation %d: jpegOrientation = %d", order, orientation, got)
+			}
+		}
+	}
+}
+
+func TestJPEGOrientationInvalidData(t *testing.T) {
+	valid := exifJPEG(t, 4, 2, binary.LittleEndian, 6)
+
+	var plain bytes.Buffer
+	if err := jpeg.Encode(&plain, image.NewRGBA(image.Rect(0, 0, 4, 2)), nil); err != nil {
+		t.Fatalf("encode JPEG: %v", err)
+	}
+
+	ifdPastEnd := exifTIFF(binary.LittleEndian, 6)
+	binary.LittleEndian.PutUint32(ifdPastEnd[4:], 0xFFFFFFFF)
+
+	ifdAtEnd := exifTIFF(binary.BigEndian, 6)
+	binary.BigEndian.PutUint32(ifdAtEnd[4:], uint32(len(ifdAtEnd)-1))
+
+	tooManyEntries := exifTIFF(binary.LittleEndian, 6)
+	binary.LittleEndian.PutUint16(tooManyEntries[8:], 0xFFFF)
+	tooManyEntries[10] = 0 // entri pertama bukan Orientation sehingga entri berikutnya ikut dibaca
+
+	badOrder := exifTIFF(binary.LittleEndian, 6)
+	copy(badOrder, "XX")
+
+	badMagic := exifTIFF(binary.BigEndian, 6)
+	binary.BigEndian.PutUint16(badMagic[2:], 43)
+
+	exif := func(tiff []byte) []byte {
+		return withSegment(plain.Bytes(), app1Segment(append([]byte("Exif\x00\x00"), tiff...)))
+	}
+
+	// Panjang segmen APP1 melebihi sisa file
+	oversized := withSegment(plain.Bytes(), []byte{0xFF, 0xE1, 0xFF, 0xFF, 'E', 'x', 'i', 'f'})
+	// Panjang segmen lebih kecil dari 2
+	undersized := withSegment(plain.Bytes(), []byte{0xFF, 0xE1, 0x00, 0x01})
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"kosong", nil},
+		{"bukan JPEG", []byte("\x89PNG\r\n\x1a\n")},
+		{"hanya SOI", []byte{0xFF, 0xD8}},
+		{"JPEG tanpa EXIF", plain.Bytes()},
+		{"APP1 bukan Exif", withSegment(plain.Bytes(), app1Segment([]byte("http://ns.adobe.com/xap/1.0/")))},
+		{"APP1 melebihi file", oversized},
+		{"APP1 terlalu pendek", undersized},
+		{"TIFF terpotong", exif([]byte("II*\x00"))},
+		{"byte order tidak dikenal", exif(badOrder)},
+		{"magic TIFF salah", exif(badMagic)},
+		{"offset IFD melewati akhir", exif(ifdPastEnd)},
+		{"offset IFD di akhir buffer", exif(ifdAtEnd)},
+		{"jumlah entri IFD melebihi data", exif(tooManyEntries)},
+		{"orientation 0", exif(exifTIFF(binary.LittleEndian, 0))},
+		{"orientation 9", exif(exifTIFF(binary.LittleEndian, 9))},
+		{"data acak setelah SOI", []byte{0xFF, 0xD8, 0x12, 0x34, 0x56, 0x78}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegOrientation(tt.data); got != 1 {
+				t.Errorf("jpegOrientation = %d, want 1", got)
+			}
+		})
+	}
+
+	// Setiap potongan file valid tidak boleh membuat parser panic
+	for n := 0; n <= len(valid); n++ {
+		if got := jpegOrientation(valid[:n]); got < 1 || got > 8 {
+			t.Fatalf("jpegOrientation(valid[:%d]) = %d, di luar 1..8", n, got)
+		}
+	}
+}
+
+func FuzzJPEGOrientation(f *testing.F) {
+	f.Add([]byte{0xFF, 0xD8})
+	f.Add(withSegment([]byte{0xFF, 0xD8, 0xFF, 0xD9}, app1Segment(append([]byte("Exif\x00\x00"), exifTIFF(binary.LittleEndian, 6)...))))
+	f.Add(withSegment([]byte{0xFF, 0xD8, 0xFF, 0xD9}, app1Segment(append([]byte("Exif\x00\x00"), exifTIFF(binary.BigEndian, 8)...))))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if got := jpegOrientation(data); got < 1 || got > 8 {
+			t.Fatalf("jpegOrientation = %d, di luar 1..8", got)
+		}
+	})
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// Gambar 3x2 dengan warna berbeda di setiap sudut
+	const width, height = 3, 2
+	src := image.NewRGBA(image.Rect(0, 0, width, height))
+	corners := map[image.Point]color.RGBA{
+		{0, 0}:                  {255, 0, 0, 255},
+		{width - 1, 0}:          {0, 255, 0, 255},
+		{0, height - 1}:         {0, 0, 255, 255},
+		{width - 1, height - 1}: {255, 255, 0, 255},
+	}
+	for p, c := range corners {
+		src.SetRGBA(p.X, p.Y, c)
+	}
+
+	// topLeft adalah piksel sumber yang tampil di kiri atas setelah orientasi diterapkan
+	tests := []struct {
+		orientation   int
+		width, height int
+		topLeft       image.Point
+	}{
+		{1, 3, 2, image.Pt(0, 0)},
+		{2, 3, 2, image.Pt(width-1, 0)},
+		{3, 3, 2, image.Pt(width-1, height-1)},
+		{4, 3, 2, image.Pt(0, height-1)},
+		{5, 2, 3, image.Pt(0, 0)},
+		{6, 2, 3, image.Pt(0, height-1)},
+		{7, 2, 3, image.Pt(width-1, height-1)},
+		{8, 2, 3, image.Pt(width-1, 0)},
+	}
+	for _, tt := range tests {
+		got := applyOrientation(src, tt.orientation)
+		bounds := got.Bounds()
+		if bounds.Dx() != tt.width || bounds.Dy() != tt.height {
+			t.Errorf("orientation %d: ukuran %dx%d, want %dx%d", tt.orientation, bounds.Dx(), bounds.Dy(), tt.width, tt.height)
+			continue
+		}
+		want := corners[tt.topLeft]
+		if c := color.RGBAModel.Convert(got.At(bounds.Min.X, bounds.Min.Y)).(color.RGBA); c != want {
+			t.Errorf("orientation %d: piksel kiri atas %v, want %v", tt.orientation, c, want)
+		}
+	}
+}
+
+func TestDecodeAppliesOrientationAndEncodeStripsExif(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		data := exifJPEG(t, 40, 20, order, 6)
+		if !hasExifSegment(data) {
+			t.Fatal("fixture tidak memiliki segmen EXIF")
+		}
+
+		img, format, err := Decode(data)
+		if err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		if format != "jpeg" {
+			t.Fatalf("format = %q, want jpeg", format)
+		}
+		if b := img.Bounds(); b.Dx() != 20 || b.Dy() != 40 {
+			t.Errorf("%v: ukuran setelah Decode %dx%d, want 20x40", order, b.Dx(), b.Dy())
+		}
+
+		encoded, ext, contentType, err := Encode(img, format)
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		if ext != ".jpg" || contentType != "image/jpeg" {
+			t.Errorf("Encode = %q %q, want .jpg image/jpeg", ext, contentType)
+		}
+		if hasExifSegment(encoded) {
+			t.Errorf("%v: hasil encode masih memiliki segmen APP1 Exif", order)
+		}
+		if got := jpegOrientation(encoded); got != 1 {
+			t.Errorf("%v: orientation hasil encode = %d, want 1", order, got)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package imaging
+
+import (
+	"bytes"
+)
+
+// DetectContentType menentukan content type gambar dari magic bytes di awal file,
+// bukan dari nama file atau header Content-Type yang dikirim klien.
+// Mengembalikan "application/octet-stream" jika format tidak dikenali.
+func DetectContentType(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return "image/jpeg"
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return "image/png"
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return "image/gif"
+	case len(data) >= 12 && bytes.Equal(data[:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP")):
+		return "image/webp"
+	}
+	return "application/octet-stream"
+}
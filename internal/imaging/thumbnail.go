@@ -0,0 +1,152 @@
+// Package imaging berisi pengolahan gambar sederhana untuk file yang diunggah pengguna
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+
+	// Registrasi decoder GIF untuk image.Decode
+	_ "image/gif"
+)
+
+const (
+	// jpegQuality adalah kualitas kompresi untuk hasil berformat JPEG
+	jpegQuality = 85
+	// maxPixels membatasi resolusi gambar agar file kecil dengan resolusi sangat besar
+	// tidak menghabiskan memori saat di-decode
+	maxPixels = 25_000_000
+)
+
+// Decode membaca gambar JPEG, PNG atau GIF dan mengembalikan nama formatnya.
+// Foto JPEG diputar sesuai tag Orientation EXIF, karena metadata EXIF tidak ikut disimpan saat gambar di-encode ulang.
+func Decode(data []byte) (image.Image, string, error) {
+	if err := Validate(data); err != nil {
+		return nil, "", err
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("gagal membaca gambar: %w", err)
+	}
+	if format == "jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+	return img, format, nil
+}
+
+// Validate memeriksa header gambar dan resolusinya tanpa men-decode seluruh piksel,
+// sehingga banyak file dapat diperiksa sebelum salah satunya diproses
+func Validate(data []byte) error {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("gagal membaca gambar: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return fmt.Errorf("resolusi gambar %dx%d tidak didukung", cfg.Width, cfg.Height)
+	}
+	return nil
+}
+
+// SquareThumbnail memotong bagian tengah gambar menjadi persegi lalu memperkecilnya
+// menjadi size x size piksel. Gambar yang lebih kecil dari size tidak diperbesar.
+func SquareThumbnail(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+
+	// Potong bagian tengah gambar menjadi persegi
+	origin := image.Pt(
+		bounds.Min.X+(bounds.Dx()-side)/2,
+		bounds.Min.Y+(bounds.Dy()-side)/2,
+	)
+	cropped := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(cropped, cropped.Bounds(), src, origin, draw.Src)
+
+	if side <= size {
+		return cropped
+	}
+	return downscale(cropped, size, size)
+}
+
+// Fit memperkecil gambar sehingga sisi terpanjangnya paling besar maxSide piksel dengan
+// mempertahankan rasio aspek. Gambar yang lebih kecil dari maxSide tidak diperbesar.
+func Fit(src image.Image, maxSide int) *image.RGBA {
+	rgba := toRGBA(src)
+	width, height := rgba.Bounds().Dx(), rgba.Bounds().Dy()
+	if width <= maxSide && height <= maxSide {
+		return rgba
+	}
+
+	if width >= height {
+		return downscale(rgba, maxSide, max(height*maxSide/width, 1))
+	}
+	return downscale(rgba, max(width*maxSide/height, 1), maxSide)
+}
+
+// toRGBA menyalin gambar ke *image.RGBA dengan titik awal (0, 0)
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
+
+// downscale memperkecil gambar menjadi width x height piksel dengan merata-ratakan
+// piksel sumber (area averaging). Ukuran tujuan tidak boleh lebih besar dari sumber.
+func downscale(src *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcHeight/height, (y+1)*srcHeight/height
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcWidth/width, (x+1)*srcWidth/width
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					px := row[sx*4 : sx*4+4]
+					r += uint32(px[0])
+					g += uint32(px[1])
+					b += uint32(px[2])
+					a += uint32(px[3])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// Encode menyimpan gambar sebagai JPEG jika format sumbernya JPEG, selain itu sebagai PNG
+// agar transparansi tidak hilang. Mengembalikan isi file, ekstensi dan content type.
+func Encode(img image.Image, sourceFormat string) ([]byte, string, string, error) {
+	var buf bytes.Buffer
+	if sourceFormat == "jpeg" {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, "", "", fmt.Errorf("gagal menyimpan gambar JPEG: %w", err)
+		}
+		return buf.Bytes(), ".jpg", "image/jpeg", nil
+	}
+
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", "", fmt.Errorf("gagal menyimpan gambar PNG: %w", err)
+	}
+	return buf.Bytes(), ".png", "image/png", nil
+}
@@ -0,0 +1,143 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		maxSide       int
+		wantW, wantH  int
+	}{
+		{"landscape", 400, 200, 100, 100, 50},
+		{"portrait", 200, 400, 100, 50, 100},
+		{"persegi", 300, 300, 100, 100, 100},
+		{"lebih kecil tidak diperbesar", 80, 40, 100, 80, 40},
+		{"sama dengan batas", 100, 60, 100, 100, 60},
+		{"sangat lebar", 1000, 1, 100, 100, 1},
+		{"sangat tinggi", 1, 1000, 100, 1, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Fit(image.NewRGBA(image.Rect(0, 0, tt.width, tt.height)), tt.maxSide)
+			if got.Bounds().Dx() != tt.wantW || got.Bounds().Dy() != tt.wantH {
+				t.Errorf("Fit = %dx%d, want %dx%d", got.Bounds().Dx(), got.Bounds().Dy(), tt.wantW, tt.wantH)
+			}
+			if got.Bounds().Min != (image.Point{}) {
+				t.Errorf("Fit bounds dimulai dari %v, want (0,0)", got.Bounds().Min)
+			}
+		})
+	}
+}
+
+func TestFitNonZeroOrigin(t *testing.T) {
+	// Sub-image memiliki bounds yang tidak dimulai dari (0, 0)
+	src := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	src.SetRGBA(10, 10, color.RGBA{255, 0, 0, 255})
+	sub := src.SubImage(image.Rect(10, 10, 14, 12))
+
+	got := Fit(sub, 100)
+	if got.Bounds() != image.Rect(0, 0, 4, 2) {
+		t.Fatalf("Fit bounds = %v, want (0,0)-(4,2)", got.Bounds())
+	}
+	if c := got.RGBAAt(0, 0); c != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("piksel kiri atas = %v, want merah", c)
+	}
+}
+
+func TestDownscaleAveragesPixels(t *testing.T) {
+	// Papan catur hitam putih 4x2 menjadi 2x1 abu-abu
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			if (x+y)%2 == 0 {
+				src.SetRGBA(x, y, color.RGBA{255, 255, 255, 255})
+			} else {
+				src.SetRGBA(x, y, color.RGBA{0, 0, 0, 255})
+			}
+		}
+	}
+
+	got := downscale(src, 2, 1)
+	for x := 0; x < 2; x++ {
+		if c := got.RGBAAt(x, 0); c != (color.RGBA{127, 127, 127, 255}) {
+			t.Errorf("piksel (%d,0) = %v, want abu-abu 127", x, c)
+		}
+	}
+}
+
+func TestSquareThumbnail(t *testing.T) {
+	got := SquareThumbnail(image.NewRGBA(image.Rect(0, 0, 300, 100)), 50)
+	if got.Bounds() != image.Rect(0, 0, 50, 50) {
+		t.Errorf("SquareThumbnail bounds = %v, want 50x50", got.Bounds())
+	}
+
+	small := SquareThumbnail(image.NewRGBA(image.Rect(0, 0, 30, 40)), 50)
+	if small.Bounds() != image.Rect(0, 0, 30, 30) {
+		t.Errorf("SquareThumbnail gambar kecil = %v, want 30x30", small.Bounds())
+	}
+}
+
+// pngWithSize membuat PNG 1x1 lalu mengubah ukuran di header IHDR menjadi width x height
+func pngWithSize(t *testing.T, width, height uint32) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatalf("encode PNG: %v", err)
+	}
+	data := buf.Bytes()
+
+	// Signature 8 byte, lalu panjang chunk (4), tipe "IHDR" (4), data IHDR (13) dan CRC (4)
+	ihdr := data[12 : 12+4+13]
+	binary.BigEndian.PutUint32(ihdr[4:], width)
+	binary.BigEndian.PutUint32(ihdr[8:], height)
+	binary.BigEndian.PutUint32(data[12+4+13:], crc32.ChecksumIEEE(ihdr))
+	return data
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(pngWithSize(t, 100, 100)); err != nil {
+		t.Errorf("Validate 100x100: %v", err)
+	}
+	if err := Validate(pngWithSize(t, 10000, 10000)); err == nil {
+		t.Error("Validate 10000x10000 seharusnya ditolak")
+	}
+	if err := Validate([]byte("bukan gambar")); err == nil {
+		t.Error("Validate data acak seharusnya ditolak")
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, image.NewRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatalf("encode PNG: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"jpeg", []byte{0xFF, 0xD8, 0xFF, 0xE0}, "image/jpeg"},
+		{"png", pngData.Bytes(), "image/png"},
+		{"gif87a", []byte("GIF87a...."), "image/gif"},
+		{"gif89a", []byte("GIF89a...."), "image/gif"},
+		{"webp", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), "image/webp"},
+		{"riff bukan webp", []byte("RIFF\x00\x00\x00\x00WAVEfmt "), "application/octet-stream"},
+		{"html", []byte("<html><script>"), "application/octet-stream"},
+		{"kosong", nil, "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		if got := DetectContentType(tt.data); got != tt.want {
+			t.Errorf("%s: DetectContentType = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+)
+
+// Message adalah email yang akan dikirim
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer adalah interface untuk pengiriman email
+type Mailer interface {
+	// Send mengirim email ke penerima
+	Send(ctx context.Context, msg Message) error
+}
+
+// New membuat Mailer berdasarkan driver pada konfigurasi
+func New(cfg config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.From), nil
+	case "outbox", "":
+		return NewOutboxMailer(cfg.OutboxDir, cfg.From)
+	default:
+		return nil, fmt.Errorf("driver mail tidak dikenal: %s", cfg.Driver)
+	}
+}
@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// OutboxMailer menyimpan email sebagai file .eml di direktori lokal.
+// Berguna untuk development dan pengujian tanpa server SMTP.
+type OutboxMailer struct {
+	dir  string
+	from string
+}
+
+// unsafeFileChars adalah karakter yang tidak dipakai dalam nama file outbox
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+// NewOutboxMailer membuat instance baru OutboxMailer dan memastikan direktori outbox ada
+func NewOutboxMailer(dir, from string) (*OutboxMailer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("gagal membuat direktori outbox: %w", err)
+	}
+	return &OutboxMailer{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+// Send menulis email ke direktori outbox
+func (m *OutboxMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	fileName := fmt.Sprintf("%d_%s.eml", time.Now().UnixNano(), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	if err := os.WriteFile(filepath.Join(m.dir, fileName), buildMessage(m.from, msg), 0644); err != nil {
+		return fmt.Errorf("gagal menulis email ke outbox: %w", err)
+	}
+	return nil
+}
@@ -6,78 +6,217 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
 	"github.com/mfuadfakhruzzaki/jubel/internal/service"
 	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
 )
 
-// AuthMiddleware middleware untuk otentikasi JWT
+// AuthMiddleware middleware untuk otentikasi JWT dan personal access token
 type AuthMiddleware struct {
-	authService service.AuthService
+	authService        service.AuthService
+	accessTokenService service.AccessTokenService
 }
 
 // NewAuthMiddleware membuat instance baru AuthMiddleware
-func NewAuthMiddleware(authService service.AuthService) *AuthMiddleware {
+func NewAuthMiddleware(authService service.AuthService, accessTokenService service.AccessTokenService) *AuthMiddleware {
 	return &AuthMiddleware{
-		authService: authService,
+		authService:        authService,
+		accessTokenService: accessTokenService,
 	}
 }
 
-// VerifyToken digunakan untuk memverifikasi token JWT
+// VerifyToken digunakan untuk memverifikasi token JWT.
+// Personal access token ditolak karena route ini tidak menyatakan scope yang dibutuhkan.
 func (m *AuthMiddleware) VerifyToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Dapatkan token dari header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			utils.ErrorResponse(c, http.StatusUnauthorized, "Token tidak ditemukan", nil)
-			c.Abort()
-			return
+		if m.authenticate(c, "") {
+			c.Next()
 		}
+	}
+}
 
-		// Format token harus "Bearer [token]"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			utils.ErrorResponse(c, http.StatusUnauthorized, "Format token tidak valid", nil)
-			c.Abort()
+// RequireScope digunakan untuk memverifikasi token JWT atau personal access token.
+// Token JWT dari login pengguna memiliki akses penuh, sedangkan personal access token
+// harus memiliki scope yang diminta.
+func (m *AuthMiddleware) RequireScope(scope domain.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.authenticate(c, scope) {
+			c.Next()
+		}
+	}
+}
+
+// OptionalScope sama seperti RequireScope, tetapi request tanpa header Authorization
+// tetap diteruskan sebagai pengunjung anonim. Dipakai untuk endpoint publik yang
+// hasilnya bisa disesuaikan untuk pengguna yang login.
+func (m *AuthMiddleware) OptionalScope(scope domain.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
 			return
 		}
+		if m.authenticate(c, scope) {
+			c.Next()
+		}
+	}
+}
 
-		token := parts[1]
+// authenticate memverifikasi token pada header Authorization dan mengisi data pengguna di context.
+// Personal access token hanya diterima jika scope tidak kosong. Mengembalikan false jika request dihentikan.
+func (m *AuthMiddleware) authenticate(c *gin.Context, scope domain.Scope) bool {
+	// Dapatkan token dari header
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Token tidak ditemukan", nil)
+		c.Abort()
+		return false
+	}
 
-		// Validasi token
-		claims, err := m.authService.ValidateToken(c.Request.Context(), token)
-		if err != nil {
-			utils.ErrorResponse(c, http.StatusUnauthorized, err.Error(), nil)
+	// Format token harus "Bearer [token]"
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Format token tidak valid", nil)
+		c.Abort()
+		return false
+	}
+
+	token := parts[1]
+
+	if strings.HasPrefix(token, domain.AccessTokenPrefix) {
+		return m.authenticateAccessToken(c, token, scope)
+	}
+
+	// Validasi token
+	claims, err := m.authService.ValidateToken(c.Request.Context(), token)
+	if err != nil {
+		abortWithAuthError(c, err)
+		return false
+	}
+
+	// Set user info di context
+	c.Set("userID", claims.UserID)
+	c.Set("userEmail", claims.Email)
+	c.Set("userRole", claims.Role)
+	c.Set("tokenID", claims.ID)
+	c.Set("tokenExpiresAt", claims.ExpiresAt.Time)
+	c.Set("userMFA", claims.MFA)
+	c.Set("sessionID", claims.SessionID)
+	c.Request = c.Request.WithContext(policy.WithPrincipal(c.Request.Context(), policy.Principal{
+		UserID: claims.UserID,
+		Role:   claims.Role,
+		MFA:    claims.MFA,
+	}))
+
+	// Catat aktivitas sesi; kegagalan di sini tidak perlu menggagalkan request
+	_ = m.authService.TouchSession(c.Request.Context(), claims.SessionID, domain.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+
+	return true
+}
+
+// authenticateAccessToken memverifikasi personal access token beserta scope-nya
+func (m *AuthMiddleware) authenticateAccessToken(c *gin.Context, token string, scope domain.Scope) bool {
+	if scope == "" {
+		utils.ErrorResponse(c, http.StatusForbidden, "Akses ditolak: personal access token tidak dapat dipakai untuk endpoint ini", nil)
+		c.Abort()
+		return false
+	}
+
+	accessToken, user, err := m.accessTokenService.Authenticate(c.Request.Context(), token)
+	if err != nil {
+		abortWithAuthError(c, err)
+		return false
+	}
+
+	if !accessToken.HasScope(scope) {
+		utils.ErrorResponse(c, http.StatusForbidden, "Akses ditolak: token tidak memiliki scope "+string(scope), nil)
+		c.Abort()
+		return false
+	}
+
+	// Set user info di context. Personal access token tidak pernah dianggap login dengan 2FA,
+	// sehingga tidak bisa dipakai untuk route admin.
+	c.Set("userID", user.ID)
+	c.Set("userEmail", user.Email)
+	c.Set("userRole", user.Role)
+	c.Set("userMFA", false)
+	c.Set("accessTokenID", accessToken.ID)
+	c.Set("tokenScopes", accessToken.ScopeList())
+	c.Request = c.Request.WithContext(policy.WithPrincipal(c.Request.Context(), policy.Principal{
+		UserID: user.ID,
+		Role:   user.Role,
+	}))
+
+	return true
+}
+
+// abortWithAuthError menghentikan request karena otentikasi gagal.
+// StandardError (misalnya akun ditangguhkan) memakai status code-nya sendiri, selain itu 401.
+func abortWithAuthError(c *gin.Context, err error) {
+	if stdErr, ok := errors.AsStandardError(err); ok {
+		utils.ErrorResponse(c, stdErr.Code, stdErr.Message, nil)
+	} else {
+		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error(), nil)
+	}
+	c.Abort()
+}
+
+// RequirePermission digunakan untuk memeriksa apakah role pengguna memiliki izin tertentu
+// terhadap semua resource (misalnya admin atau moderator)
+func (m *AuthMiddleware) RequirePermission(action policy.Action) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Dapatkan principal dari context
+		principal, exists := policy.FromContext(c.Request.Context())
+		if !exists {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
 			c.Abort()
 			return
 		}
 
-		// Set user info di context
-		c.Set("userID", claims.UserID)
-		c.Set("userEmail", claims.Email)
-		c.Set("userRole", claims.Role)
+		if !policy.Can(principal, action, nil) {
+			// Admin dan moderator wajib login dengan verifikasi dua langkah
+			if principal.Role.RequiresMFA() && !principal.MFA {
+				utils.ErrorResponse(c, http.StatusForbidden, "Akses ditolak: "+string(principal.Role)+" wajib login dengan verifikasi dua langkah", nil)
+				c.Abort()
+				return
+			}
+			utils.ErrorResponse(c, http.StatusForbidden, "Akses ditolak: tidak memiliki izin", nil)
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
 }
 
-// RequireAdmin digunakan untuk memeriksa apakah pengguna adalah admin
-func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
+// RequireVerifiedEmail digunakan untuk memeriksa apakah email pengguna sudah diverifikasi
+func (m *AuthMiddleware) RequireVerifiedEmail() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Dapatkan role dari context
-		role, exists := c.Get("userRole")
+		// Dapatkan user ID dari context
+		userID, exists := c.Get("userID")
 		if !exists {
 			utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
 			c.Abort()
 			return
 		}
 
-		// Cek apakah role adalah admin
-		if role.(domain.Role) != domain.RoleAdmin {
-			utils.ErrorResponse(c, http.StatusForbidden, "Akses ditolak: memerlukan hak admin", nil)
+		verified, err := m.authService.IsEmailVerified(c.Request.Context(), userID.(uint))
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusUnauthorized, err.Error(), nil)
+			c.Abort()
+			return
+		}
+
+		// Cek apakah email sudah diverifikasi
+		if !verified {
+			utils.ErrorResponse(c, http.StatusForbidden, "Akses ditolak: email belum diverifikasi", nil)
 			c.Abort()
 			return
 		}
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
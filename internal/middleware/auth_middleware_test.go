@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+	"github.com/mfuadfakhruzzaki/jubel/internal/service"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+	"gorm.io/gorm"
+)
+
+// fakeAccessTokenRepository menyimpan personal access token di memori. Method yang tidak
+// dipakai diteruskan ke interface nil sehingga panic jika terpanggil.
+type fakeAccessTokenRepository struct {
+	repository.AccessTokenRepository
+	tokens []domain.AccessToken
+}
+
+func (r *fakeAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.AccessToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeAccessTokenRepository) TouchLastUsed(ctx context.Context, id uint, now, staleBefore time.Time) error {
+	return nil
+}
+
+// fakeUserRepository menyimpan pengguna di memori
+type fakeUserRepository struct {
+	repository.UserRepository
+	users map[uint]domain.User
+}
+
+func (r *fakeUserRepository) FindByID(ctx context.Context, id uint) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+// fakeAuthService menerima token JWT yang terdaftar di claims
+type fakeAuthService struct {
+	service.AuthService
+	claims map[string]utils.JWTClaims
+}
+
+func (s *fakeAuthService) ValidateToken(ctx context.Context, token string) (*utils.JWTClaims, error) {
+	claims, ok := s.claims[token]
+	if !ok {
+		return nil, jwt.ErrTokenMalformed
+	}
+	return &claims, nil
+}
+
+func (s *fakeAuthService) TouchSession(ctx context.Context, sessionID string, client domain.ClientInfo) error {
+	return nil
+}
+
+func TestAccessTokenAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const (
+		userID  uint = 1
+		adminID uint = 2
+	)
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	users := &fakeUserRepository{users: map[uint]domain.User{
+		userID:  {ID: userID, Email: "budi@example.com", Role: domain.RoleUser},
+		adminID: {ID: adminID, Email: "admin@example.com", Role: domain.RoleAdmin},
+	}}
+	accessTokens := &fakeAccessTokenRepository{}
+	addToken := func(raw string, owner uint, scopes string, expiresAt, revokedAt *time.Time) string {
+		raw = domain.AccessTokenPrefix + raw
+		accessTokens.tokens = append(accessTokens.tokens, domain.AccessToken{
+			ID:         uint(len(accessTokens.tokens) + 1),
+			PenggunaID: owner,
+			TokenHash:  utils.HashToken(raw),
+			Scopes:     scopes,
+			ExpiresAt:  expiresAt,
+			RevokedAt:  revokedAt,
+		})
+		return raw
+	}
+	userToken := addToken("pengguna", userID, "items:read items:write", &future, nil)
+	adminToken := addToken("admin", adminID, "items:read items:write", nil, nil)
+	revokedToken := addToken("dicabut", userID, "items:read", nil, &past)
+	expiredToken := addToken("kedaluwarsa", userID, "items:read", &past, nil)
+
+	// Token JWT admin yang login dengan 2FA sebagai pembanding untuk route admin
+	auth := &fakeAuthService{claims: map[string]utils.JWTClaims{
+		"jwt-admin": {
+			UserID:           adminID,
+			Role:             domain.RoleAdmin,
+			MFA:              true,
+			RegisteredClaims: jwt.RegisteredClaims{ID: "jti-admin", ExpiresAt: jwt.NewNumericDate(future)},
+		},
+	}}
+	m := NewAuthMiddleware(auth, service.NewAccessTokenService(accessTokens, users))
+
+	var got *policy.Principal
+	handler := func(c *gin.Context) {
+		principal, _ := policy.FromContext(c.Request.Context())
+		got = &principal
+		c.Status(http.StatusOK)
+	}
+	router := gin.New()
+	router.GET("/items", m.RequireScope(domain.ScopeItemsRead), handler)
+	router.POST("/chats", m.RequireScope(domain.ScopeChatsWrite), handler)
+	router.GET("/auth/me", m.VerifyToken(), handler)
+	router.DELETE("/admin/items/1", m.RequireScope(domain.ScopeItemsWrite), m.RequirePermission(policy.ItemDelete), handler)
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		token         string
+		wantStatus    int
+		wantPrincipal *policy.Principal
+	}{
+		{"scope cocok", http.MethodGet, "/items", userToken, http.StatusOK, &policy.Principal{UserID: userID, Role: domain.RoleUser}},
+		{"scope tidak cocok", http.MethodPost, "/chats", userToken, http.StatusForbidden, nil},
+		{"route tanpa scope", http.MethodGet, "/auth/me", userToken, http.StatusForbidden, nil},
+		{"token dicabut", http.MethodGet, "/items", revokedToken, http.StatusUnauthorized, nil},
+		{"token kedaluwarsa", http.MethodGet, "/items", expiredToken, http.StatusUnauthorized, nil},
+		{"token tidak dikenal", http.MethodGet, "/items", domain.AccessTokenPrefix + "tidak-dikenal", http.StatusUnauthorized, nil},
+		{"token admin di route biasa", http.MethodGet, "/items", adminToken, http.StatusOK, &policy.Principal{UserID: adminID, Role: domain.RoleAdmin}},
+		{"token admin tidak mendapat izin admin", http.MethodDelete, "/admin/items/1", adminToken, http.StatusForbidden, nil},
+		{"JWT admin dengan 2FA mendapat izin admin", http.MethodDelete, "/admin/items/1", "jwt-admin", http.StatusOK, &policy.Principal{UserID: adminID, Role: domain.RoleAdmin, MFA: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			switch {
+			case tt.wantPrincipal == nil && got != nil:
+				t.Errorf("handler dipanggil dengan principal %+v", *got)
+			case tt.wantPrincipal != nil && got == nil:
+				t.Errorf("handler tidak dipanggil, want principal %+v", *tt.wantPrincipal)
+			case tt.wantPrincipal != nil && *got != *tt.wantPrincipal:
+				t.Errorf("principal = %+v, want %+v", *got, *tt.wantPrincipal)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
@@ -42,6 +43,11 @@ func ErrorMiddleware(logger zerolog.Logger) gin.HandlerFunc {
 					errorLogger.Error().Msg(stdErr.Message)
 				}
 
+				// Tell the client when it may retry
+				if retryAfter, ok := stdErr.RetryAfter(); ok {
+					c.Header("Retry-After", strconv.Itoa(retryAfter))
+				}
+
 				// Respond with JSON
 				c.JSON(stdErr.Code, gin.H{
 					"error": gin.H{
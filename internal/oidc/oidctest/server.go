@@ -0,0 +1,273 @@
+// Package oidctest berisi penyedia identitas OpenID Connect tiruan untuk test,
+// sehingga alur login OIDC dapat diuji tanpa penyedia identitas sungguhan
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Identity adalah akun pengguna di penyedia identitas tiruan
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// authorization adalah authorization code yang belum ditukar
+type authorization struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	identity      Identity
+}
+
+// Server adalah penyedia identitas tiruan yang menyajikan discovery, JWKS dan token endpoint.
+// Authorization endpoint tidak disajikan; test memanggil Authorize dengan URL login dari klien.
+type Server struct {
+	*httptest.Server
+
+	// ClientID adalah client yang terdaftar di penyedia
+	ClientID string
+	// Key dan KeyID adalah kunci penandatangan ID token yang dipublikasikan di JWKS
+	Key   *rsa.PrivateKey
+	KeyID string
+
+	// TokenClaims, jika diisi, dipanggil untuk mengubah claim ID token sebelum ditandatangani
+	TokenClaims func(claims jwt.MapClaims)
+	// SigningKey, jika diisi, dipakai menandatangani ID token menggantikan Key
+	SigningKey *rsa.PrivateKey
+
+	mu            sync.Mutex
+	codes         map[string]authorization
+	jwksRequests  int
+	tokenRequests int
+}
+
+// NewServer menjalankan penyedia identitas tiruan yang ditutup otomatis di akhir test
+func NewServer(t testing.TB, clientID string) *Server {
+	t.Helper()
+
+	s := &Server{
+		ClientID: clientID,
+		Key:      NewKey(t),
+		KeyID:    "kunci-1",
+		codes:    make(map[string]authorization),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("GET /jwks", s.handleJWKS)
+	mux.HandleFunc("POST /token", s.handleToken)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// NewKey membuat kunci RSA untuk menandatangani ID token
+func NewKey(t testing.TB) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("gagal membuat kunci RSA: %v", err)
+	}
+	return key
+}
+
+// Issuer mengembalikan issuer penyedia
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// Authorize mensimulasikan pengguna login di penyedia dari URL login yang dibuat klien.
+// Mengembalikan authorization code dan state yang dikirim kembali ke redirect URI.
+func (s *Server) Authorize(t testing.TB, authURL string, identity Identity) (code, state string) {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("URL login tidak valid: %v", err)
+	}
+	query := u.Query()
+	if got := query.Get("response_type"); got != "code" {
+		t.Fatalf("response_type = %q, want code", got)
+	}
+	if got := query.Get("client_id"); got != s.ClientID {
+		t.Fatalf("client_id = %q, want %q", got, s.ClientID)
+	}
+	if got := query.Get("code_challenge_method"); got != "S256" {
+		t.Fatalf("code_challenge_method = %q, want S256", got)
+	}
+	if query.Get("code_challenge") == "" || query.Get("state") == "" || query.Get("nonce") == "" {
+		t.Fatalf("URL login tidak berisi code_challenge, state dan nonce: %s", authURL)
+	}
+
+	code = randomString(t)
+	s.mu.Lock()
+	s.codes[code] = authorization{
+		clientID:      query.Get("client_id"),
+		redirectURI:   query.Get("redirect_uri"),
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		identity:      identity,
+	}
+	s.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+// SignIDToken menandatangani claim sebagai ID token dengan Key dan KeyID
+func (s *Server) SignIDToken(t testing.TB, claims jwt.MapClaims) string {
+	t.Helper()
+	return SignToken(t, s.Key, s.KeyID, claims)
+}
+
+// SignToken menandatangani claim dengan RS256 memakai key, header kid diisi jika keyID tidak kosong
+func SignToken(t testing.TB, key *rsa.PrivateKey, keyID string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if keyID != "" {
+		token.Header["kid"] = keyID
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("gagal menandatangani token: %v", err)
+	}
+	return signed
+}
+
+// Claims membuat claim ID token yang valid untuk identity dan nonce
+func (s *Server) Claims(identity Identity, nonce string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            s.Issuer(),
+		"aud":            s.ClientID,
+		"sub":            identity.Subject,
+		"email":          identity.Email,
+		"email_verified": identity.EmailVerified,
+		"name":           identity.Name,
+		"nonce":          nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	}
+}
+
+// JWKSRequests mengembalikan jumlah request ke JWKS endpoint
+func (s *Server) JWKSRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jwksRequests
+}
+
+// TokenRequests mengembalikan jumlah request ke token endpoint
+func (s *Server) TokenRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokenRequests
+}
+
+// handleDiscovery menyajikan dokumen /.well-known/openid-configuration
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 s.Issuer(),
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+// handleJWKS menyajikan kunci publik penandatangan ID token
+func (s *Server) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.jwksRequests++
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": s.KeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(s.Key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.Key.E)).Bytes()),
+		}},
+	})
+}
+
+// handleToken menukar authorization code dengan ID token setelah memeriksa code_verifier PKCE.
+// Setiap code hanya dapat ditukar sekali.
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.tokenRequests++
+	auth, ok := s.codes[r.PostFormValue("code")]
+	delete(s.codes, r.PostFormValue("code"))
+	s.mu.Unlock()
+
+	if r.PostFormValue("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+	if !ok || auth.clientID != r.PostFormValue("client_id") || auth.redirectURI != r.PostFormValue("redirect_uri") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != auth.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "code_verifier tidak cocok"})
+		return
+	}
+
+	claims := s.Claims(auth.identity, auth.nonce)
+	if s.TokenClaims != nil {
+		s.TokenClaims(claims)
+	}
+	key := s.Key
+	if s.SigningKey != nil {
+		key = s.SigningKey
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.KeyID
+	signed, err := token.SignedString(key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": "akses",
+		"token_type":   "Bearer",
+		"id_token":     signed,
+	})
+}
+
+// writeJSON menulis respons JSON
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// randomString membuat string acak untuk authorization code
+func randomString(t testing.TB) string {
+	t.Helper()
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		t.Fatalf("gagal membuat string acak: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,309 @@
+package oidc_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+	"github.com/mfuadfakhruzzaki/jubel/internal/oidc"
+	"github.com/mfuadfakhruzzaki/jubel/internal/oidc/oidctest"
+)
+
+const testClientID = "jubel-test"
+
+// newTestProvider menjalankan penyedia identitas tiruan dan membuat klien Provider untuknya
+func newTestProvider(t *testing.T) (*oidctest.Server, *oidc.Provider) {
+	t.Helper()
+	server := oidctest.NewServer(t, testClientID)
+	provider := oidc.NewProvider(config.OIDCProviderConfig{
+		Name:        "tiruan",
+		Issuer:      server.Issuer(),
+		ClientID:    testClientID,
+		RedirectURL: "http://localhost/api/v1/auth/oidc/tiruan/callback",
+		Scopes:      []string{"openid", "email", "profile"},
+	}, server.Client())
+	return server, provider
+}
+
+var testIdentity = oidctest.Identity{
+	Subject:       "sub-123",
+	Email:         " Budi@Example.com ",
+	EmailVerified: true,
+	Name:          "Budi",
+}
+
+func TestProviderExchangeWithPKCE(t *testing.T) {
+	ctx := context.Background()
+	server, provider := newTestProvider(t)
+
+	verifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		t.Fatalf("GenerateCodeVerifier: %v", err)
+	}
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	if !strings.HasPrefix(authURL, server.URL+"/authorize?") {
+		t.Errorf("AuthCodeURL = %s, want authorization endpoint penyedia", authURL)
+	}
+
+	code, state := server.Authorize(t, authURL, testIdentity)
+	if state != "state-1" {
+		t.Errorf("state = %q, want state-1", state)
+	}
+
+	rawIDToken, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	claims, err := provider.VerifyIDToken(ctx, rawIDToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	want := oidc.IDTokenClaims{Subject: "sub-123", Email: "budi@example.com", EmailVerified: true, Name: "Budi"}
+	if *claims != want {
+		t.Errorf("claims = %+v, want %+v", *claims, want)
+	}
+
+	// Authorization code hanya dapat ditukar sekali
+	if _, err := provider.Exchange(ctx, code, verifier); err == nil {
+		t.Error("penukaran ulang authorization code seharusnya ditolak")
+	}
+}
+
+func TestProviderExchangeRejectsWrongVerifier(t *testing.T) {
+	ctx := context.Background()
+	server, provider := newTestProvider(t)
+
+	verifier, _ := oidc.GenerateCodeVerifier()
+	otherVerifier, _ := oidc.GenerateCodeVerifier()
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	code, _ := server.Authorize(t, authURL, testIdentity)
+
+	if _, err := provider.Exchange(ctx, code, otherVerifier); err == nil {
+		t.Error("Exchange dengan code_verifier lain seharusnya ditolak")
+	}
+	if _, err := provider.Exchange(ctx, "kode-tidak-dikenal", verifier); err == nil {
+		t.Error("Exchange dengan authorization code tidak dikenal seharusnya ditolak")
+	}
+}
+
+func TestProviderVerifyIDToken(t *testing.T) {
+	ctx := context.Background()
+	server, provider := newTestProvider(t)
+	foreignKey := oidctest.NewKey(t)
+
+	// claims membuat claim valid lalu menerapkan perubahan
+	claims := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		c := server.Claims(testIdentity, "nonce")
+		if change != nil {
+			change(c)
+		}
+		return c
+	}
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"valid", server.SignIDToken(t, claims(nil)), false},
+		{"tanpa kid dengan satu kunci", oidctest.SignToken(t, server.Key, "", claims(nil)), false},
+		{"email_verified berupa string", server.SignIDToken(t, claims(func(c jwt.MapClaims) { c["email_verified"] = "true" })), false},
+		{"audience ganda dengan azp cocok", server.SignIDToken(t, claims(func(c jwt.MapClaims) {
+			c["aud"] = []string{testClientID, "klien-lain"}
+			c["azp"] = testClientID
+		})), false},
+		{"exp dalam toleransi jam", server.SignIDToken(t, claims(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-30 * time.Second).Unix() })), false},
+
+		{"tanda tangan kunci lain", oidctest.SignToken(t, foreignKey, server.KeyID, claims(nil)), true},
+		{"kid tidak dikenal", oidctest.SignToken(t, server.Key, "kunci-lain", claims(nil)), true},
+		{"payload diubah", tamperPayload(t, server.SignIDToken(t, claims(nil))), true},
+		{"algoritma none", noneToken(t, claims(nil)), true},
+		{"HS256 dengan modulus sebagai rahasia", hmacToken(t, server.Key.N.Bytes(), claims(nil)), true},
+		{"issuer lain", server.SignIDToken(t, claims(func(c jwt.MapClaims) { c["iss"] = "https://issuer-lain.example.com" })), true},
+		{"audience lain", server.SignIDToken(t, claims(func(c jwt.MapClaims) { c["aud"] = "klien-lain" })), true},
+		{"audience ganda tanpa azp", server.SignIDToken(t, claims(func(c jwt.MapClaims) { c["aud"] = []string{testClientID, "klien-lain"} })), true},
+		{"audience ganda dengan azp lain", server.SignIDToken(t, claims(func(c jwt.MapClaims) {
+			c["aud"] = []string{testClientID, "klien-lain"}
+			c["azp"] = "klien-lain"
+		})), true},
+		{"nonce lain", server.SignIDToken(t, claims(func(c jwt.MapClaims) { c["nonce"] = "nonce-lain" })), true},
+		{"tanpa nonce", server.SignIDToken(t, claims(func(c jwt.MapClaims) { delete(c, "nonce") })), true},
+		{"kedaluwarsa", server.SignIDToken(t, claims(func(c jwt.MapClaims) { c["exp"] = past.Unix() })), true},
+		{"tanpa exp", server.SignIDToken(t, claims(func(c jwt.MapClaims) { delete(c, "exp") })), true},
+		{"iat di masa depan", server.SignIDToken(t, claims(func(c jwt.MapClaims) { c["iat"] = time.Now().Add(time.Hour).Unix() })), true},
+		{"tanpa subject", server.SignIDToken(t, claims(func(c jwt.MapClaims) { delete(c, "sub") })), true},
+		{"bukan JWT", "bukan.jwt", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.VerifyIDToken(ctx, tt.token, "nonce")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyIDToken error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProviderRejectsTokenFromExchangeWithWrongClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(c jwt.MapClaims)
+	}{
+		{"issuer lain", func(c jwt.MapClaims) { c["iss"] = "https://issuer-lain.example.com" }},
+		{"audience lain", func(c jwt.MapClaims) { c["aud"] = "klien-lain" }},
+		{"nonce lain", func(c jwt.MapClaims) { c["nonce"] = "nonce-lain" }},
+		{"kedaluwarsa", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server, provider := newTestProvider(t)
+			server.TokenClaims = tt.change
+
+			verifier, _ := oidc.GenerateCodeVerifier()
+			authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+			if err != nil {
+				t.Fatalf("AuthCodeURL: %v", err)
+			}
+			code, _ := server.Authorize(t, authURL, testIdentity)
+			rawIDToken, err := provider.Exchange(ctx, code, verifier)
+			if err != nil {
+				t.Fatalf("Exchange: %v", err)
+			}
+			if _, err := provider.VerifyIDToken(ctx, rawIDToken, "nonce"); err == nil {
+				t.Error("VerifyIDToken seharusnya menolak token")
+			}
+		})
+	}
+}
+
+func TestProviderJWKSRefreshIsRateLimited(t *testing.T) {
+	ctx := context.Background()
+	server, provider := newTestProvider(t)
+
+	valid := server.SignIDToken(t, server.Claims(testIdentity, "nonce"))
+	if _, err := provider.VerifyIDToken(ctx, valid, "nonce"); err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	if got := server.JWKSRequests(); got != 1 {
+		t.Fatalf("JWKS diambil %d kali, want 1", got)
+	}
+
+	// Kid tidak dikenal memicu pengambilan ulang JWKS paling banyak sekali per keyRefreshInterval
+	unknown := oidctest.SignToken(t, server.Key, "kunci-baru", server.Claims(testIdentity, "nonce"))
+	for i := 0; i < 3; i++ {
+		if _, err := provider.VerifyIDToken(ctx, unknown, "nonce"); err == nil {
+			t.Fatal("kid tidak dikenal seharusnya ditolak")
+		}
+		if _, err := provider.VerifyIDToken(ctx, valid, "nonce"); err != nil {
+			t.Fatalf("VerifyIDToken kunci yang dikenal: %v", err)
+		}
+	}
+	if got := server.JWKSRequests(); got != 1 {
+		t.Errorf("JWKS diambil %d kali, want 1", got)
+	}
+}
+
+func TestProviderDiscoveryIssuerMismatch(t *testing.T) {
+	server := oidctest.NewServer(t, testClientID)
+	provider := oidc.NewProvider(config.OIDCProviderConfig{
+		Name:     "tiruan",
+		Issuer:   server.Issuer() + "/tenant",
+		ClientID: testClientID,
+	}, server.Client())
+
+	// Dokumen discovery tidak tersedia di issuer yang salah
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); err == nil {
+		t.Error("AuthCodeURL seharusnya gagal jika discovery tidak tersedia")
+	}
+
+	mismatch := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 "https://issuer-lain.example.com",
+			"authorization_endpoint": server.URL + "/authorize",
+			"token_endpoint":         server.URL + "/token",
+			"jwks_uri":               server.URL + "/jwks",
+		})
+	}))
+	defer mismatch.Close()
+
+	provider = oidc.NewProvider(config.OIDCProviderConfig{
+		Name:     "tiruan",
+		Issuer:   mismatch.URL,
+		ClientID: testClientID,
+	}, mismatch.Client())
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); err == nil {
+		t.Error("AuthCodeURL seharusnya gagal jika issuer discovery tidak cocok")
+	}
+}
+
+func TestCodeChallengeS256(t *testing.T) {
+	// Contoh dari RFC 7636 Appendix B
+	const verifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	const want = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	if got := oidc.CodeChallengeS256(verifier); got != want {
+		t.Errorf("CodeChallengeS256 = %q, want %q", got, want)
+	}
+
+	first, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		t.Fatalf("GenerateCodeVerifier: %v", err)
+	}
+	second, _ := oidc.GenerateCodeVerifier()
+	if first == second {
+		t.Error("GenerateCodeVerifier menghasilkan nilai yang sama dua kali")
+	}
+	// RFC 7636: panjang code_verifier 43 sampai 128 karakter
+	if len(first) < 43 || len(first) > 128 {
+		t.Errorf("panjang code_verifier = %d, want 43..128", len(first))
+	}
+}
+
+// tamperPayload mengganti payload token tanpa menandatangani ulang
+func tamperPayload(t *testing.T, token string) string {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	payload, err := jwt.NewParser().DecodeSegment(parts[1])
+	if err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	tampered := strings.Replace(string(payload), `"sub-123"`, `"sub-999"`, 1)
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(tampered))
+	return strings.Join(parts, ".")
+}
+
+// noneToken membuat token tanpa tanda tangan
+func noneToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("gagal membuat token none: %v", err)
+	}
+	return signed
+}
+
+// hmacToken membuat token HS256, meniru serangan pertukaran algoritma dengan kunci publik sebagai rahasia
+func hmacToken(t *testing.T, secret []byte, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = "kunci-1"
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		t.Fatalf("gagal membuat token HS256: %v", err)
+	}
+	return signed
+}
@@ -0,0 +1,147 @@
+package policy
+
+import (
+	"errors"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+)
+
+// ErrForbidden dikembalikan jika principal tidak memiliki izin untuk suatu action
+var ErrForbidden = errors.New("akses ditolak: tidak memiliki izin")
+
+// Action adalah nama izin yang diperiksa oleh policy
+type Action string
+
+const (
+	// Izin barang
+	ItemUpdate       Action = "item:update"
+	ItemUpdateStatus Action = "item:update_status"
+	ItemUploadImage  Action = "item:upload_image"
+	ItemDelete       Action = "item:delete"
+
+	// Izin transaksi
+	TransactionListAll      Action = "transaction:list_all"
+	TransactionRead         Action = "transaction:read"
+	TransactionUpdateStatus Action = "transaction:update_status"
+	TransactionDelete       Action = "transaction:delete"
+
+	// Izin chat
+	ChatRead       Action = "chat:read"
+	ChatListByItem Action = "chat:list_by_item"
+	ChatMarkRead   Action = "chat:mark_read"
+	ChatDelete     Action = "chat:delete"
+
+	// Izin pengguna
+	UserListAll     Action = "user:list_all"
+	UserReadPrivate Action = "user:read_private"
+	UserUpdate      Action = "user:update"
+	UserDelete      Action = "user:delete"
+
+	// Izin ulasan
+	ReviewUpdate   Action = "review:update"
+	ReviewReply    Action = "review:reply"
+	ReviewModerate Action = "review:moderate"
+
+	// Izin laporan
+	ReportModerate Action = "report:moderate"
+
+	// Izin manajemen pengguna oleh admin
+	UserChangeRole Action = "user:change_role"
+	UserSuspend    Action = "user:suspend"
+	AdminLogRead   Action = "admin_log:read"
+)
+
+// roleGrants berisi izin yang dimiliki role terhadap semua resource, tanpa melihat kepemilikan
+var roleGrants = map[domain.Role]map[Action]bool{
+	domain.RoleAdmin: {
+		ItemDelete:         true,
+		TransactionListAll: true,
+		TransactionRead:    true,
+		ChatRead:           true,
+		ChatDelete:         true,
+		UserListAll:        true,
+		UserReadPrivate:    true,
+		UserDelete:         true,
+		UserChangeRole:     true,
+		UserSuspend:        true,
+		AdminLogRead:       true,
+		ReviewModerate:     true,
+		ReportModerate:     true,
+	},
+	domain.RoleModerator: {
+		ItemDelete:     true,
+		ChatRead:       true,
+		ChatDelete:     true,
+		ReviewModerate: true,
+		ReportModerate: true,
+	},
+}
+
+// Can memeriksa apakah principal boleh melakukan action terhadap resource.
+// Resource berupa *domain.Item, *domain.Transaction, *domain.Chat, *domain.User atau *domain.Review.
+// Jika resource nil, hanya izin dari role yang diperiksa.
+func Can(principal Principal, action Action, resource interface{}) bool {
+	if principal.hasRoleGrants() && roleGrants[principal.Role][action] {
+		return true
+	}
+	if principal.UserID == 0 {
+		return false
+	}
+	return isOwnerAllowed(principal.UserID, action, resource)
+}
+
+// isOwnerAllowed berisi aturan untuk pemilik atau pihak yang terlibat dalam resource.
+// Pointer resource yang nil tidak dimiliki siapa pun.
+func isOwnerAllowed(userID uint, action Action, resource interface{}) bool {
+	switch r := resource.(type) {
+	case *domain.Item:
+		if r == nil {
+			return false
+		}
+		switch action {
+		case ItemUpdate, ItemUpdateStatus, ItemUploadImage, ItemDelete, ChatListByItem:
+			return r.PenjualID == userID
+		}
+	case *domain.Transaction:
+		if r == nil {
+			return false
+		}
+		switch action {
+		case TransactionRead, TransactionUpdateStatus:
+			return r.PembeliID == userID || r.Barang.PenjualID == userID
+		case TransactionDelete:
+			return r.Barang.PenjualID == userID
+		}
+	case *domain.Chat:
+		if r == nil {
+			return false
+		}
+		switch action {
+		case ChatRead:
+			return r.PengirimID == userID || r.PenerimaID == userID
+		case ChatMarkRead:
+			return r.PenerimaID == userID
+		case ChatDelete:
+			return r.PengirimID == userID
+		}
+	case *domain.Review:
+		if r == nil {
+			return false
+		}
+		switch action {
+		case ReviewUpdate:
+			return r.PenulisID == userID
+		case ReviewReply:
+			return r.TargetID == userID
+		}
+	case *domain.User:
+		if r == nil {
+			return false
+		}
+		switch action {
+		case UserReadPrivate, UserUpdate, UserDelete:
+			return r.ID == userID
+		}
+	}
+	return false
+}
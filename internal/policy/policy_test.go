@@ -0,0 +1,205 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+)
+
+// allActions berisi semua action yang dikenal policy
+var allActions = []Action{
+	ItemUpdate, ItemUpdateStatus, ItemUploadImage, ItemDelete,
+	TransactionListAll, TransactionRead, TransactionUpdateStatus, TransactionDelete,
+	ChatRead, ChatListByItem, ChatMarkRead, ChatDelete,
+	UserListAll, UserReadPrivate, UserUpdate, UserDelete,
+	ReviewUpdate, ReviewReply, ReviewModerate,
+	ReportModerate,
+	UserChangeRole, UserSuspend, AdminLogRead,
+}
+
+// adminActions dan moderatorActions adalah izin role yang tidak bergantung pada kepemilikan
+var (
+	adminActions = []Action{
+		ItemDelete, TransactionListAll, TransactionRead, ChatRead, ChatDelete,
+		UserListAll, UserReadPrivate, UserDelete, UserChangeRole, UserSuspend, AdminLogRead,
+		ReviewModerate, ReportModerate,
+	}
+	moderatorActions = []Action{ItemDelete, ChatRead, ChatDelete, ReviewModerate, ReportModerate}
+)
+
+// ID pengguna yang dipakai di resource uji. Admin dan moderator tidak terlibat di resource mana pun.
+const (
+	firstPartyID  uint = 1
+	secondPartyID uint = 2
+	otherUserID   uint = 99
+	adminID       uint = 50
+	moderatorID   uint = 51
+)
+
+func TestCan(t *testing.T) {
+	resources := []struct {
+		name     string
+		resource interface{}
+		// parties berisi pihak yang terlibat di resource beserta izin yang didapat dari kepemilikan
+		parties map[string]struct {
+			userID  uint
+			actions []Action
+		}
+	}{
+		{
+			name:     "barang",
+			resource: &domain.Item{ID: 10, PenjualID: firstPartyID},
+			parties: map[string]struct {
+				userID  uint
+				actions []Action
+			}{
+				"penjual": {firstPartyID, []Action{ItemUpdate, ItemUpdateStatus, ItemUploadImage, ItemDelete, ChatListByItem}},
+			},
+		},
+		{
+			name:     "transaksi",
+			resource: &domain.Transaction{ID: 20, PembeliID: firstPartyID, Barang: domain.Item{PenjualID: secondPartyID}},
+			parties: map[string]struct {
+				userID  uint
+				actions []Action
+			}{
+				"pembeli": {firstPartyID, []Action{TransactionRead, TransactionUpdateStatus}},
+				"penjual": {secondPartyID, []Action{TransactionRead, TransactionUpdateStatus, TransactionDelete}},
+			},
+		},
+		{
+			name:     "chat",
+			resource: &domain.Chat{ID: 30, PengirimID: firstPartyID, PenerimaID: secondPartyID},
+			parties: map[string]struct {
+				userID  uint
+				actions []Action
+			}{
+				"pengirim": {firstPartyID, []Action{ChatRead, ChatDelete}},
+				"penerima": {secondPartyID, []Action{ChatRead, ChatMarkRead}},
+			},
+		},
+		{
+			name:     "ulasan",
+			resource: &domain.Review{ID: 40, PenulisID: firstPartyID, TargetID: secondPartyID},
+			parties: map[string]struct {
+				userID  uint
+				actions []Action
+			}{
+				"penulis": {firstPartyID, []Action{ReviewUpdate}},
+				"target":  {secondPartyID, []Action{ReviewReply}},
+			},
+		},
+		{
+			name:     "pengguna",
+			resource: &domain.User{ID: firstPartyID},
+			parties: map[string]struct {
+				userID  uint
+				actions []Action
+			}{
+				"diri sendiri": {firstPartyID, []Action{UserReadPrivate, UserUpdate, UserDelete}},
+			},
+		},
+		{name: "resource nil", resource: nil},
+		{name: "*domain.Item nil", resource: (*domain.Item)(nil)},
+		{name: "*domain.Transaction nil", resource: (*domain.Transaction)(nil)},
+		{name: "*domain.Chat nil", resource: (*domain.Chat)(nil)},
+		{name: "*domain.Review nil", resource: (*domain.Review)(nil)},
+		{name: "*domain.User nil", resource: (*domain.User)(nil)},
+		{name: "tipe resource tidak dikenal", resource: "barang"},
+	}
+
+	for _, res := range resources {
+		principals := []struct {
+			name      string
+			principal Principal
+			allowed   []Action
+		}{
+			{"bukan pemilik", Principal{UserID: otherUserID, Role: domain.RoleUser}, nil},
+			{"principal kosong", Principal{}, nil},
+			{"admin dengan MFA", Principal{UserID: adminID, Role: domain.RoleAdmin, MFA: true}, adminActions},
+			{"admin tanpa MFA", Principal{UserID: adminID, Role: domain.RoleAdmin}, nil},
+			{"moderator dengan MFA", Principal{UserID: moderatorID, Role: domain.RoleModerator, MFA: true}, moderatorActions},
+			{"moderator tanpa MFA", Principal{UserID: moderatorID, Role: domain.RoleModerator}, nil},
+			// Pengguna biasa tidak mendapat izin role meskipun login dengan MFA
+			{"pengguna biasa dengan MFA", Principal{UserID: otherUserID, Role: domain.RoleUser, MFA: true}, nil},
+			// Role tanpa ID pengguna tidak dianggap pemilik resource apa pun
+			{"role admin tanpa ID", Principal{Role: domain.RoleAdmin, MFA: true}, adminActions},
+		}
+		for party, p := range res.parties {
+			principals = append(principals,
+				struct {
+					name      string
+					principal Principal
+					allowed   []Action
+				}{party, Principal{UserID: p.userID, Role: domain.RoleUser}, p.actions},
+				// Admin tanpa MFA tetap memiliki izin sebagai pihak yang terlibat, tetapi tidak izin role
+				struct {
+					name      string
+					principal Principal
+					allowed   []Action
+				}{party + " (admin tanpa MFA)", Principal{UserID: p.userID, Role: domain.RoleAdmin}, p.actions},
+				struct {
+					name      string
+					principal Principal
+					allowed   []Action
+				}{party + " (moderator dengan MFA)", Principal{UserID: p.userID, Role: domain.RoleModerator, MFA: true}, union(p.actions, moderatorActions)},
+			)
+		}
+
+		for _, p := range principals {
+			allowed := make(map[Action]bool, len(p.allowed))
+			for _, action := range p.allowed {
+				allowed[action] = true
+			}
+			for _, action := range allActions {
+				if got := Can(p.principal, action, res.resource); got != allowed[action] {
+					t.Errorf("%s / %s / %s: Can = %v, want %v", res.name, p.name, action, got, allowed[action])
+				}
+			}
+		}
+	}
+}
+
+func TestRoleGrantsOnlyContainKnownActions(t *testing.T) {
+	known := make(map[Action]bool, len(allActions))
+	for _, action := range allActions {
+		known[action] = true
+	}
+	for role, grants := range roleGrants {
+		for action := range grants {
+			if !known[action] {
+				t.Errorf("role %s memiliki action %s yang tidak ada di allActions", role, action)
+			}
+		}
+	}
+}
+
+func TestPrincipalFor(t *testing.T) {
+	moderator := Principal{UserID: moderatorID, Role: domain.RoleModerator, MFA: true}
+	ctx := WithPrincipal(context.Background(), moderator)
+
+	if got, ok := FromContext(ctx); !ok || got != moderator {
+		t.Errorf("FromContext = %+v, %v, want %+v, true", got, ok, moderator)
+	}
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("FromContext tanpa principal seharusnya false")
+	}
+
+	if got := PrincipalFor(ctx, moderatorID); got != moderator {
+		t.Errorf("PrincipalFor pengguna yang sama = %+v, want %+v", got, moderator)
+	}
+	// Principal milik pengguna lain tidak boleh dipinjam
+	want := Principal{UserID: otherUserID, Role: domain.RoleUser}
+	if got := PrincipalFor(ctx, otherUserID); got != want {
+		t.Errorf("PrincipalFor pengguna lain = %+v, want %+v", got, want)
+	}
+	if got := PrincipalFor(context.Background(), otherUserID); got != want {
+		t.Errorf("PrincipalFor tanpa principal = %+v, want %+v", got, want)
+	}
+}
+
+// union menggabungkan dua daftar action
+func union(a, b []Action) []Action {
+	return append(append([]Action{}, a...), b...)
+}
@@ -0,0 +1,44 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+)
+
+// Principal adalah pengguna yang melakukan request
+type Principal struct {
+	UserID uint
+	Role   domain.Role
+	// MFA bernilai true jika pengguna login dengan verifikasi dua langkah
+	MFA bool
+}
+
+// principalKey adalah key context untuk Principal
+type principalKey struct{}
+
+// WithPrincipal menyimpan Principal di context
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext mengambil Principal dari context
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// PrincipalFor mengambil Principal dari context jika milik pengguna userID.
+// Jika tidak ada, userID dianggap pengguna biasa sehingga hanya aturan pemilik yang berlaku.
+func PrincipalFor(ctx context.Context, userID uint) Principal {
+	if principal, ok := FromContext(ctx); ok && principal.UserID == userID {
+		return principal
+	}
+	return Principal{UserID: userID, Role: domain.RoleUser}
+}
+
+// hasRoleGrants memeriksa apakah hak dari role boleh dipakai.
+// Role yang wajib 2FA hanya mendapatkan haknya jika login dengan verifikasi dua langkah.
+func (p Principal) hasRoleGrants() bool {
+	return !p.Role.RequiresMFA() || p.MFA
+}
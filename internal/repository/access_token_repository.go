@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AccessTokenRepository adalah interface untuk operasi database personal access token
+type AccessTokenRepository interface {
+	// Create menyimpan personal access token baru
+	Create(ctx context.Context, token *domain.AccessToken) error
+
+	// FindByHash mencari personal access token berdasarkan hash token
+	FindByHash(ctx context.Context, tokenHash string) (*domain.AccessToken, error)
+
+	// FindByUserID mencari personal access token milik pengguna yang belum dicabut
+	FindByUserID(ctx context.Context, userID uint) ([]domain.AccessToken, error)
+
+	// CountActiveByUserID menghitung personal access token milik pengguna yang belum dicabut
+	CountActiveByUserID(ctx context.Context, userID uint) (int64, error)
+
+	// Revoke mencabut personal access token milik pengguna.
+	// Mengembalikan false jika token tidak ditemukan atau sudah dicabut.
+	Revoke(ctx context.Context, id, userID uint) (bool, error)
+
+	// TouchLastUsed memperbarui waktu terakhir token dipakai jika sudah lebih lama dari staleBefore
+	TouchLastUsed(ctx context.Context, id uint, now, staleBefore time.Time) error
+}
+
+// accessTokenRepositoryImpl adalah implementasi PostgreSQL dari AccessTokenRepository
+type accessTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAccessTokenRepository membuat instance baru dari AccessTokenRepository
+func NewAccessTokenRepository(db *gorm.DB) AccessTokenRepository {
+	return &accessTokenRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create menyimpan personal access token baru
+func (r *accessTokenRepositoryImpl) Create(ctx context.Context, token *domain.AccessToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// FindByHash mencari personal access token berdasarkan hash token
+func (r *accessTokenRepositoryImpl) FindByHash(ctx context.Context, tokenHash string) (*domain.AccessToken, error) {
+	var token domain.AccessToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("personal access token tidak ditemukan")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByUserID mencari personal access token milik pengguna yang belum dicabut
+func (r *accessTokenRepositoryImpl) FindByUserID(ctx context.Context, userID uint) ([]domain.AccessToken, error) {
+	var tokens []domain.AccessToken
+	err := r.db.WithContext(ctx).
+		Where("pengguna_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// CountActiveByUserID menghitung personal access token milik pengguna yang belum dicabut
+func (r *accessTokenRepositoryImpl) CountActiveByUserID(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.AccessToken{}).
+		Where("pengguna_id = ? AND revoked_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// Revoke mencabut personal access token milik pengguna
+func (r *accessTokenRepositoryImpl) Revoke(ctx context.Context, id, userID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.AccessToken{}).
+		Where("id = ? AND pengguna_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// TouchLastUsed memperbarui waktu terakhir token dipakai
+func (r *accessTokenRepositoryImpl) TouchLastUsed(ctx context.Context, id uint, now, staleBefore time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.AccessToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, staleBefore).
+		Update("last_used_at", now).Error
+}
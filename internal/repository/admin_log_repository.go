@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AdminLogRepository adalah interface untuk operasi database log tindakan admin
+type AdminLogRepository interface {
+	// Create menyimpan catatan tindakan admin
+	Create(ctx context.Context, log *domain.AdminLog) error
+
+	// FindAll mencari catatan tindakan admin dengan paginasi, terbaru lebih dulu.
+	// Jika targetID bukan 0, hanya catatan untuk pengguna tersebut yang diambil.
+	FindAll(ctx context.Context, page, limit int, targetID uint) ([]domain.AdminLog, int64, error)
+}
+
+// adminLogRepositoryImpl adalah implementasi PostgreSQL dari AdminLogRepository
+type adminLogRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAdminLogRepository membuat instance baru dari AdminLogRepository
+func NewAdminLogRepository(db *gorm.DB) AdminLogRepository {
+	return &adminLogRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create menyimpan catatan tindakan admin
+func (r *adminLogRepositoryImpl) Create(ctx context.Context, log *domain.AdminLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// FindAll mencari catatan tindakan admin dengan paginasi
+func (r *adminLogRepositoryImpl) FindAll(ctx context.Context, page, limit int, targetID uint) ([]domain.AdminLog, int64, error) {
+	var logs []domain.AdminLog
+	var total int64
+
+	// Hitung offset berdasarkan halaman dan batas
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&domain.AdminLog{})
+	if targetID != 0 {
+		query = query.Where("target_id = ?", targetID)
+	}
+
+	// Hitung total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Jalankan query dengan paginasi
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
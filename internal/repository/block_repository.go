@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// BlockRepository adalah interface untuk operasi database pemblokiran pengguna
+type BlockRepository interface {
+	// Create menyimpan pemblokiran baru
+	Create(ctx context.Context, block *domain.Block) error
+
+	// Find mencari pemblokiran blockedID oleh blockerID.
+	// Mengembalikan nil tanpa error jika pengguna tidak diblokir.
+	Find(ctx context.Context, blockerID, blockedID uint) (*domain.Block, error)
+
+	// Delete menghapus pemblokiran dan mengembalikan false jika pemblokiran tidak ada
+	Delete(ctx context.Context, blockerID, blockedID uint) (bool, error)
+
+	// IsBlockedBetween memeriksa apakah salah satu dari kedua pengguna memblokir yang lain
+	IsBlockedBetween(ctx context.Context, userA, userB uint) (bool, error)
+
+	// FindByBlockerID mencari pengguna yang diblokir oleh blockerID dengan paginasi
+	FindByBlockerID(ctx context.Context, blockerID uint, page, limit int) ([]domain.Block, int64, error)
+}
+
+// blockRepositoryImpl adalah implementasi PostgreSQL dari BlockRepository
+type blockRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBlockRepository membuat instance baru dari BlockRepository
+func NewBlockRepository(db *gorm.DB) BlockRepository {
+	return &blockRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create menyimpan pemblokiran baru
+func (r *blockRepositoryImpl) Create(ctx context.Context, block *domain.Block) error {
+	return r.db.WithContext(ctx).Create(block).Error
+}
+
+// Find mencari pemblokiran blockedID oleh blockerID
+func (r *blockRepositoryImpl) Find(ctx context.Context, blockerID, blockedID uint) (*domain.Block, error) {
+	var block domain.Block
+	if err := r.db.WithContext(ctx).Preload("Diblokir").
+		Where("pemblokir_id = ? AND diblokir_id = ?", blockerID, blockedID).
+		First(&block).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &block, nil
+}
+
+// Delete menghapus pemblokiran
+func (r *blockRepositoryImpl) Delete(ctx context.Context, blockerID, blockedID uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("pemblokir_id = ? AND diblokir_id = ?", blockerID, blockedID).
+		Delete(&domain.Block{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// IsBlockedBetween memeriksa apakah salah satu dari kedua pengguna memblokir yang lain
+func (r *blockRepositoryImpl) IsBlockedBetween(ctx context.Context, userA, userB uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Block{}).
+		Where("(pemblokir_id = ? AND diblokir_id = ?) OR (pemblokir_id = ? AND diblokir_id = ?)", userA, userB, userB, userA).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindByBlockerID mencari pengguna yang diblokir oleh blockerID dengan paginasi
+func (r *blockRepositoryImpl) FindByBlockerID(ctx context.Context, blockerID uint, page, limit int) ([]domain.Block, int64, error) {
+	var blocks []domain.Block
+	var total int64
+
+	// Hitung offset berdasarkan halaman dan batas
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&domain.Block{}).Where("pemblokir_id = ?", blockerID)
+
+	// Hitung total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Jalankan query dengan paginasi
+	if err := query.Preload("Diblokir").Offset(offset).Limit(limit).Order("created_at DESC").Find(&blocks).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return blocks, total, nil
+}
@@ -23,9 +23,13 @@ type ChatRepository interface {
 	// FindByUserIDs mencari chat antara dua pengguna untuk barang tertentu
 	FindByUserIDs(ctx context.Context, pengirimID, penerimaID, barangID uint, page, limit int) ([]domain.Chat, int64, error)
 	
-	// FindChatPartners mencari semua partner chat untuk pengguna tertentu
+	// FindChatPartners mencari semua partner chat untuk pengguna tertentu,
+	// kecuali pengguna yang diblokirnya
 	FindChatPartners(ctx context.Context, userID uint) ([]domain.User, error)
 	
+	// FindByUserID mencari semua chat yang dikirim atau diterima pengguna dengan paginasi
+	FindByUserID(ctx context.Context, userID uint, page, limit int) ([]domain.Chat, int64, error)
+	
 	// UpdateReadStatus memperbarui status dibaca untuk chat
 	UpdateReadStatus(ctx context.Context, chatID uint, dibaca bool) error
 	
@@ -126,11 +130,13 @@ func (r *chatRepositoryImpl) FindChatPartners(ctx context.Context, userID uint)
 	// Mencari semua pengguna yang pernah berinteraksi dengan pengguna ini
 	// Buat subquery untuk pengirim
 	query := r.db.WithContext(ctx).Table("pengguna").
-		Where("id IN (?)",
+		Where("id IN (?) OR id IN (?)",
 			r.db.Table("chat").Select("DISTINCT penerima_id").Where("pengirim_id = ?", userID),
-		).
-		Or("id IN (?)",
 			r.db.Table("chat").Select("DISTINCT pengirim_id").Where("penerima_id = ?", userID),
+		).
+		// Pengguna yang diblokir tidak ditampilkan
+		Where("id NOT IN (?)",
+			r.db.Table("blokir").Select("diblokir_id").Where("pemblokir_id = ?", userID),
 		)
 
 	if err := query.Find(&users).Error; err != nil {
@@ -140,6 +146,29 @@ func (r *chatRepositoryImpl) FindChatPartners(ctx context.Context, userID uint)
 	return users, nil
 }
 
+// FindByUserID mencari semua chat yang dikirim atau diterima pengguna dengan paginasi
+func (r *chatRepositoryImpl) FindByUserID(ctx context.Context, userID uint, page, limit int) ([]domain.Chat, int64, error) {
+	var chats []domain.Chat
+	var total int64
+
+	// Hitung offset berdasarkan halaman dan batas
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&domain.Chat{}).Where("pengirim_id = ? OR penerima_id = ?", userID, userID)
+
+	// Hitung total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Jalankan query dengan paginasi
+	if err := query.Offset(offset).Limit(limit).Order("timestamp ASC, id ASC").Find(&chats).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return chats, total, nil
+}
+
 // UpdateReadStatus memperbarui status dibaca untuk chat
 func (r *chatRepositoryImpl) UpdateReadStatus(ctx context.Context, chatID uint, dibaca bool) error {
 	return r.db.WithContext(ctx).Model(&domain.Chat{}).Where("id = ?", chatID).Update("dibaca", dibaca).Error
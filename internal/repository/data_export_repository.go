@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// DataExportRepository adalah interface untuk operasi database ekspor data pribadi
+type DataExportRepository interface {
+	// Create menyimpan permintaan ekspor baru
+	Create(ctx context.Context, export *domain.DataExport) error
+
+	// FindByID mencari permintaan ekspor berdasarkan ID
+	FindByID(ctx context.Context, id uint) (*domain.DataExport, error)
+
+	// FindLatestByUserID mencari permintaan ekspor terbaru milik pengguna.
+	// Mengembalikan nil tanpa error jika pengguna belum pernah meminta ekspor.
+	FindLatestByUserID(ctx context.Context, userID uint) (*domain.DataExport, error)
+
+	// ClaimNext mengambil satu permintaan ekspor yang menunggu dan menandainya sedang diproses.
+	// Permintaan yang sudah diproses lebih lama dari staleAfter dianggap macet dan diambil ulang.
+	// Mengembalikan nil tanpa error jika tidak ada permintaan.
+	ClaimNext(ctx context.Context, staleAfter time.Duration) (*domain.DataExport, error)
+
+	// FindExpired mencari arsip siap unduh yang sudah melewati masa simpan
+	FindExpired(ctx context.Context, now time.Time) ([]domain.DataExport, error)
+
+	// Update memperbarui permintaan ekspor
+	Update(ctx context.Context, export *domain.DataExport) error
+}
+
+// dataExportRepositoryImpl adalah implementasi PostgreSQL dari DataExportRepository
+type dataExportRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewDataExportRepository membuat instance baru dari DataExportRepository
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &dataExportRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create menyimpan permintaan ekspor baru
+func (r *dataExportRepositoryImpl) Create(ctx context.Context, export *domain.DataExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+// FindByID mencari permintaan ekspor berdasarkan ID
+func (r *dataExportRepositoryImpl) FindByID(ctx context.Context, id uint) (*domain.DataExport, error) {
+	var export domain.DataExport
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("ekspor data dengan ID %d tidak ditemukan", id)
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// FindLatestByUserID mencari permintaan ekspor terbaru milik pengguna
+func (r *dataExportRepositoryImpl) FindLatestByUserID(ctx context.Context, userID uint) (*domain.DataExport, error) {
+	var export domain.DataExport
+	if err := r.db.WithContext(ctx).Where("pengguna_id = ?", userID).Order("created_at DESC, id DESC").First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ClaimNext mengambil satu permintaan ekspor yang menunggu dan menandainya sedang diproses.
+// SKIP LOCKED memastikan satu permintaan hanya diproses oleh satu worker jika ada beberapa instance.
+func (r *dataExportRepositoryImpl) ClaimNext(ctx context.Context, staleAfter time.Duration) (*domain.DataExport, error) {
+	var export domain.DataExport
+	now := time.Now()
+	if err := r.db.WithContext(ctx).Raw(`
+		UPDATE ekspor_data SET status = ?, updated_at = ?
+		WHERE id = (
+			SELECT id FROM ekspor_data
+			WHERE status = ? OR (status = ? AND updated_at < ?)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, domain.DataExportProcessing, now,
+		domain.DataExportPending, domain.DataExportProcessing, now.Add(-staleAfter),
+	).Scan(&export).Error; err != nil {
+		return nil, err
+	}
+	if export.ID == 0 {
+		return nil, nil
+	}
+	return &export, nil
+}
+
+// FindExpired mencari arsip siap unduh yang sudah melewati masa simpan
+func (r *dataExportRepositoryImpl) FindExpired(ctx context.Context, now time.Time) ([]domain.DataExport, error) {
+	var exports []domain.DataExport
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", domain.DataExportReady, now).
+		Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// Update memperbarui permintaan ekspor
+func (r *dataExportRepositoryImpl) Update(ctx context.Context, export *domain.DataExport) error {
+	return r.db.WithContext(ctx).Save(export).Error
+}
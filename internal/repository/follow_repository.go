@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// FollowRepository adalah interface untuk operasi database pengguna yang mengikuti penjual
+type FollowRepository interface {
+	// Create menyimpan hubungan mengikuti baru
+	Create(ctx context.Context, follow *domain.Follow) error
+
+	// Find mencari hubungan mengikuti followedID oleh followerID.
+	// Mengembalikan nil tanpa error jika followerID tidak mengikuti followedID.
+	Find(ctx context.Context, followerID, followedID uint) (*domain.Follow, error)
+
+	// Delete menghapus hubungan mengikuti dan mengembalikan false jika hubungan tidak ada
+	Delete(ctx context.Context, followerID, followedID uint) (bool, error)
+
+	// DeleteBetween menghapus hubungan mengikuti di antara dua pengguna dari kedua arah
+	DeleteBetween(ctx context.Context, userA, userB uint) error
+
+	// FindFollowers mencari pengikut pengguna dengan paginasi, terbaru lebih dulu
+	FindFollowers(ctx context.Context, userID uint, page, limit int) ([]domain.Follow, int64, error)
+
+	// FindFollowing mencari penjual yang diikuti pengguna dengan paginasi, terbaru lebih dulu
+	FindFollowing(ctx context.Context, userID uint, page, limit int) ([]domain.Follow, int64, error)
+}
+
+// followRepositoryImpl adalah implementasi PostgreSQL dari FollowRepository
+type followRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewFollowRepository membuat instance baru dari FollowRepository
+func NewFollowRepository(db *gorm.DB) FollowRepository {
+	return &followRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create menyimpan hubungan mengikuti baru
+func (r *followRepositoryImpl) Create(ctx context.Context, follow *domain.Follow) error {
+	return r.db.WithContext(ctx).Omit("Pengikut", "Diikuti").Create(follow).Error
+}
+
+// Find mencari hubungan mengikuti followedID oleh followerID
+func (r *followRepositoryImpl) Find(ctx context.Context, followerID, followedID uint) (*domain.Follow, error) {
+	var follow domain.Follow
+	if err := r.db.WithContext(ctx).
+		Where("pengikut_id = ? AND diikuti_id = ?", followerID, followedID).
+		First(&follow).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &follow, nil
+}
+
+// Delete menghapus hubungan mengikuti
+func (r *followRepositoryImpl) Delete(ctx context.Context, followerID, followedID uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("pengikut_id = ? AND diikuti_id = ?", followerID, followedID).
+		Delete(&domain.Follow{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteBetween menghapus hubungan mengikuti di antara dua pengguna dari kedua arah
+func (r *followRepositoryImpl) DeleteBetween(ctx context.Context, userA, userB uint) error {
+	return r.db.WithContext(ctx).
+		Where("(pengikut_id = ? AND diikuti_id = ?) OR (pengikut_id = ? AND diikuti_id = ?)", userA, userB, userB, userA).
+		Delete(&domain.Follow{}).Error
+}
+
+// FindFollowers mencari pengikut pengguna dengan paginasi
+func (r *followRepositoryImpl) FindFollowers(ctx context.Context, userID uint, page, limit int) ([]domain.Follow, int64, error) {
+	return r.findPage(ctx, "diikuti_id", "Pengikut", userID, page, limit)
+}
+
+// FindFollowing mencari penjual yang diikuti pengguna dengan paginasi
+func (r *followRepositoryImpl) FindFollowing(ctx context.Context, userID uint, page, limit int) ([]domain.Follow, int64, error) {
+	return r.findPage(ctx, "pengikut_id", "Diikuti", userID, page, limit)
+}
+
+// findPage mencari hubungan mengikuti berdasarkan salah satu kolom pengguna
+// dan memuat pengguna di sisi lain hubungan
+func (r *followRepositoryImpl) findPage(ctx context.Context, column, preload string, userID uint, page, limit int) ([]domain.Follow, int64, error) {
+	var follows []domain.Follow
+	var total int64
+
+	// Hitung offset berdasarkan halaman dan batas
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&domain.Follow{}).Where(column+" = ?", userID)
+
+	// Hitung total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Jalankan query dengan paginasi
+	if err := query.Preload(preload).Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&follows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return follows, total, nil
+}
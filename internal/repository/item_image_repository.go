@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ErrGalleryFull dikembalikan Create jika gambar baru membuat galeri melebihi domain.MaxItemImages
+var ErrGalleryFull = errors.New("galeri barang sudah penuh")
+
+// ItemImageRepository adalah interface untuk operasi database galeri gambar barang.
+// Setiap perubahan galeri juga memperbarui kolom gambar pada barang menjadi URL gambar sampul.
+type ItemImageRepository interface {
+	// Create menambahkan gambar setelah gambar terakhir di galeri barang.
+	// Jika asCover true, gambar pertama dijadikan sampul.
+	// Mengembalikan ErrGalleryFull jika galeri tidak dapat menampung semua gambar.
+	Create(ctx context.Context, barangID uint, images []domain.ItemImage, asCover bool) error
+
+	// FindByBarangID mencari semua gambar barang sesuai urutan galeri
+	FindByBarangID(ctx context.Context, barangID uint) ([]domain.ItemImage, error)
+
+	// FindByID mencari gambar milik barang, mengembalikan nil jika tidak ada
+	FindByID(ctx context.Context, barangID, id uint) (*domain.ItemImage, error)
+
+	// CountByBarangID menghitung jumlah gambar barang
+	CountByBarangID(ctx context.Context, barangID uint) (int64, error)
+
+	// UpdateKeterangan mengubah keterangan gambar
+	UpdateKeterangan(ctx context.Context, id uint, keterangan string) error
+
+	// SetCover menjadikan gambar sebagai satu-satunya sampul barang
+	SetCover(ctx context.Context, barangID, id uint) error
+
+	// Reorder mengubah urutan galeri sesuai urutan ids. ids harus berisi semua gambar barang.
+	Reorder(ctx context.Context, barangID uint, ids []uint) error
+
+	// Delete menghapus gambar. Jika gambar adalah sampul, gambar pertama yang tersisa menjadi sampul.
+	Delete(ctx context.Context, barangID, id uint) error
+}
+
+// itemImageRepositoryImpl adalah implementasi PostgreSQL dari ItemImageRepository
+type itemImageRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewItemImageRepository membuat instance baru dari ItemImageRepository
+func NewItemImageRepository(db *gorm.DB) ItemImageRepository {
+	return &itemImageRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create menambahkan gambar setelah gambar terakhir di galeri barang
+func (r *itemImageRepositoryImpl) Create(ctx context.Context, barangID uint, images []domain.ItemImage, asCover bool) error {
+	if len(images) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Kunci baris barang agar upload bersamaan ke galeri yang sama berjalan bergantian,
+		// sehingga jumlah gambar dan urutan berikutnya tidak dibaca dua kali dengan nilai yang sama
+		if err := tx.Exec("SELECT id FROM barang WHERE id = ? FOR UPDATE", barangID).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&domain.ItemImage{}).Where("barang_id = ?", barangID).Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count)+len(images) > domain.MaxItemImages {
+			return ErrGalleryFull
+		}
+
+		var next int
+		if err := tx.Model(&domain.ItemImage{}).
+			Select("COALESCE(MAX(urutan) + 1, 0)").
+			Where("barang_id = ?", barangID).
+			Scan(&next).Error; err != nil {
+			return err
+		}
+
+		if asCover {
+			if err := r.clearCover(tx, barangID); err != nil {
+				return err
+			}
+		}
+
+		for i := range images {
+			images[i].BarangID = barangID
+			images[i].Urutan = next + i
+			images[i].Sampul = asCover && i == 0
+		}
+		if err := tx.Create(&images).Error; err != nil {
+			return err
+		}
+
+		return r.syncCover(tx, barangID)
+	})
+}
+
+// FindByBarangID mencari semua gambar barang sesuai urutan galeri
+func (r *itemImageRepositoryImpl) FindByBarangID(ctx context.Context, barangID uint) ([]domain.ItemImage, error) {
+	var images []domain.ItemImage
+	if err := r.db.WithContext(ctx).Where("barang_id = ?", barangID).Order("urutan ASC, id ASC").Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// FindByID mencari gambar milik barang
+func (r *itemImageRepositoryImpl) FindByID(ctx context.Context, barangID, id uint) (*domain.ItemImage, error) {
+	var image domain.ItemImage
+	if err := r.db.WithContext(ctx).Where("id = ? AND barang_id = ?", id, barangID).First(&image).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+// CountByBarangID menghitung jumlah gambar barang
+func (r *itemImageRepositoryImpl) CountByBarangID(ctx context.Context, barangID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.ItemImage{}).Where("barang_id = ?", barangID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// UpdateKeterangan mengubah keterangan gambar
+func (r *itemImageRepositoryImpl) UpdateKeterangan(ctx context.Context, id uint, keterangan string) error {
+	return r.db.WithContext(ctx).Model(&domain.ItemImage{}).Where("id = ?", id).Update("keterangan", keterangan).Error
+}
+
+// SetCover menjadikan gambar sebagai satu-satunya sampul barang
+func (r *itemImageRepositoryImpl) SetCover(ctx context.Context, barangID, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Sampul lama dilepas lebih dulu karena setiap barang hanya boleh memiliki satu sampul
+		if err := r.clearCover(tx, barangID); err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.ItemImage{}).
+			Where("id = ? AND barang_id = ?", id, barangID).
+			Update("sampul", true).Error; err != nil {
+			return err
+		}
+		return r.syncCover(tx, barangID)
+	})
+}
+
+// Reorder mengubah urutan galeri sesuai urutan ids
+func (r *itemImageRepositoryImpl) Reorder(ctx context.Context, barangID uint, ids []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			if err := tx.Model(&domain.ItemImage{}).
+				Where("id = ? AND barang_id = ?", id, barangID).
+				Update("urutan", i).Error; err != nil {
+				return err
+			}
+		}
+		return r.syncCover(tx, barangID)
+	})
+}
+
+// Delete menghapus gambar dari galeri barang
+func (r *itemImageRepositoryImpl) Delete(ctx context.Context, barangID, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND barang_id = ?", id, barangID).Delete(&domain.ItemImage{}).Error; err != nil {
+			return err
+		}
+		return r.syncCover(tx, barangID)
+	})
+}
+
+// clearCover melepas tanda sampul dari semua gambar barang
+func (r *itemImageRepositoryImpl) clearCover(tx *gorm.DB, barangID uint) error {
+	return tx.Model(&domain.ItemImage{}).
+		Where("barang_id = ? AND sampul", barangID).
+		Update("sampul", false).Error
+}
+
+// syncCover memastikan galeri yang tidak kosong memiliki sampul, lalu menyalin URL sampul
+// ke kolom gambar barang agar klien lama tetap menampilkan gambar utama
+func (r *itemImageRepositoryImpl) syncCover(tx *gorm.DB, barangID uint) error {
+	var images []domain.ItemImage
+	if err := tx.Where("barang_id = ?", barangID).Order("urutan ASC, id ASC").Find(&images).Error; err != nil {
+		return err
+	}
+
+	gambar := ""
+	if len(images) > 0 {
+		cover := images[0]
+		for _, image := range images {
+			if image.Sampul {
+				cover = image
+				break
+			}
+		}
+		if !cover.Sampul {
+			if err := tx.Model(&domain.ItemImage{}).Where("id = ?", cover.ID).Update("sampul", true).Error; err != nil {
+				return err
+			}
+		}
+		gambar = cover.URL
+	}
+
+	return tx.Model(&domain.Item{}).Where("id = ?", barangID).UpdateColumn("gambar", gambar).Error
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ItemRepository adalah interface untuk operasi database barang
@@ -17,11 +19,29 @@ type ItemRepository interface {
 	// FindByID mencari barang berdasarkan ID
 	FindByID(ctx context.Context, id uint) (*domain.Item, error)
 	
-	// FindAll mencari semua barang dengan paginasi dan filter
-	FindAll(ctx context.Context, page, limit int, search string, kategori string, status string) ([]domain.Item, int64, error)
+	// FindAll mencari semua barang dengan paginasi dan filter.
+	// Pencarian memakai full-text search dengan pencocokan awalan kata; jika tidak ada hasil,
+	// pencarian diulang dengan kemiripan trigram pada nama barang agar salah ketik tetap menemukan barang.
+	// Pencarian yang tidak berisi huruf atau angka tidak menemukan barang apa pun.
+	// SearchMatch menunjukkan cara pencarian menemukan barang.
+	FindAll(ctx context.Context, filter domain.ItemFilter, page, limit int) ([]domain.Item, int64, domain.SearchMatch, error)
+	
+	// FindHighlights membuat cuplikan nama dan deskripsi barang dengan kata pencarian ditandai <mark>
+	FindHighlights(ctx context.Context, ids []uint, search string) (map[uint]domain.ItemHighlight, error)
+	
+	// FindNameSuggestions mencari barang yang tampil di daftar barang dan namanya diawali kata-kata search,
+	// paling relevan lebih dulu. Hanya kolom id, nama_barang dan kategori yang dimuat.
+	FindNameSuggestions(ctx context.Context, search string, limit int) ([]domain.Item, error)
 	
 	// FindByPenjualID mencari barang berdasarkan ID penjual
-	FindByPenjualID(ctx context.Context, penjualID uint, page, limit int) ([]domain.Item, int64, error)
+	FindByPenjualID(ctx context.Context, penjualID uint, page, limit int, includeHidden bool) ([]domain.Item, int64, error)
+	
+	// FindFeed mencari barang Tersedia dari penjual yang diikuti followerID, terbaru lebih dulu.
+	// Jika cursor tidak nil, hanya barang yang lebih lama dari cursor yang dikembalikan.
+	FindFeed(ctx context.Context, followerID uint, cursor *domain.FeedCursor, limit int) ([]domain.Item, error)
+	
+	// FindAllByPenjualIDWithDeleted mencari semua barang milik penjual, termasuk yang sudah dihapus
+	FindAllByPenjualIDWithDeleted(ctx context.Context, penjualID uint) ([]domain.Item, error)
 	
 	// Update memperbarui data barang
 	Update(ctx context.Context, item *domain.Item) error
@@ -56,7 +76,7 @@ func (r *itemRepositoryImpl) Create(ctx context.Context, item *domain.Item) erro
 // FindByID mencari barang berdasarkan ID
 func (r *itemRepositoryImpl) FindByID(ctx context.Context, id uint) (*domain.Item, error) {
 	var item domain.Item
-	if err := r.db.WithContext(ctx).Preload("Penjual").Where("id = ?", id).First(&item).Error; err != nil {
+	if err := r.withImages(r.db.WithContext(ctx)).Preload("Penjual").Where("id = ?", id).First(&item).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("barang dengan ID %d tidak ditemukan", id)
 		}
@@ -66,50 +86,148 @@ func (r *itemRepositoryImpl) FindByID(ctx context.Context, id uint) (*domain.Ite
 }
 
 // FindAll mencari semua barang dengan paginasi dan filter
-func (r *itemRepositoryImpl) FindAll(ctx context.Context, page, limit int, search string, kategori string, status string) ([]domain.Item, int64, error) {
+func (r *itemRepositoryImpl) FindAll(ctx context.Context, filter domain.ItemFilter, page, limit int) ([]domain.Item, int64, domain.SearchMatch, error) {
 	var items []domain.Item
 	var total int64
+	match := domain.SearchMatchNone
 
 	// Hitung offset berdasarkan halaman dan batas
 	offset := (page - 1) * limit
 
-	// Buat query dasar
-	query := r.db.WithContext(ctx).Model(&domain.Item{}).Preload("Penjual")
+	query := r.listQuery(r.db.WithContext(ctx), filter)
+	order := "created_at DESC, id DESC"
+	var orderVars []interface{}
 
-	// Tambahkan filter pencarian jika ada
-	if search != "" {
-		searchQuery := "%" + search + "%"
-		query = query.Where("nama_barang ILIKE ? OR deskripsi ILIKE ?", searchQuery, searchQuery)
+	tsquery := prefixTSQuery(filter.Search, "")
+	if filter.Search != "" && tsquery == "" {
+		// Pencarian tanpa huruf atau angka, misalnya "!!!", tidak cocok dengan barang apa pun
+		return []domain.Item{}, 0, domain.SearchMatchNone, nil
 	}
 
-	// Filter berdasarkan kategori
-	if kategori != "" {
-		query = query.Where("kategori = ?", kategori)
+	if tsquery != "" {
+		// Full-text search memakai index GIN pada search_vector
+		query = query.Where("search_vector @@ "+searchTSQuery, tsquery)
+		if err := query.Count(&total).Error; err != nil {
+			return nil, 0, domain.SearchMatchNone, err
+		}
+		if total == 0 {
+			return r.findSimilar(ctx, filter, offset, limit)
+		}
+		match = domain.SearchMatchFullText
+
+		// Bobot: nama barang (A) lebih tinggi dari deskripsi (B) dan kategori (C)
+		if filter.Sort == domain.ItemSortRelevance {
+			order = "ts_rank_cd(search_vector, " + searchTSQuery + ") DESC, " + order
+			orderVars = []interface{}{tsquery}
+		}
+	} else if err := query.Count(&total).Error; err != nil {
+		// Hitung total records
+		return nil, 0, domain.SearchMatchNone, err
 	}
 
-	// Filter berdasarkan status
-	if status != "" {
-		query = query.Where("status = ?", status)
-	} else {
-		// Default menampilkan barang yang tersedia
-		query = query.Where("status = ?", domain.StatusTersedia)
+	// Jalankan query dengan paginasi
+	if err := r.findPage(query, order, orderVars, offset, limit, &items); err != nil {
+		return nil, 0, domain.SearchMatchNone, err
 	}
 
-	// Hitung total records
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	return items, total, match, nil
+}
+
+// findSimilar mencari barang yang namanya mirip dengan teks pencarian memakai index trigram,
+// dipakai jika full-text search tidak menemukan barang, misalnya karena salah ketik
+func (r *itemRepositoryImpl) findSimilar(ctx context.Context, filter domain.ItemFilter, offset, limit int) ([]domain.Item, int64, domain.SearchMatch, error) {
+	var items []domain.Item
+	var total int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Batas kemiripan hanya berlaku di transaksi ini
+		if err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)", fuzzySearchThreshold).Error; err != nil {
+			return err
+		}
+
+		query := r.listQuery(tx, filter).Where("? <% nama_barang", filter.Search)
+		if err := query.Count(&total).Error; err != nil {
+			return err
+		}
+
+		order := "created_at DESC, id DESC"
+		var orderVars []interface{}
+		if filter.Sort == domain.ItemSortRelevance {
+			order = "word_similarity(?, nama_barang) DESC, " + order
+			orderVars = []interface{}{filter.Search}
+		}
+		return r.findPage(query, order, orderVars, offset, limit, &items)
+	})
+	if err != nil {
+		return nil, 0, domain.SearchMatchNone, err
+	}
+	if total == 0 {
+		return items, 0, domain.SearchMatchNone, nil
 	}
 
-	// Jalankan query dengan paginasi
-	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&items).Error; err != nil {
-		return nil, 0, err
+	return items, total, domain.SearchMatchSimilar, nil
+}
+
+// FindHighlights membuat cuplikan nama dan deskripsi barang dengan kata pencarian ditandai <mark>
+func (r *itemRepositoryImpl) FindHighlights(ctx context.Context, ids []uint, search string) (map[uint]domain.ItemHighlight, error) {
+	highlights := make(map[uint]domain.ItemHighlight, len(ids))
+	tsquery := prefixTSQuery(search, "")
+	if len(ids) == 0 || tsquery == "" {
+		return highlights, nil
 	}
 
-	return items, total, nil
+	var rows []struct {
+		ID         uint
+		NamaBarang string
+		Deskripsi  string
+	}
+	if err := r.db.WithContext(ctx).Raw(
+		`SELECT id,
+			ts_headline('`+searchConfig+`', `+escapedHTMLColumn("nama_barang")+`, `+searchTSQuery+`, ?) AS nama_barang,
+			ts_headline('`+searchConfig+`', `+escapedHTMLColumn("deskripsi")+`, `+searchTSQuery+`, ?) AS deskripsi
+		FROM barang WHERE id IN ?`,
+		tsquery, nameHeadlineOptions,
+		tsquery, descriptionHeadlineOptions,
+		ids,
+	).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		highlights[row.ID] = domain.ItemHighlight{NamaBarang: row.NamaBarang, Deskripsi: row.Deskripsi}
+	}
+	return highlights, nil
+}
+
+// FindNameSuggestions mencari barang yang namanya diawali kata-kata search
+func (r *itemRepositoryImpl) FindNameSuggestions(ctx context.Context, search string, limit int) ([]domain.Item, error) {
+	var items []domain.Item
+
+	// Bobot A membatasi pencocokan ke nama barang, tetap memakai index GIN pada search_vector
+	tsquery := prefixTSQuery(search, nameSearchWeight)
+	if tsquery == "" {
+		return items, nil
+	}
+
+	err := r.listQuery(r.db.WithContext(ctx), domain.ItemFilter{}).
+		Select("id, nama_barang, kategori").
+		Where("search_vector @@ "+searchTSQuery, tsquery).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "ts_rank_cd(search_vector, " + searchTSQuery + ") DESC, created_at DESC, id DESC",
+			Vars:               []interface{}{tsquery},
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
 }
 
 // FindByPenjualID mencari barang berdasarkan ID penjual
-func (r *itemRepositoryImpl) FindByPenjualID(ctx context.Context, penjualID uint, page, limit int) ([]domain.Item, int64, error) {
+func (r *itemRepositoryImpl) FindByPenjualID(ctx context.Context, penjualID uint, page, limit int, includeHidden bool) ([]domain.Item, int64, error) {
 	var items []domain.Item
 	var total int64
 
@@ -118,6 +236,9 @@ func (r *itemRepositoryImpl) FindByPenjualID(ctx context.Context, penjualID uint
 
 	// Buat query dasar
 	query := r.db.WithContext(ctx).Model(&domain.Item{}).Where("penjual_id = ?", penjualID)
+	if !includeHidden {
+		query = query.Where("hidden_at IS NULL")
+	}
 
 	// Hitung total records
 	if err := query.Count(&total).Error; err != nil {
@@ -125,16 +246,47 @@ func (r *itemRepositoryImpl) FindByPenjualID(ctx context.Context, penjualID uint
 	}
 
 	// Jalankan query dengan paginasi
-	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&items).Error; err != nil {
+	if err := r.withImages(query).Offset(offset).Limit(limit).Order("created_at DESC").Find(&items).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return items, total, nil
 }
 
+// FindFeed mencari barang Tersedia dari penjual yang diikuti followerID
+func (r *itemRepositoryImpl) FindFeed(ctx context.Context, followerID uint, cursor *domain.FeedCursor, limit int) ([]domain.Item, error) {
+	var items []domain.Item
+
+	followed := r.db.Model(&domain.Follow{}).Select("diikuti_id").Where("pengikut_id = ?", followerID)
+	query := r.db.WithContext(ctx).Model(&domain.Item{}).Preload("Penjual").
+		Where("penjual_id IN (?) AND status = ?", followed, domain.StatusTersedia).
+		Where("hidden_at IS NULL AND penjual_id NOT IN (?)", r.hiddenSellerIDs())
+
+	// Cursor berisi created_at dan id barang terakhir; id memisahkan barang dengan waktu yang sama
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	if err := r.withImages(query).Order("created_at DESC, id DESC").Limit(limit).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// FindAllByPenjualIDWithDeleted mencari semua barang milik penjual, termasuk yang sudah dihapus
+func (r *itemRepositoryImpl) FindAllByPenjualIDWithDeleted(ctx context.Context, penjualID uint) ([]domain.Item, error) {
+	var items []domain.Item
+	if err := r.withImages(r.db.WithContext(ctx)).Unscoped().Where("penjual_id = ?", penjualID).Order("created_at ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 // Update memperbarui data barang
 func (r *itemRepositoryImpl) Update(ctx context.Context, item *domain.Item) error {
-	return r.db.WithContext(ctx).Save(item).Error
+	// Galeri diubah melalui ItemImageRepository
+	return r.db.WithContext(ctx).Omit("Images").Save(item).Error
 }
 
 // UpdateStatus memperbarui status barang
@@ -150,4 +302,57 @@ func (r *itemRepositoryImpl) Delete(ctx context.Context, id uint) error {
 // HardDelete menghapus barang secara permanen
 func (r *itemRepositoryImpl) HardDelete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Unscoped().Delete(&domain.Item{}, id).Error
-}
\ No newline at end of file
+}
+
+// listQuery membuat query daftar barang dengan filter selain pencarian
+func (r *itemRepositoryImpl) listQuery(db *gorm.DB, filter domain.ItemFilter) *gorm.DB {
+	query := db.Model(&domain.Item{})
+
+	// Filter berdasarkan kategori
+	if filter.Kategori != "" {
+		query = query.Where("kategori = ?", filter.Kategori)
+	}
+
+	// Filter berdasarkan status
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	} else {
+		// Default menampilkan barang yang tersedia
+		query = query.Where("status = ?", domain.StatusTersedia)
+	}
+
+	// Sembunyikan barang yang dilaporkan, serta barang milik penjual yang sedang ditangguhkan,
+	// akunnya dihapus atau profilnya disembunyikan karena laporan
+	query = query.Where("hidden_at IS NULL AND penjual_id NOT IN (?)", r.hiddenSellerIDs())
+
+	// Sembunyikan barang milik pengguna yang diblokir oleh viewer
+	if filter.ViewerID != 0 {
+		query = query.Where("penjual_id NOT IN (?)",
+			r.db.Model(&domain.Block{}).Select("diblokir_id").Where("pemblokir_id = ?", filter.ViewerID),
+		)
+	}
+
+	return query
+}
+
+// findPage menjalankan query daftar barang dengan urutan dan paginasi
+func (r *itemRepositoryImpl) findPage(query *gorm.DB, order string, orderVars []interface{}, offset, limit int, items *[]domain.Item) error {
+	return r.withImages(query).Preload("Penjual").
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: order, Vars: orderVars, WithoutParentheses: true}}).
+		Offset(offset).Limit(limit).
+		Find(items).Error
+}
+
+// withImages memuat galeri gambar barang sesuai urutannya
+func (r *itemRepositoryImpl) withImages(query *gorm.DB) *gorm.DB {
+	return query.Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Order("urutan ASC, id ASC")
+	})
+}
+
+// hiddenSellerIDs membuat subquery penjual yang barangnya tidak ditampilkan di daftar barang:
+// sedang ditangguhkan, akunnya dihapus atau profilnya disembunyikan karena laporan
+func (r *itemRepositoryImpl) hiddenSellerIDs() *gorm.DB {
+	return r.db.Model(&domain.User{}).Select("id").
+		Where("(suspended_at IS NOT NULL AND (suspended_until IS NULL OR suspended_until > ?)) OR deletion_scheduled_at IS NOT NULL OR hidden_at IS NOT NULL", time.Now())
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// searchConfig adalah konfigurasi text search PostgreSQL untuk kolom barang.search_vector.
+	// Konfigurasi simple tidak melakukan stemming karena nama barang bercampur bahasa Indonesia,
+	// bahasa Inggris dan nama merek, dan stemming membuat pencocokan awalan kata sulit ditebak.
+	searchConfig = "simple"
+
+	// searchTSQuery adalah ekspresi tsquery dari hasil prefixTSQuery
+	searchTSQuery = "to_tsquery('" + searchConfig + "', ?)"
+
+	// maxSearchTerms membatasi jumlah kata pencarian yang dipakai
+	maxSearchTerms = 8
+
+	// nameSearchWeight adalah bobot search_vector untuk nama barang
+	nameSearchWeight = "A"
+
+	// fuzzySearchThreshold adalah batas word_similarity pg_trgm untuk pencarian yang toleran salah ketik,
+	// misalnya "lapotp" tetap menemukan "laptop"
+	fuzzySearchThreshold = "0.3"
+
+	// nameHeadlineOptions dan descriptionHeadlineOptions mengatur cuplikan hasil pencarian dari ts_headline
+	nameHeadlineOptions        = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	descriptionHeadlineOptions = `StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=15, MaxFragments=2, FragmentDelimiter=" ... "`
+)
+
+// searchTermPattern adalah kata pencarian yang aman dipakai di to_tsquery
+var searchTermPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// prefixTSQuery mengubah teks pencarian menjadi tsquery yang mencocokkan awalan setiap kata,
+// misalnya "lapt hp" menjadi "lapt:* & hp:*". Karakter selain huruf dan angka dibuang sehingga
+// input pengguna tidak dapat membentuk sintaks tsquery yang tidak valid.
+// weights membatasi pencocokan ke bobot tertentu, misalnya nameSearchWeight untuk nama barang saja,
+// atau kosong untuk semua bobot.
+// Mengembalikan string kosong jika tidak ada kata yang dapat dicari.
+func prefixTSQuery(search, weights string) string {
+	terms := searchTermPattern.FindAllString(strings.ToLower(search), maxSearchTerms)
+	for i, term := range terms {
+		terms[i] = term + ":*" + weights
+	}
+	return strings.Join(terms, " & ")
+}
+
+// likePrefixPattern membuat pola LIKE yang mencocokkan awalan prefix,
+// dengan karakter wildcard di prefix di-escape
+func likePrefixPattern(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix) + "%"
+}
+
+// escapedHTMLColumn membuat ekspresi SQL yang meng-escape karakter HTML pada kolom teks,
+// agar cuplikan ts_headline hanya berisi tag <mark> dari sistem
+func escapedHTMLColumn(column string) string {
+	return "replace(replace(replace(coalesce(" + column + ", ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;')"
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// LoginAttemptRepository adalah interface untuk menyimpan percobaan login yang gagal.
+// Implementasi PostgreSQL dipakai jika aplikasi berjalan di beberapa replika,
+// implementasi in-memory cukup untuk satu instance atau pengembangan lokal.
+type LoginAttemptRepository interface {
+	// Get mendapatkan status percobaan login untuk kunci, nil jika belum pernah gagal
+	Get(ctx context.Context, key string) (*domain.LoginAttempt, error)
+
+	// RecordFailure menambah jumlah kegagalan untuk kunci dan mengembalikan status terbaru.
+	// Hitungan dimulai ulang jika kegagalan terakhir lebih lama dari window.
+	RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*domain.LoginAttempt, error)
+
+	// Lock mengunci kunci sampai waktu tertentu
+	Lock(ctx context.Context, key string, until time.Time) error
+
+	// Reset menghapus catatan kegagalan untuk kunci
+	Reset(ctx context.Context, key string) error
+}
+
+// loginAttemptRepositoryImpl adalah implementasi PostgreSQL dari LoginAttemptRepository
+type loginAttemptRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository membuat instance baru dari LoginAttemptRepository berbasis PostgreSQL
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepositoryImpl{
+		db: db,
+	}
+}
+
+// Get mendapatkan status percobaan login untuk kunci
+func (r *loginAttemptRepositoryImpl) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	var attempt domain.LoginAttempt
+	if err := r.db.WithContext(ctx).Where("attempt_key = ?", key).First(&attempt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// RecordFailure menambah jumlah kegagalan untuk kunci
+func (r *loginAttemptRepositoryImpl) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*domain.LoginAttempt, error) {
+	// Upsert atomik agar beberapa replika bisa mencatat kegagalan bersamaan
+	var attempt domain.LoginAttempt
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO percobaan_login (attempt_key, failures, last_failure_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT (attempt_key) DO UPDATE SET
+			failures = CASE
+				WHEN percobaan_login.last_failure_at < ? THEN 1
+				ELSE percobaan_login.failures + 1
+			END,
+			locked_until = CASE
+				WHEN percobaan_login.last_failure_at < ? THEN NULL
+				ELSE percobaan_login.locked_until
+			END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING attempt_key, failures, last_failure_at, locked_until
+	`, key, now, now.Add(-window), now.Add(-window)).Scan(&attempt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// Lock mengunci kunci sampai waktu tertentu
+func (r *loginAttemptRepositoryImpl) Lock(ctx context.Context, key string, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.LoginAttempt{}).
+		Where("attempt_key = ?", key).
+		Update("locked_until", until).Error
+}
+
+// Reset menghapus catatan kegagalan untuk kunci
+func (r *loginAttemptRepositoryImpl) Reset(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Where("attempt_key = ?", key).Delete(&domain.LoginAttempt{}).Error
+}
+
+// memoryLoginAttemptRepository adalah implementasi in-memory dari LoginAttemptRepository
+type memoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempt
+	// retention adalah lama catatan disimpan setelah kegagalan terakhir atau akhir penguncian
+	retention time.Duration
+}
+
+// loginAttemptMemoryMaxEntries adalah batas jumlah entri sebelum catatan lama dibersihkan
+const loginAttemptMemoryMaxEntries = 10000
+
+// NewMemoryLoginAttemptRepository membuat instance baru dari LoginAttemptRepository yang disimpan di memori.
+// Catatan yang tidak aktif lebih lama dari retention dibuang saat penyimpanan penuh.
+func NewMemoryLoginAttemptRepository(retention time.Duration) LoginAttemptRepository {
+	return &memoryLoginAttemptRepository{
+		attempts:  make(map[string]domain.LoginAttempt),
+		retention: retention,
+	}
+}
+
+// Get mendapatkan status percobaan login untuk kunci
+func (r *memoryLoginAttemptRepository) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	return &attempt, nil
+}
+
+// RecordFailure menambah jumlah kegagalan untuk kunci
+func (r *memoryLoginAttemptRepository) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*domain.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok || attempt.LastFailureAt.Before(now.Add(-window)) {
+		if len(r.attempts) >= loginAttemptMemoryMaxEntries {
+			r.cleanup(now)
+		}
+		attempt = domain.LoginAttempt{Key: key}
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	r.attempts[key] = attempt
+
+	return &attempt, nil
+}
+
+// Lock mengunci kunci sampai waktu tertentu
+func (r *memoryLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil
+	}
+	attempt.LockedUntil = &until
+	r.attempts[key] = attempt
+	return nil
+}
+
+// Reset menghapus catatan kegagalan untuk kunci
+func (r *memoryLoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+// cleanup membuang catatan yang sudah tidak aktif. Harus dipanggil dengan mu terkunci.
+func (r *memoryLoginAttemptRepository) cleanup(now time.Time) {
+	for key, attempt := range r.attempts {
+		lastActive := attempt.LastFailureAt
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(lastActive) {
+			lastActive = *attempt.LockedUntil
+		}
+		if now.Sub(lastActive) > r.retention {
+			delete(r.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// NotificationRepository adalah interface untuk operasi database notifikasi
+type NotificationRepository interface {
+	// CreateForFollowers membuat salinan notifikasi untuk setiap pengikut penjual dalam satu query.
+	// Mengembalikan jumlah notifikasi yang dibuat.
+	CreateForFollowers(ctx context.Context, penjualID uint, notification *domain.Notification) (int64, error)
+
+	// FindByPenggunaID mencari notifikasi pengguna dengan paginasi, terbaru lebih dulu
+	FindByPenggunaID(ctx context.Context, penggunaID uint, unreadOnly bool, page, limit int) ([]domain.Notification, int64, error)
+
+	// CountUnread menghitung notifikasi pengguna yang belum dibaca
+	CountUnread(ctx context.Context, penggunaID uint) (int64, error)
+
+	// MarkAsRead menandai notifikasi sebagai sudah dibaca.
+	// Mengembalikan false jika notifikasi tidak ditemukan atau bukan milik pengguna.
+	MarkAsRead(ctx context.Context, id, penggunaID uint) (bool, error)
+
+	// MarkAllAsRead menandai semua notifikasi pengguna sebagai sudah dibaca
+	// dan mengembalikan jumlah notifikasi yang diubah
+	MarkAllAsRead(ctx context.Context, penggunaID uint) (int64, error)
+}
+
+// notificationRepositoryImpl adalah implementasi PostgreSQL dari NotificationRepository
+type notificationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository membuat instance baru dari NotificationRepository
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepositoryImpl{
+		db: db,
+	}
+}
+
+// CreateForFollowers membuat salinan notifikasi untuk setiap pengikut penjual
+func (r *notificationRepositoryImpl) CreateForFollowers(ctx context.Context, penjualID uint, notification *domain.Notification) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO notifikasi (pengguna_id, jenis, judul, pesan, barang_id, created_at)
+		SELECT pengikut_id, ?, ?, ?, ?, ?
+		FROM mengikuti
+		WHERE diikuti_id = ?
+	`, notification.Jenis, notification.Judul, notification.Pesan, notification.BarangID, time.Now(), penjualID)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// FindByPenggunaID mencari notifikasi pengguna dengan paginasi
+func (r *notificationRepositoryImpl) FindByPenggunaID(ctx context.Context, penggunaID uint, unreadOnly bool, page, limit int) ([]domain.Notification, int64, error) {
+	var notifications []domain.Notification
+	var total int64
+
+	// Hitung offset berdasarkan halaman dan batas
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&domain.Notification{}).Where("pengguna_id = ?", penggunaID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	// Hitung total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Jalankan query dengan paginasi
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+// CountUnread menghitung notifikasi pengguna yang belum dibaca
+func (r *notificationRepositoryImpl) CountUnread(ctx context.Context, penggunaID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Notification{}).
+		Where("pengguna_id = ? AND read_at IS NULL", penggunaID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkAsRead menandai notifikasi sebagai sudah dibaca
+func (r *notificationRepositoryImpl) MarkAsRead(ctx context.Context, id, penggunaID uint) (bool, error) {
+	// COALESCE mempertahankan waktu baca pertama jika notifikasi sudah pernah dibaca
+	result := r.db.WithContext(ctx).Model(&domain.Notification{}).
+		Where("id = ? AND pengguna_id = ?", id, penggunaID).
+		UpdateColumn("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkAllAsRead menandai semua notifikasi pengguna sebagai sudah dibaca
+func (r *notificationRepositoryImpl) MarkAllAsRead(ctx context.Context, penggunaID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&domain.Notification{}).
+		Where("pengguna_id = ? AND read_at IS NULL", penggunaID).
+		UpdateColumn("read_at", time.Now())
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// OIDCIdentityRepository adalah interface untuk operasi database identitas OpenID Connect
+type OIDCIdentityRepository interface {
+	// Create menghubungkan identitas penyedia dengan pengguna
+	Create(ctx context.Context, identity *domain.OIDCIdentity) error
+
+	// FindBySubject mencari identitas berdasarkan nama penyedia dan subject
+	FindBySubject(ctx context.Context, provider, subject string) (*domain.OIDCIdentity, error)
+
+	// TouchLogin mencatat waktu login terakhir melalui identitas ini
+	TouchLogin(ctx context.Context, id uint, now time.Time) error
+}
+
+// oidcIdentityRepositoryImpl adalah implementasi PostgreSQL dari OIDCIdentityRepository
+type oidcIdentityRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOIDCIdentityRepository membuat instance baru dari OIDCIdentityRepository
+func NewOIDCIdentityRepository(db *gorm.DB) OIDCIdentityRepository {
+	return &oidcIdentityRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create menghubungkan identitas penyedia dengan pengguna
+func (r *oidcIdentityRepositoryImpl) Create(ctx context.Context, identity *domain.OIDCIdentity) error {
+	return r.db.WithContext(ctx).Create(identity).Error
+}
+
+// FindBySubject mencari identitas berdasarkan nama penyedia dan subject
+func (r *oidcIdentityRepositoryImpl) FindBySubject(ctx context.Context, provider, subject string) (*domain.OIDCIdentity, error) {
+	var identity domain.OIDCIdentity
+	if err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("identitas tidak ditemukan")
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// TouchLogin mencatat waktu login terakhir melalui identitas ini
+func (r *oidcIdentityRepositoryImpl) TouchLogin(ctx context.Context, id uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.OIDCIdentity{}).
+		Where("id = ?", id).
+		Update("last_login_at", now).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OIDCStateRepository adalah interface untuk operasi database state login OpenID Connect
+type OIDCStateRepository interface {
+	// Create menyimpan state login baru
+	Create(ctx context.Context, state *domain.OIDCState) error
+
+	// Consume mengambil lalu menghapus state berdasarkan hash-nya sehingga state hanya bisa dipakai sekali
+	Consume(ctx context.Context, stateHash string) (*domain.OIDCState, error)
+
+	// DeleteExpired menghapus state yang sudah kedaluwarsa
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+// oidcStateRepositoryImpl adalah implementasi PostgreSQL dari OIDCStateRepository
+type oidcStateRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOIDCStateRepository membuat instance baru dari OIDCStateRepository
+func NewOIDCStateRepository(db *gorm.DB) OIDCStateRepository {
+	return &oidcStateRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create menyimpan state login baru
+func (r *oidcStateRepositoryImpl) Create(ctx context.Context, state *domain.OIDCState) error {
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+// Consume mengambil lalu menghapus state berdasarkan hash-nya
+func (r *oidcStateRepositoryImpl) Consume(ctx context.Context, stateHash string) (*domain.OIDCState, error) {
+	// DELETE ... RETURNING agar dua callback dengan state yang sama tidak sama-sama berhasil
+	var states []domain.OIDCState
+	result := r.db.WithContext(ctx).Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(states) == 0 {
+		return nil, fmt.Errorf("state login tidak ditemukan")
+	}
+	return &states[0], nil
+}
+
+// DeleteExpired menghapus state yang sudah kedaluwarsa
+func (r *oidcStateRepositoryImpl) DeleteExpired(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&domain.OIDCState{}).Error
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// PasswordResetRepository adalah interface untuk operasi database token reset password
+type PasswordResetRepository interface {
+	// Create menyimpan token reset password baru
+	Create(ctx context.Context, reset *domain.PasswordReset) error
+
+	// FindByHash mencari token reset password berdasarkan hash token
+	FindByHash(ctx context.Context, tokenHash string) (*domain.PasswordReset, error)
+
+	// MarkUsed menandai token sebagai sudah dipakai.
+	// Mengembalikan false jika token sudah dipakai sebelumnya.
+	MarkUsed(ctx context.Context, id uint) (bool, error)
+
+	// InvalidateByUserID menandai semua token milik pengguna yang belum dipakai sebagai sudah dipakai
+	InvalidateByUserID(ctx context.Context, userID uint) error
+}
+
+// passwordResetRepositoryImpl adalah implementasi PostgreSQL dari PasswordResetRepository
+type passwordResetRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetRepository membuat instance baru dari PasswordResetRepository
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create menyimpan token reset password baru
+func (r *passwordResetRepositoryImpl) Create(ctx context.Context, reset *domain.PasswordReset) error {
+	return r.db.WithContext(ctx).Create(reset).Error
+}
+
+// FindByHash mencari token reset password berdasarkan hash token
+func (r *passwordResetRepositoryImpl) FindByHash(ctx context.Context, tokenHash string) (*domain.PasswordReset, error) {
+	var reset domain.PasswordReset
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&reset).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("token reset password tidak ditemukan")
+		}
+		return nil, err
+	}
+	return &reset, nil
+}
+
+// MarkUsed menandai token sebagai sudah dipakai
+func (r *passwordResetRepositoryImpl) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.PasswordReset{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// InvalidateByUserID menandai semua token milik pengguna yang belum dipakai sebagai sudah dipakai
+func (r *passwordResetRepositoryImpl) InvalidateByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&domain.PasswordReset{}).
+		Where("pengguna_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
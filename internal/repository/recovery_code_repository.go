@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// RecoveryCodeRepository adalah interface untuk operasi database kode pemulihan 2FA
+type RecoveryCodeRepository interface {
+	// ReplaceForUser menghapus kode pemulihan lama dan menyimpan kode baru milik pengguna
+	ReplaceForUser(ctx context.Context, userID uint, codeHashes []string) error
+
+	// Consume menandai kode pemulihan sebagai sudah dipakai.
+	// Mengembalikan false jika kode tidak ditemukan atau sudah dipakai.
+	Consume(ctx context.Context, userID uint, codeHash string) (bool, error)
+
+	// CountUnused menghitung kode pemulihan yang belum dipakai
+	CountUnused(ctx context.Context, userID uint) (int64, error)
+
+	// DeleteByUserID menghapus semua kode pemulihan milik pengguna
+	DeleteByUserID(ctx context.Context, userID uint) error
+}
+
+// recoveryCodeRepositoryImpl adalah implementasi PostgreSQL dari RecoveryCodeRepository
+type recoveryCodeRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecoveryCodeRepository membuat instance baru dari RecoveryCodeRepository
+func NewRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepositoryImpl{
+		db: db,
+	}
+}
+
+// ReplaceForUser menghapus kode pemulihan lama dan menyimpan kode baru milik pengguna
+func (r *recoveryCodeRepositoryImpl) ReplaceForUser(ctx context.Context, userID uint, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("pengguna_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]domain.RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, domain.RecoveryCode{
+				PenggunaID: userID,
+				CodeHash:   hash,
+			})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// Consume menandai kode pemulihan sebagai sudah dipakai
+func (r *recoveryCodeRepositoryImpl) Consume(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.RecoveryCode{}).
+		Where("pengguna_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountUnused menghitung kode pemulihan yang belum dipakai
+func (r *recoveryCodeRepositoryImpl) CountUnused(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.RecoveryCode{}).
+		Where("pengguna_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteByUserID menghapus semua kode pemulihan milik pengguna
+func (r *recoveryCodeRepositoryImpl) DeleteByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("pengguna_id = ?", userID).Delete(&domain.RecoveryCode{}).Error
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepository adalah interface untuk operasi database refresh token
+type RefreshTokenRepository interface {
+	// Create menyimpan refresh token baru
+	Create(ctx context.Context, token *domain.RefreshToken) error
+
+	// FindByHash mencari refresh token berdasarkan hash token
+	FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+
+	// MarkUsed menandai refresh token sebagai sudah dipakai.
+	// Mengembalikan false jika token sudah dipakai atau dicabut sebelumnya.
+	MarkUsed(ctx context.Context, id uint) (bool, error)
+
+	// RevokeFamily mencabut semua refresh token dalam satu family
+	RevokeFamily(ctx context.Context, familyID string) error
+
+	// RevokeByUserID mencabut semua refresh token milik pengguna
+	RevokeByUserID(ctx context.Context, userID uint) error
+}
+
+// refreshTokenRepositoryImpl adalah implementasi PostgreSQL dari RefreshTokenRepository
+type refreshTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository membuat instance baru dari RefreshTokenRepository
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create menyimpan refresh token baru
+func (r *refreshTokenRepositoryImpl) Create(ctx context.Context, token *domain.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// FindByHash mencari refresh token berdasarkan hash token
+func (r *refreshTokenRepositoryImpl) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("refresh token tidak ditemukan")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed menandai refresh token sebagai sudah dipakai
+func (r *refreshTokenRepositoryImpl) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	// Update bersyarat agar dua request refresh yang bersamaan tidak sama-sama berhasil
+	result := r.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RevokeFamily mencabut semua refresh token dalam satu family
+func (r *refreshTokenRepositoryImpl) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeByUserID mencabut semua refresh token milik pengguna
+func (r *refreshTokenRepositoryImpl) RevokeByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("pengguna_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
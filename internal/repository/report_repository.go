@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ReportRepository adalah interface untuk operasi database laporan
+type ReportRepository interface {
+	// Create menyimpan laporan baru
+	Create(ctx context.Context, report *domain.Report) error
+
+	// FindByID mencari laporan beserta pelapor, moderator dan tindakan yang terkait
+	FindByID(ctx context.Context, id uint) (*domain.Report, error)
+
+	// FindQueue mencari laporan untuk antrean moderasi, laporan terlama lebih dulu
+	FindQueue(ctx context.Context, filter domain.ReportFilter, page, limit int) ([]domain.Report, int64, error)
+
+	// FindByPelaporID mencari laporan yang dibuat pengguna, terbaru lebih dulu
+	FindByPelaporID(ctx context.Context, pelaporID uint, page, limit int) ([]domain.Report, int64, error)
+
+	// HasPendingReport memeriksa apakah pelapor masih memiliki laporan terbuka terhadap target
+	HasPendingReport(ctx context.Context, pelaporID uint, targetType domain.ReportTargetType, targetID uint) (bool, error)
+
+	// CountPendingReporters menghitung pelapor berbeda yang laporannya terhadap target masih terbuka
+	CountPendingReporters(ctx context.Context, targetType domain.ReportTargetType, targetID uint) (int64, error)
+
+	// Update memperbarui laporan
+	Update(ctx context.Context, report *domain.Report) error
+
+	// ResolvePending menyelesaikan semua laporan terbuka lain terhadap target yang sama
+	// dengan status dan catatan yang sama
+	ResolvePending(ctx context.Context, report *domain.Report) error
+
+	// IsTargetHidden memeriksa apakah target laporan sedang disembunyikan
+	IsTargetHidden(ctx context.Context, targetType domain.ReportTargetType, targetID uint) (bool, error)
+
+	// SetTargetHidden menyembunyikan target (hiddenAt tidak nil) atau menampilkannya kembali (hiddenAt nil).
+	// Mengembalikan false jika status target tidak berubah.
+	SetTargetHidden(ctx context.Context, targetType domain.ReportTargetType, targetID uint, hiddenAt *time.Time) (bool, error)
+
+	// LinkAdminLogs menghubungkan catatan tindakan admin terhadap pemilik target dengan laporan.
+	// Mengembalikan jumlah catatan yang berhasil dihubungkan.
+	LinkAdminLogs(ctx context.Context, reportID, targetOwnerID uint, adminLogIDs []uint) (int64, error)
+}
+
+// reportRepositoryImpl adalah implementasi PostgreSQL dari ReportRepository
+type reportRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewReportRepository membuat instance baru dari ReportRepository
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepositoryImpl{
+		db: db,
+	}
+}
+
+// reportTargetTables memetakan jenis target laporan ke tabelnya
+var reportTargetTables = map[domain.ReportTargetType]string{
+	domain.ReportTargetItem: "barang",
+	domain.ReportTargetChat: "chat",
+	domain.ReportTargetUser: "pengguna",
+}
+
+// Create menyimpan laporan baru
+func (r *reportRepositoryImpl) Create(ctx context.Context, report *domain.Report) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
+
+// FindByID mencari laporan beserta pelapor, moderator dan tindakan yang terkait
+func (r *reportRepositoryImpl) FindByID(ctx context.Context, id uint) (*domain.Report, error) {
+	var report domain.Report
+	if err := r.withRelations(r.db.WithContext(ctx)).Where("id = ?", id).First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("laporan dengan ID %d tidak ditemukan", id)
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+// FindQueue mencari laporan untuk antrean moderasi
+func (r *reportRepositoryImpl) FindQueue(ctx context.Context, filter domain.ReportFilter, page, limit int) ([]domain.Report, int64, error) {
+	var reports []domain.Report
+	var total int64
+
+	// Hitung offset berdasarkan halaman dan batas
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&domain.Report{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.ModeratorID != 0 {
+		query = query.Where("moderator_id = ?", filter.ModeratorID)
+	}
+	if filter.Unassigned {
+		query = query.Where("moderator_id IS NULL")
+	}
+
+	// Hitung total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Jalankan query dengan paginasi
+	if err := r.withRelations(query).Offset(offset).Limit(limit).Order("created_at ASC, id ASC").Find(&reports).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return reports, total, nil
+}
+
+// FindByPelaporID mencari laporan yang dibuat pengguna
+func (r *reportRepositoryImpl) FindByPelaporID(ctx context.Context, pelaporID uint, page, limit int) ([]domain.Report, int64, error) {
+	var reports []domain.Report
+	var total int64
+
+	// Hitung offset berdasarkan halaman dan batas
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&domain.Report{}).Where("pelapor_id = ?", pelaporID)
+
+	// Hitung total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Jalankan query dengan paginasi
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&reports).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return reports, total, nil
+}
+
+// HasPendingReport memeriksa apakah pelapor masih memiliki laporan terbuka terhadap target
+func (r *reportRepositoryImpl) HasPendingReport(ctx context.Context, pelaporID uint, targetType domain.ReportTargetType, targetID uint) (bool, error) {
+	var count int64
+	if err := r.pendingForTarget(ctx, targetType, targetID).
+		Where("pelapor_id = ?", pelaporID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountPendingReporters menghitung pelapor berbeda yang laporannya terhadap target masih terbuka
+func (r *reportRepositoryImpl) CountPendingReporters(ctx context.Context, targetType domain.ReportTargetType, targetID uint) (int64, error) {
+	var count int64
+	if err := r.pendingForTarget(ctx, targetType, targetID).
+		Distinct("pelapor_id").
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Update memperbarui laporan
+func (r *reportRepositoryImpl) Update(ctx context.Context, report *domain.Report) error {
+	return r.db.WithContext(ctx).Omit("Pelapor", "Moderator", "Tindakan").Save(report).Error
+}
+
+// ResolvePending menyelesaikan semua laporan terbuka lain terhadap target yang sama
+func (r *reportRepositoryImpl) ResolvePending(ctx context.Context, report *domain.Report) error {
+	return r.pendingForTarget(ctx, report.TargetType, report.TargetID).
+		Where("id <> ?", report.ID).
+		Updates(map[string]interface{}{
+			"status":               report.Status,
+			"moderator_id":         report.ModeratorID,
+			"catatan_penyelesaian": report.CatatanPenyelesaian,
+			"resolved_at":          report.ResolvedAt,
+			"updated_at":           time.Now(),
+		}).Error
+}
+
+// IsTargetHidden memeriksa apakah target laporan sedang disembunyikan
+func (r *reportRepositoryImpl) IsTargetHidden(ctx context.Context, targetType domain.ReportTargetType, targetID uint) (bool, error) {
+	table, ok := reportTargetTables[targetType]
+	if !ok {
+		return false, fmt.Errorf("jenis target laporan %q tidak dikenal", targetType)
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Table(table).
+		Where("id = ? AND hidden_at IS NOT NULL", targetID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SetTargetHidden menyembunyikan atau menampilkan kembali target laporan
+func (r *reportRepositoryImpl) SetTargetHidden(ctx context.Context, targetType domain.ReportTargetType, targetID uint, hiddenAt *time.Time) (bool, error) {
+	table, ok := reportTargetTables[targetType]
+	if !ok {
+		return false, fmt.Errorf("jenis target laporan %q tidak dikenal", targetType)
+	}
+
+	query := r.db.WithContext(ctx).Table(table).Where("id = ?", targetID)
+	if hiddenAt != nil {
+		query = query.Where("hidden_at IS NULL")
+	} else {
+		query = query.Where("hidden_at IS NOT NULL")
+	}
+
+	result := query.UpdateColumn("hidden_at", hiddenAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// LinkAdminLogs menghubungkan catatan tindakan admin dengan laporan
+func (r *reportRepositoryImpl) LinkAdminLogs(ctx context.Context, reportID, targetOwnerID uint, adminLogIDs []uint) (int64, error) {
+	if len(adminLogIDs) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).Model(&domain.AdminLog{}).
+		Where("id IN ? AND target_id = ? AND laporan_id IS NULL", adminLogIDs, targetOwnerID).
+		Update("laporan_id", reportID)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// withRelations memuat relasi yang dibutuhkan antrean moderasi
+func (r *reportRepositoryImpl) withRelations(query *gorm.DB) *gorm.DB {
+	return query.Preload("Pelapor").Preload("Moderator").
+		Preload("Tindakan", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC, id ASC")
+		})
+}
+
+// pendingForTarget membuat query laporan terbuka terhadap target
+func (r *reportRepositoryImpl) pendingForTarget(ctx context.Context, targetType domain.ReportTargetType, targetID uint) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&domain.Report{}).
+		Where("target_type = ? AND target_id = ? AND status IN ?", targetType, targetID,
+			[]domain.ReportStatus{domain.ReportOpen, domain.ReportReviewing})
+}
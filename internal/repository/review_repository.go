@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ReviewRepository adalah interface untuk operasi database ulasan
+type ReviewRepository interface {
+	// Create menyimpan ulasan baru
+	Create(ctx context.Context, review *domain.Review) error
+
+	// FindByID mencari ulasan berdasarkan ID beserta penulisnya
+	FindByID(ctx context.Context, id uint) (*domain.Review, error)
+
+	// ExistsByTransactionAndAuthor memeriksa apakah penulis sudah mengulas transaksi
+	ExistsByTransactionAndAuthor(ctx context.Context, transactionID, authorID uint) (bool, error)
+
+	// FindByTransactionID mencari semua ulasan untuk satu transaksi
+	FindByTransactionID(ctx context.Context, transactionID uint) ([]domain.Review, error)
+
+	// FindVisibleByTargetID mencari ulasan yang tidak disembunyikan untuk pengguna dengan paginasi
+	FindVisibleByTargetID(ctx context.Context, targetID uint, page, limit int) ([]domain.Review, int64, error)
+
+	// Update memperbarui ulasan
+	Update(ctx context.Context, review *domain.Review) error
+
+	// RefreshUserRating menghitung ulang rata-rata rating dan jumlah ulasan pengguna
+	RefreshUserRating(ctx context.Context, userID uint) error
+}
+
+// reviewRepositoryImpl adalah implementasi PostgreSQL dari ReviewRepository
+type reviewRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository membuat instance baru dari ReviewRepository
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create menyimpan ulasan baru
+func (r *reviewRepositoryImpl) Create(ctx context.Context, review *domain.Review) error {
+	return r.db.WithContext(ctx).Create(review).Error
+}
+
+// FindByID mencari ulasan berdasarkan ID beserta penulisnya
+func (r *reviewRepositoryImpl) FindByID(ctx context.Context, id uint) (*domain.Review, error) {
+	var review domain.Review
+	if err := r.db.WithContext(ctx).Preload("Penulis").Where("id = ?", id).First(&review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("ulasan dengan ID %d tidak ditemukan", id)
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ExistsByTransactionAndAuthor memeriksa apakah penulis sudah mengulas transaksi
+func (r *reviewRepositoryImpl) ExistsByTransactionAndAuthor(ctx context.Context, transactionID, authorID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.Review{}).
+		Where("transaksi_id = ? AND penulis_id = ?", transactionID, authorID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindByTransactionID mencari semua ulasan untuk satu transaksi
+func (r *reviewRepositoryImpl) FindByTransactionID(ctx context.Context, transactionID uint) ([]domain.Review, error) {
+	var reviews []domain.Review
+	if err := r.db.WithContext(ctx).Preload("Penulis").
+		Where("transaksi_id = ?", transactionID).
+		Order("created_at ASC").
+		Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// FindVisibleByTargetID mencari ulasan yang tidak disembunyikan untuk pengguna dengan paginasi
+func (r *reviewRepositoryImpl) FindVisibleByTargetID(ctx context.Context, targetID uint, page, limit int) ([]domain.Review, int64, error) {
+	var reviews []domain.Review
+	var total int64
+
+	// Hitung offset berdasarkan halaman dan batas
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&domain.Review{}).Where("target_id = ? AND hidden_at IS NULL", targetID)
+
+	// Hitung total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Jalankan query dengan paginasi
+	if err := query.Preload("Penulis").Offset(offset).Limit(limit).Order("created_at DESC").Find(&reviews).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return reviews, total, nil
+}
+
+// Update memperbarui ulasan
+func (r *reviewRepositoryImpl) Update(ctx context.Context, review *domain.Review) error {
+	return r.db.WithContext(ctx).Omit("Penulis").Save(review).Error
+}
+
+// RefreshUserRating menghitung ulang rata-rata rating dan jumlah ulasan pengguna.
+// Agregat disimpan di tabel pengguna agar bisa ditampilkan pada setiap listing tanpa query tambahan.
+func (r *reviewRepositoryImpl) RefreshUserRating(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Exec(`
+		UPDATE pengguna SET
+			jumlah_ulasan = agregat.jumlah,
+			rating_rata_rata = agregat.rata_rata
+		FROM (
+			SELECT COUNT(*) AS jumlah, ROUND(AVG(rating)::numeric, 2) AS rata_rata
+			FROM ulasan
+			WHERE target_id = ? AND hidden_at IS NULL
+		) agregat
+		WHERE pengguna.id = ?
+	`, userID, userID).Error
+}
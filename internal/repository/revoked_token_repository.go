@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevokedTokenRepository adalah interface untuk penyimpanan token akses yang dicabut
+type RevokedTokenRepository interface {
+	// Revoke mencabut token akses berdasarkan jti sampai token tersebut kedaluwarsa
+	Revoke(ctx context.Context, jti string, userID uint, expiresAt time.Time) error
+
+	// IsRevoked memeriksa apakah token akses dengan jti tertentu sudah dicabut
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// revokedTokenRepositoryImpl adalah implementasi PostgreSQL dari RevokedTokenRepository
+type revokedTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRevokedTokenRepository membuat instance baru dari RevokedTokenRepository
+func NewRevokedTokenRepository(db *gorm.DB) RevokedTokenRepository {
+	return &revokedTokenRepositoryImpl{
+		db: db,
+	}
+}
+
+// Revoke mencabut token akses berdasarkan jti
+func (r *revokedTokenRepositoryImpl) Revoke(ctx context.Context, jti string, userID uint, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.RevokedToken{
+			JTI:        jti,
+			PenggunaID: userID,
+			ExpiresAt:  expiresAt,
+			RevokedAt:  time.Now(),
+		}).Error
+}
+
+// IsRevoked memeriksa apakah token akses sudah dicabut
+func (r *revokedTokenRepositoryImpl) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&domain.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// revocationCacheEntry menyimpan hasil pengecekan pencabutan token beserta masa berlakunya
+type revocationCacheEntry struct {
+	revoked bool
+	until   time.Time
+}
+
+// cachedRevokedTokenRepository menambahkan cache in-memory di depan RevokedTokenRepository
+// agar pengecekan token di setiap request tidak selalu menyentuh database
+type cachedRevokedTokenRepository struct {
+	next        RevokedTokenRepository
+	negativeTTL time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]revocationCacheEntry
+}
+
+// revocationCacheMaxEntries adalah batas jumlah entri sebelum cache dibersihkan
+const revocationCacheMaxEntries = 10000
+
+// NewCachedRevokedTokenRepository membungkus RevokedTokenRepository dengan cache in-memory.
+// Token yang dicabut disimpan di cache sampai kedaluwarsa, sedangkan hasil "belum dicabut"
+// hanya disimpan selama negativeTTL supaya pencabutan dari instance lain tetap cepat terlihat.
+func NewCachedRevokedTokenRepository(next RevokedTokenRepository, negativeTTL time.Duration) RevokedTokenRepository {
+	return &cachedRevokedTokenRepository{
+		next:        next,
+		negativeTTL: negativeTTL,
+		entries:     make(map[string]revocationCacheEntry),
+	}
+}
+
+// Revoke mencabut token dan langsung mencatatnya di cache
+func (r *cachedRevokedTokenRepository) Revoke(ctx context.Context, jti string, userID uint, expiresAt time.Time) error {
+	if err := r.next.Revoke(ctx, jti, userID, expiresAt); err != nil {
+		return err
+	}
+	r.store(jti, revocationCacheEntry{revoked: true, until: expiresAt})
+	return nil
+}
+
+// IsRevoked memeriksa cache terlebih dahulu sebelum bertanya ke database
+func (r *cachedRevokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	now := time.Now()
+
+	r.mu.RLock()
+	entry, ok := r.entries[jti]
+	r.mu.RUnlock()
+	if ok && now.Before(entry.until) {
+		return entry.revoked, nil
+	}
+
+	revoked, err := r.next.IsRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	until := now.Add(r.negativeTTL)
+	if revoked {
+		// Token yang sudah dicabut tidak akan pernah berlaku lagi
+		until = now.Add(24 * time.Hour)
+	}
+	r.store(jti, revocationCacheEntry{revoked: revoked, until: until})
+
+	return revoked, nil
+}
+
+// store menyimpan entri ke cache dan membuang entri yang sudah kedaluwarsa jika cache penuh
+func (r *cachedRevokedTokenRepository) store(jti string, entry revocationCacheEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.entries) >= revocationCacheMaxEntries {
+		now := time.Now()
+		for key, existing := range r.entries {
+			if !now.Before(existing.until) {
+				delete(r.entries, key)
+			}
+		}
+	}
+	r.entries[jti] = entry
+}
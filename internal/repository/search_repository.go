@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// SearchRepository adalah interface untuk operasi database log pencarian dan kueri populer
+type SearchRepository interface {
+	// LogBatch mencatat sekumpulan pencarian dalam satu transaksi dan menambahkan pencarian yang
+	// Dihitung ke agregasi kueri populer. Pencarian kueri yang sama oleh PencariHash yang sama
+	// dalam dedupeWindow sejak pencarian terakhir yang dihitung tidak dihitung lagi.
+	LogBatch(ctx context.Context, entries []domain.SearchLog, dedupeWindow time.Duration) error
+
+	// DeleteLogsBefore menghapus log pencarian yang dibuat sebelum before.
+	// Agregasi kueri populer tidak ikut berubah.
+	DeleteLogsBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// FindPopularQueries mencari kueri populer yang diawali prefix dan sudah dicari minimal minCount kali,
+	// paling sering dicari lebih dulu
+	FindPopularQueries(ctx context.Context, prefix string, minCount int64, limit int) ([]string, error)
+}
+
+// searchRepositoryImpl adalah implementasi PostgreSQL dari SearchRepository
+type searchRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSearchRepository membuat instance baru dari SearchRepository
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepositoryImpl{
+		db: db,
+	}
+}
+
+// searcherQuery adalah pasangan pencari dan kueri untuk deduplikasi kueri populer
+type searcherQuery struct {
+	pencariHash string
+	kueri       string
+}
+
+// LogBatch mencatat sekumpulan pencarian dan memperbarui agregasi kueri populer
+func (r *searchRepositoryImpl) LogBatch(ctx context.Context, entries []domain.SearchLog, dedupeWindow time.Duration) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lastCounted, err := r.findLastCounted(tx, entries, dedupeWindow)
+		if err != nil {
+			return err
+		}
+
+		counts, lastSearched := countPopularSearches(entries, lastCounted, dedupeWindow)
+
+		if err := tx.Create(&entries).Error; err != nil {
+			return err
+		}
+
+		// Agregasi diperbarui saat pencarian dicatat sehingga saran kueri
+		// cukup membaca satu tabel kecil melalui index awalan kueri
+		for query, count := range counts {
+			if err := tx.Exec(`INSERT INTO kueri_populer (kueri, jumlah, terakhir_dicari) VALUES (?, ?, ?)
+				ON CONFLICT (kueri) DO UPDATE SET jumlah = kueri_populer.jumlah + EXCLUDED.jumlah,
+					terakhir_dicari = GREATEST(kueri_populer.terakhir_dicari, EXCLUDED.terakhir_dicari)`,
+				query, count, lastSearched[query]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// findLastCounted mencari waktu pencarian terakhir yang dihitung ke kueri populer
+// untuk setiap pencari di batch, dalam dedupeWindow sebelum pencarian pertama di batch
+func (r *searchRepositoryImpl) findLastCounted(tx *gorm.DB, entries []domain.SearchLog, dedupeWindow time.Duration) (map[searcherQuery]time.Time, error) {
+	lastCounted := make(map[searcherQuery]time.Time)
+
+	var hashes []string
+	var earliest time.Time
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.Dihitung || entry.PencariHash == "" {
+			continue
+		}
+		if !seen[entry.PencariHash] {
+			seen[entry.PencariHash] = true
+			hashes = append(hashes, entry.PencariHash)
+		}
+		if earliest.IsZero() || entry.CreatedAt.Before(earliest) {
+			earliest = entry.CreatedAt
+		}
+	}
+	if len(hashes) == 0 || dedupeWindow <= 0 {
+		return lastCounted, nil
+	}
+
+	var rows []struct {
+		PencariHash string
+		Kueri       string
+		CreatedAt   time.Time
+	}
+	if err := tx.Model(&domain.SearchLog{}).
+		Select("pencari_hash, kueri, MAX(created_at) AS created_at").
+		Where("dihitung AND pencari_hash IN ? AND created_at > ?", hashes, earliest.Add(-dedupeWindow)).
+		Group("pencari_hash, kueri").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		lastCounted[searcherQuery{pencariHash: row.PencariHash, kueri: row.Kueri}] = row.CreatedAt
+	}
+
+	return lastCounted, nil
+}
+
+// countPopularSearches membatalkan Dihitung pada pencarian berulang oleh pencari yang sama dalam
+// dedupeWindow, lalu menjumlahkan kenaikan kueri populer dan waktu pencarian terakhir per kueri.
+// Entri diproses berurutan sehingga pencarian berulang di batch yang sama juga hanya dihitung sekali.
+func countPopularSearches(entries []domain.SearchLog, lastCounted map[searcherQuery]time.Time, dedupeWindow time.Duration) (map[string]int64, map[string]time.Time) {
+	counts := make(map[string]int64)
+	lastSearched := make(map[string]time.Time)
+	for i := range entries {
+		entry := &entries[i]
+		if !entry.Dihitung {
+			continue
+		}
+		if entry.PencariHash != "" {
+			key := searcherQuery{pencariHash: entry.PencariHash, kueri: entry.Kueri}
+			if last, ok := lastCounted[key]; ok && entry.CreatedAt.Sub(last) < dedupeWindow {
+				entry.Dihitung = false
+				continue
+			}
+			lastCounted[key] = entry.CreatedAt
+		}
+		counts[entry.Kueri]++
+		if entry.CreatedAt.After(lastSearched[entry.Kueri]) {
+			lastSearched[entry.Kueri] = entry.CreatedAt
+		}
+	}
+	return counts, lastSearched
+}
+
+// DeleteLogsBefore menghapus log pencarian yang lebih lama dari before
+func (r *searchRepositoryImpl) DeleteLogsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&domain.SearchLog{})
+	return result.RowsAffected, result.Error
+}
+
+// FindPopularQueries mencari kueri populer yang diawali prefix
+func (r *searchRepositoryImpl) FindPopularQueries(ctx context.Context, prefix string, minCount int64, limit int) ([]string, error) {
+	var queries []string
+	if err := r.db.WithContext(ctx).Model(&domain.PopularQuery{}).
+		Where("kueri LIKE ? AND jumlah >= ?", likePrefixPattern(prefix), minCount).
+		Order("jumlah DESC, kueri ASC").
+		Limit(limit).
+		Pluck("kueri", &queries).Error; err != nil {
+		return nil, err
+	}
+	return queries, nil
+}
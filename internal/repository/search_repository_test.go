@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+)
+
+func TestCountPopularSearches(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	search := func(hash, query string, minutes int, eligible bool) domain.SearchLog {
+		return domain.SearchLog{PencariHash: hash, Kueri: query, Dihitung: eligible, CreatedAt: at(minutes)}
+	}
+
+	// Pencari a sudah dihitung untuk "laptop" 30 menit sebelum batch, pencari b 2 jam sebelumnya
+	lastCounted := map[searcherQuery]time.Time{
+		{pencariHash: "a", kueri: "laptop"}: at(-30),
+		{pencariHash: "b", kueri: "laptop"}: at(-120),
+	}
+	entries := []domain.SearchLog{
+		search("a", "laptop", 0, true),  // masih dalam jendela pencarian sebelumnya
+		search("b", "laptop", 0, true),  // pencarian sebelumnya sudah di luar jendela
+		search("b", "laptop", 10, true), // berulang di batch yang sama
+		search("a", "sepeda", 0, true),  // kueri lain oleh pencari yang sama tetap dihitung
+		search("c", "sepeda", 5, true),  // pencari lain
+		search("c", "sepeda", 65, true), // jendela sejak pencarian terakhir yang dihitung sudah lewat
+		search("d", "kulkas", 0, false), // tidak layak dihitung
+		search("", "lemari", 0, true),   // tanpa hash pencari selalu dihitung
+		search("", "lemari", 1, true),
+	}
+
+	counts, lastSearched := countPopularSearches(entries, lastCounted, time.Hour)
+
+	wantCounts := map[string]int64{"laptop": 1, "sepeda": 3, "lemari": 2}
+	if len(counts) != len(wantCounts) {
+		t.Errorf("counts = %v, want %v", counts, wantCounts)
+	}
+	for query, want := range wantCounts {
+		if counts[query] != want {
+			t.Errorf("counts[%q] = %d, want %d", query, counts[query], want)
+		}
+	}
+
+	wantCounted := []bool{false, true, false, true, true, true, false, true, true}
+	for i, entry := range entries {
+		if entry.Dihitung != wantCounted[i] {
+			t.Errorf("entri %d (%s %q): Dihitung = %v, want %v", i, entry.PencariHash, entry.Kueri, entry.Dihitung, wantCounted[i])
+		}
+	}
+
+	wantLast := map[string]time.Time{"laptop": at(0), "sepeda": at(65), "lemari": at(1)}
+	for query, want := range wantLast {
+		if !lastSearched[query].Equal(want) {
+			t.Errorf("lastSearched[%q] = %v, want %v", query, lastSearched[query], want)
+		}
+	}
+}
+
+func TestCountPopularSearchesWithoutDedupeWindow(t *testing.T) {
+	entries := []domain.SearchLog{
+		{PencariHash: "a", Kueri: "laptop", Dihitung: true, CreatedAt: time.Now()},
+		{PencariHash: "a", Kueri: "laptop", Dihitung: true, CreatedAt: time.Now()},
+	}
+	counts, _ := countPopularSearches(entries, map[searcherQuery]time.Time{}, 0)
+	if counts["laptop"] != 2 {
+		t.Errorf("counts[laptop] = %d, want 2 tanpa jendela deduplikasi", counts["laptop"])
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"gorm.io/gorm"
+)
+
+// SessionRepository adalah interface untuk operasi database sesi login
+type SessionRepository interface {
+	// Create menyimpan sesi baru
+	Create(ctx context.Context, session *domain.Session) error
+
+	// FindByID mencari sesi berdasarkan ID
+	FindByID(ctx context.Context, id string) (*domain.Session, error)
+
+	// FindActiveByUserID mencari sesi yang belum diakhiri dan masih aktif setelah waktu tertentu
+	FindActiveByUserID(ctx context.Context, userID uint, activeSince time.Time) ([]domain.Session, error)
+
+	// Touch memperbarui waktu terakhir aktif, alamat IP dan user agent sesi.
+	// Update hanya dilakukan jika last_seen_at lebih lama dari staleBefore agar tidak menulis ke database di setiap request.
+	Touch(ctx context.Context, id string, client domain.ClientInfo, now, staleBefore time.Time) error
+
+	// Revoke mengakhiri sesi
+	Revoke(ctx context.Context, id string) error
+
+	// RevokeByUserID mengakhiri semua sesi milik pengguna kecuali sesi dengan ID exceptID
+	RevokeByUserID(ctx context.Context, userID uint, exceptID string) ([]string, error)
+}
+
+// sessionRepositoryImpl adalah implementasi PostgreSQL dari SessionRepository
+type sessionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository membuat instance baru dari SessionRepository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create menyimpan sesi baru
+func (r *sessionRepositoryImpl) Create(ctx context.Context, session *domain.Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// FindByID mencari sesi berdasarkan ID
+func (r *sessionRepositoryImpl) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	var session domain.Session
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("sesi tidak ditemukan")
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindActiveByUserID mencari sesi yang belum diakhiri dan masih aktif setelah waktu tertentu
+func (r *sessionRepositoryImpl) FindActiveByUserID(ctx context.Context, userID uint, activeSince time.Time) ([]domain.Session, error) {
+	var sessions []domain.Session
+	err := r.db.WithContext(ctx).
+		Where("pengguna_id = ? AND revoked_at IS NULL AND last_seen_at > ?", userID, activeSince).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Touch memperbarui waktu terakhir aktif, alamat IP dan user agent sesi
+func (r *sessionRepositoryImpl) Touch(ctx context.Context, id string, client domain.ClientInfo, now, staleBefore time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.Session{}).
+		Where("id = ? AND revoked_at IS NULL AND last_seen_at < ?", id, staleBefore).
+		Updates(map[string]interface{}{
+			"last_seen_at": now,
+			"ip_address":   client.IPAddress,
+			"user_agent":   client.UserAgent,
+		}).Error
+}
+
+// Revoke mengakhiri sesi
+func (r *sessionRepositoryImpl) Revoke(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&domain.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeByUserID mengakhiri semua sesi milik pengguna kecuali sesi dengan ID exceptID
+func (r *sessionRepositoryImpl) RevokeByUserID(ctx context.Context, userID uint, exceptID string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Model(&domain.Session{}).
+		Where("pengguna_id = ? AND revoked_at IS NULL AND id <> ?", userID, exceptID).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	err = r.db.WithContext(ctx).Model(&domain.Session{}).
+		Where("id IN ?", ids).
+		Update("revoked_at", time.Now()).Error
+	return ids, err
+}
@@ -85,7 +85,7 @@ func (r *transactionRepositoryImpl) FindAll(ctx context.Context, page, limit int
 	}
 
 	// Jalankan query dengan paginasi
-	if err := query.Preload("Barang").Preload("Pembeli").Offset(offset).Limit(limit).Order("created_at DESC").Find(&transactions).Error; err != nil {
+	if err := query.Preload("Barang").Preload("Barang.Penjual").Preload("Pembeli").Offset(offset).Limit(limit).Order("created_at DESC").Find(&transactions).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -109,7 +109,7 @@ func (r *transactionRepositoryImpl) FindByPembeliID(ctx context.Context, pembeli
 	}
 
 	// Jalankan query dengan paginasi
-	if err := query.Preload("Barang").Preload("Barang.Penjual").Offset(offset).Limit(limit).Order("created_at DESC").Find(&transactions).Error; err != nil {
+	if err := query.Preload("Barang").Preload("Barang.Penjual").Preload("Pembeli").Offset(offset).Limit(limit).Order("created_at DESC").Find(&transactions).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -137,6 +137,7 @@ func (r *transactionRepositoryImpl) FindByPenjualID(ctx context.Context, penjual
 	// Jalankan query dengan paginasi
 	if err := r.db.WithContext(ctx).
 		Preload("Barang").
+		Preload("Barang.Penjual").
 		Preload("Pembeli").
 		Joins("JOIN barang ON transaksi.barang_id = barang.id").
 		Where("barang.penjual_id = ?", penjualID).
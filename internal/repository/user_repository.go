@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
 	"gorm.io/gorm"
@@ -26,11 +27,20 @@ type UserRepository interface {
 	// Update memperbarui data pengguna
 	Update(ctx context.Context, user *domain.User) error
 	
-	// Delete menghapus pengguna (soft delete)
-	Delete(ctx context.Context, id uint) error
+	// UseTOTPStep mencatat langkah waktu TOTP yang baru dipakai.
+	// Mengembalikan false jika langkah tersebut (atau yang lebih baru) sudah pernah dipakai.
+	UseTOTPStep(ctx context.Context, id uint, step int64) (bool, error)
 	
-	// HardDelete menghapus pengguna secara permanen
-	HardDelete(ctx context.Context, id uint) error
+	// FindDueForAnonymization mencari pengguna yang masa tenggang penghapusannya sudah habis
+	FindDueForAnonymization(ctx context.Context, now time.Time) ([]domain.User, error)
+	
+	// Anonymize menghapus data pribadi pengguna tanpa menghapus barisnya, sehingga transaksi,
+	// chat dan ulasan pihak lain tetap utuh. Barang pengguna ikut dihapus (soft delete),
+	// transaksi pending dibatalkan dan semua kredensial login dicabut.
+	Anonymize(ctx context.Context, id uint, now time.Time) error
+
+	// GetStats menghitung statistik reputasi pengguna sebagai penjual
+	GetStats(ctx context.Context, id uint) (*domain.UserStats, error)
 }
 
 // userRepositoryImpl adalah implementasi PostgreSQL dari UserRepository
@@ -82,8 +92,8 @@ func (r *userRepositoryImpl) FindAll(ctx context.Context, page, limit int, searc
 	// Hitung offset berdasarkan halaman dan batas
 	offset := (page - 1) * limit
 
-	// Buat query dasar
-	query := r.db.WithContext(ctx).Model(&domain.User{})
+	// Buat query dasar. Akun yang sudah dianonimkan tidak ditampilkan.
+	query := r.db.WithContext(ctx).Model(&domain.User{}).Where("anonymized_at IS NULL")
 
 	// Tambahkan filter pencarian jika ada
 	if search != "" {
@@ -106,15 +116,141 @@ func (r *userRepositoryImpl) FindAll(ctx context.Context, page, limit int, searc
 
 // Update memperbarui data pengguna
 func (r *userRepositoryImpl) Update(ctx context.Context, user *domain.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	db := r.db.WithContext(ctx)
+	// Email dan nomor HP pengguna yang dianonimkan bernilai NULL dan tidak boleh ditimpa string kosong
+	if user.IsAnonymized() {
+		db = db.Omit("email", "no_hp")
+	}
+	return db.Save(user).Error
 }
 
-// Delete menghapus pengguna (soft delete)
-func (r *userRepositoryImpl) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&domain.User{}, id).Error
+// UseTOTPStep mencatat langkah waktu TOTP yang baru dipakai
+func (r *userRepositoryImpl) UseTOTPStep(ctx context.Context, id uint, step int64) (bool, error) {
+	// Update bersyarat agar kode yang sama tidak bisa dipakai dua kali
+	result := r.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FindDueForAnonymization mencari pengguna yang masa tenggang penghapusannya sudah habis
+func (r *userRepositoryImpl) FindDueForAnonymization(ctx context.Context, now time.Time) ([]domain.User, error) {
+	var users []domain.User
+	if err := r.db.WithContext(ctx).
+		Where("deletion_scheduled_at <= ? AND anonymized_at IS NULL", now).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
 }
 
-// HardDelete menghapus pengguna secara permanen
-func (r *userRepositoryImpl) HardDelete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Unscoped().Delete(&domain.User{}, id).Error
-}
\ No newline at end of file
+// Anonymize menghapus data pribadi pengguna tanpa menghapus barisnya
+func (r *userRepositoryImpl) Anonymize(ctx context.Context, id uint, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`
+			UPDATE pengguna SET
+				nama = ?, email = NULL, no_hp = NULL, alamat = NULL, password = '',
+				email_verified_at = NULL, totp_secret = NULL, totp_enabled_at = NULL,
+				avatar_file_id = NULL, avatar_url = NULL, avatar_thumb_url = NULL,
+				token_valid_after = ?, anonymized_at = ?, updated_at = ?
+			WHERE id = ? AND anonymized_at IS NULL
+		`, domain.AnonymizedUserName, now, now, now, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("pengguna dengan ID %d tidak ditemukan atau sudah dianonimkan", id)
+		}
+
+		statements := []struct {
+			query string
+			args  []interface{}
+		}{
+			// Barang yang sedang dibeli pengguna kembali tersedia untuk pembeli lain
+			{`UPDATE barang SET status = ?, updated_at = ? WHERE id IN (
+				SELECT barang_id FROM transaksi WHERE pembeli_id = ? AND status_transaksi = ?
+			)`, []interface{}{domain.StatusTersedia, now, id, domain.StatusPending}},
+			{`UPDATE transaksi SET status_transaksi = ?, updated_at = ?
+				WHERE status_transaksi = ? AND (pembeli_id = ? OR barang_id IN (SELECT id FROM barang WHERE penjual_id = ?))`,
+				[]interface{}{domain.StatusDibatalkan, now, domain.StatusPending, id, id}},
+			{`UPDATE barang SET deleted_at = ? WHERE penjual_id = ? AND deleted_at IS NULL`, []interface{}{now, id}},
+			// Arsip ekspor data dihapus oleh worker ekspor pada putaran berikutnya
+			{`UPDATE ekspor_data SET expires_at = ? WHERE pengguna_id = ? AND status = ?`, []interface{}{now, id, domain.DataExportReady}},
+			{`DELETE FROM sesi WHERE pengguna_id = ?`, []interface{}{id}},
+			{`DELETE FROM refresh_token WHERE pengguna_id = ?`, []interface{}{id}},
+			{`DELETE FROM token_akses WHERE pengguna_id = ?`, []interface{}{id}},
+			{`DELETE FROM kode_pemulihan WHERE pengguna_id = ?`, []interface{}{id}},
+			{`DELETE FROM reset_password WHERE pengguna_id = ?`, []interface{}{id}},
+			{`DELETE FROM identitas_oidc WHERE pengguna_id = ?`, []interface{}{id}},
+			{`DELETE FROM blokir WHERE pemblokir_id = ?`, []interface{}{id}},
+			{`DELETE FROM mengikuti WHERE pengikut_id = ? OR diikuti_id = ?`, []interface{}{id, id}},
+			{`DELETE FROM notifikasi WHERE pengguna_id = ?`, []interface{}{id}},
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement.query, statement.args...).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetStats menghitung statistik reputasi pengguna sebagai penjual
+func (r *userRepositoryImpl) GetStats(ctx context.Context, id uint) (*domain.UserStats, error) {
+	var stats domain.UserStats
+	db := r.db.WithContext(ctx)
+
+	// Barang yang sedang dijual
+	if err := db.Model(&domain.Item{}).
+		Where("penjual_id = ? AND status = ?", id, domain.StatusTersedia).
+		Count(&stats.JumlahBarang).Error; err != nil {
+		return nil, err
+	}
+
+	// Penjualan yang sudah selesai
+	if err := db.Model(&domain.Transaction{}).
+		Joins("JOIN barang ON barang.id = transaksi.barang_id").
+		Where("barang.penjual_id = ? AND transaksi.status_transaksi = ?", id, domain.StatusSelesai).
+		Count(&stats.JumlahTerjual).Error; err != nil {
+		return nil, err
+	}
+
+	// Percakapan dihitung per pasangan (barang, pembeli) yang mengirim pesan ke pengguna
+	// tentang barangnya, dan dianggap dibalas jika pengguna pernah mengirim pesan balik
+	var conversations struct {
+		Masuk   int64
+		Dibalas int64
+	}
+	if err := db.Raw(`
+		SELECT COUNT(*) AS masuk,
+			COUNT(*) FILTER (WHERE EXISTS (
+				SELECT 1 FROM chat balasan
+				WHERE balasan.barang_id = masuk.barang_id
+					AND balasan.pengirim_id = ?
+					AND balasan.penerima_id = masuk.pengirim_id
+			)) AS dibalas
+		FROM (
+			SELECT DISTINCT chat.barang_id, chat.pengirim_id
+			FROM chat
+			JOIN barang ON barang.id = chat.barang_id
+			WHERE chat.penerima_id = ? AND barang.penjual_id = ?
+		) masuk
+	`, id, id, id).Scan(&conversations).Error; err != nil {
+		return nil, err
+	}
+	stats.PercakapanMasuk = conversations.Masuk
+	stats.PercakapanDibalas = conversations.Dibalas
+
+	// Pengikut dan penjual yang diikuti
+	if err := db.Model(&domain.Follow{}).Where("diikuti_id = ?", id).Count(&stats.JumlahPengikut).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&domain.Follow{}).Where("pengikut_id = ?", id).Count(&stats.JumlahMengikuti).Error; err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+const (
+	// maxAccessTokensPerUser adalah jumlah maksimum personal access token aktif per pengguna
+	maxAccessTokensPerUser = 20
+	// maxAccessTokenDays adalah masa berlaku maksimum personal access token dalam hari
+	maxAccessTokenDays = 365
+	// accessTokenTouchInterval adalah jeda minimum antar pembaruan waktu terakhir token dipakai
+	accessTokenTouchInterval = time.Minute
+)
+
+// AccessTokenService adalah interface untuk layanan personal access token
+type AccessTokenService interface {
+	Create(ctx context.Context, userID uint, req domain.CreateAccessTokenRequest) (*domain.AccessTokenResponse, error)
+	List(ctx context.Context, userID uint) ([]domain.AccessTokenResponse, error)
+	Revoke(ctx context.Context, userID, tokenID uint) error
+	Authenticate(ctx context.Context, token string) (*domain.AccessToken, *domain.User, error)
+}
+
+// accessTokenService adalah implementasi dari AccessTokenService
+type accessTokenService struct {
+	accessTokenRepo repository.AccessTokenRepository
+	userRepo        repository.UserRepository
+}
+
+// NewAccessTokenService membuat instance baru dari AccessTokenService
+func NewAccessTokenService(accessTokenRepo repository.AccessTokenRepository, userRepo repository.UserRepository) AccessTokenService {
+	return &accessTokenService{
+		accessTokenRepo: accessTokenRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// Create membuat personal access token baru. Token hanya dikembalikan sekali
+// karena yang disimpan di database hanya hash-nya.
+func (s *accessTokenService) Create(ctx context.Context, userID uint, req domain.CreateAccessTokenRequest) (*domain.AccessTokenResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, errors.New("nama token wajib diisi dan maksimal 100 karakter")
+	}
+	if len(req.Scopes) == 0 {
+		return nil, errors.New("minimal satu scope wajib dipilih")
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > maxAccessTokenDays {
+		return nil, errors.New("masa berlaku token harus antara 0 dan 365 hari")
+	}
+
+	// Validasi scope dan buang duplikat
+	seen := make(map[domain.Scope]bool, len(req.Scopes))
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if !scope.IsValid() {
+			return nil, errors.New("scope tidak dikenal: " + string(scope))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, string(scope))
+		}
+	}
+
+	count, err := s.accessTokenRepo.CountActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxAccessTokensPerUser {
+		return nil, errors.New("jumlah personal access token sudah mencapai batas, cabut token yang tidak dipakai")
+	}
+
+	secret, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	rawToken := domain.AccessTokenPrefix + secret
+
+	token := &domain.AccessToken{
+		PenggunaID:  userID,
+		Name:        name,
+		TokenHash:   utils.HashToken(rawToken),
+		TokenPrefix: rawToken[:len(domain.AccessTokenPrefix)+4],
+		Scopes:      strings.Join(scopes, " "),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := s.accessTokenRepo.Create(ctx, token); err != nil {
+		return nil, err
+	}
+
+	response := token.ToResponse()
+	response.Token = rawToken
+	return &response, nil
+}
+
+// List mendapatkan personal access token milik pengguna yang belum dicabut
+func (s *accessTokenService) List(ctx context.Context, userID uint) ([]domain.AccessTokenResponse, error) {
+	tokens, err := s.accessTokenRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]domain.AccessTokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		responses = append(responses, token.ToResponse())
+	}
+	return responses, nil
+}
+
+// Revoke mencabut personal access token milik pengguna
+func (s *accessTokenService) Revoke(ctx context.Context, userID, tokenID uint) error {
+	revoked, err := s.accessTokenRepo.Revoke(ctx, tokenID, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("personal access token tidak ditemukan")
+	}
+	return nil
+}
+
+// Authenticate memvalidasi personal access token dan mengembalikan token beserta pemiliknya
+func (s *accessTokenService) Authenticate(ctx context.Context, rawToken string) (*domain.AccessToken, *domain.User, error) {
+	token, err := s.accessTokenRepo.FindByHash(ctx, utils.HashToken(rawToken))
+	if err != nil {
+		return nil, nil, errors.New("token tidak valid")
+	}
+
+	now := time.Now()
+	if !token.IsActive(now) {
+		return nil, nil, errors.New("token sudah tidak berlaku")
+	}
+
+	// Pengguna yang sudah dihapus tidak bisa memakai tokennya lagi
+	user, err := s.userRepo.FindByID(ctx, token.PenggunaID)
+	if err != nil {
+		return nil, nil, errors.New("token sudah tidak berlaku")
+	}
+	if err := accountStatusError(user); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.accessTokenRepo.TouchLastUsed(ctx, token.ID, now, now.Add(-accessTokenTouchInterval)); err != nil {
+		return nil, nil, err
+	}
+
+	return token, user, nil
+}
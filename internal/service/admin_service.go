@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+)
+
+// AdminService adalah interface untuk layanan manajemen pengguna oleh admin.
+// Admin yang melakukan tindakan diambil dari principal pada context.
+type AdminService interface {
+	ChangeRole(ctx context.Context, targetID uint, req domain.ChangeRoleRequest) (*domain.UserResponse, error)
+	Suspend(ctx context.Context, targetID uint, req domain.SuspendUserRequest) (*domain.UserResponse, error)
+	Unsuspend(ctx context.Context, targetID uint, req domain.UnsuspendUserRequest) (*domain.UserResponse, error)
+	GetLogs(ctx context.Context, page, limit int, targetID uint) ([]domain.AdminLog, int, int64, error)
+}
+
+// adminService adalah implementasi dari AdminService
+type adminService struct {
+	userRepo         repository.UserRepository
+	adminLogRepo     repository.AdminLogRepository
+	sessionRepo      repository.SessionRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+}
+
+// NewAdminService membuat instance baru dari AdminService
+func NewAdminService(
+	userRepo repository.UserRepository,
+	adminLogRepo repository.AdminLogRepository,
+	sessionRepo repository.SessionRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+) AdminService {
+	return &adminService{
+		userRepo:         userRepo,
+		adminLogRepo:     adminLogRepo,
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+	}
+}
+
+// ChangeRole mengubah role pengguna. Semua sesi pengguna diakhiri agar role baru langsung berlaku.
+func (s *adminService) ChangeRole(ctx context.Context, targetID uint, req domain.ChangeRoleRequest) (*domain.UserResponse, error) {
+	principal, err := s.authorize(ctx, policy.UserChangeRole)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.Role.IsValid() {
+		return nil, errors.ValidationError("role tidak valid", nil)
+	}
+	// Admin tidak bisa menurunkan role sendiri agar tidak kehilangan akses tanpa sengaja
+	if targetID == principal.UserID {
+		return nil, errors.ValidationError("anda tidak dapat mengubah role sendiri", nil)
+	}
+
+	user, err := s.findUser(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == req.Role {
+		return nil, errors.ValidationError("pengguna sudah memiliki role "+string(req.Role), nil)
+	}
+
+	oldRole := user.Role
+	user.Role = req.Role
+	// Token lama membawa role lama, sehingga harus dianggap tidak berlaku
+	user.InvalidateTokens(time.Now())
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.revokeAccess(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.record(ctx, principal, user.ID, domain.AdminActionChangeRole, fmt.Sprintf("%s -> %s", oldRole, req.Role), req.Reason); err != nil {
+		return nil, err
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// Suspend menangguhkan pengguna hingga waktu tertentu, atau permanen jika Until kosong.
+// Semua sesi pengguna diakhiri dan transaksi pending-nya dibekukan selama penangguhan.
+func (s *adminService) Suspend(ctx context.Context, targetID uint, req domain.SuspendUserRequest) (*domain.UserResponse, error) {
+	principal, err := s.authorize(ctx, policy.UserSuspend)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.ValidationError("alasan penangguhan wajib diisi", nil)
+	}
+	now := time.Now()
+	if req.Until != nil && !req.Until.After(now) {
+		return nil, errors.ValidationError("akhir penangguhan harus di masa depan", nil)
+	}
+	if targetID == principal.UserID {
+		return nil, errors.ValidationError("anda tidak dapat menangguhkan akun sendiri", nil)
+	}
+
+	user, err := s.findUser(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == domain.RoleAdmin {
+		return nil, errors.ValidationError("admin tidak dapat ditangguhkan, ubah role-nya terlebih dahulu", nil)
+	}
+
+	user.SuspendedAt = &now
+	user.SuspendedUntil = req.Until
+	user.SuspendReason = reason
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.revokeAccess(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	detail := "permanen"
+	if req.Until != nil {
+		detail = "hingga " + req.Until.Format(time.RFC3339)
+	}
+	if err := s.record(ctx, principal, user.ID, domain.AdminActionSuspend, detail, reason); err != nil {
+		return nil, err
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// Unsuspend mencabut penangguhan pengguna sebelum masa berlakunya habis
+func (s *adminService) Unsuspend(ctx context.Context, targetID uint, req domain.UnsuspendUserRequest) (*domain.UserResponse, error) {
+	principal, err := s.authorize(ctx, policy.UserSuspend)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.findUser(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsSuspended(time.Now()) {
+		return nil, errors.ValidationError("pengguna tidak sedang ditangguhkan", nil)
+	}
+
+	user.SuspendedAt = nil
+	user.SuspendedUntil = nil
+	user.SuspendReason = ""
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.record(ctx, principal, user.ID, domain.AdminActionUnsuspend, "", strings.TrimSpace(req.Reason)); err != nil {
+		return nil, err
+	}
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// GetLogs mendapatkan catatan tindakan admin dengan paginasi, opsional difilter per pengguna
+func (s *adminService) GetLogs(ctx context.Context, page, limit int, targetID uint) ([]domain.AdminLog, int, int64, error) {
+	if _, err := s.authorize(ctx, policy.AdminLogRead); err != nil {
+		return nil, 0, 0, err
+	}
+
+	// Validasi input paginasi
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	logs, total, err := s.adminLogRepo.FindAll(ctx, page, limit, targetID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// Hitung total halaman
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return logs, totalPages, total, nil
+}
+
+// authorize memastikan principal pada context memiliki izin untuk action
+func (s *adminService) authorize(ctx context.Context, action policy.Action) (policy.Principal, error) {
+	principal, ok := policy.FromContext(ctx)
+	if !ok || !policy.Can(principal, action, nil) {
+		return principal, errors.ForbiddenError("akses ditolak: tidak memiliki izin", policy.ErrForbidden)
+	}
+	return principal, nil
+}
+
+// findUser mencari pengguna target dan mengembalikan NotFoundError jika tidak ada
+func (s *adminService) findUser(ctx context.Context, id uint) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NotFoundError("pengguna tidak ditemukan", err)
+	}
+	// Akun yang sudah dianonimkan tidak bisa dikelola lagi
+	if user.IsAnonymized() {
+		return nil, errors.NotFoundError("pengguna sudah dihapus", nil)
+	}
+	return user, nil
+}
+
+// revokeAccess mengakhiri semua sesi dan mencabut refresh token pengguna
+func (s *adminService) revokeAccess(ctx context.Context, userID uint) error {
+	if _, err := s.sessionRepo.RevokeByUserID(ctx, userID, ""); err != nil {
+		return err
+	}
+	return s.refreshTokenRepo.RevokeByUserID(ctx, userID)
+}
+
+// record mencatat tindakan admin beserta admin yang melakukannya
+func (s *adminService) record(ctx context.Context, principal policy.Principal, targetID uint, action domain.AdminAction, detail, reason string) error {
+	return s.adminLogRepo.Create(ctx, &domain.AdminLog{
+		AdminID:  principal.UserID,
+		TargetID: targetID,
+		Action:   action,
+		Detail:   detail,
+		Reason:   reason,
+	})
+}
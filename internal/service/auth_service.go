@@ -2,33 +2,90 @@ package service
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/mfuadfakhruzzaki/jubel/internal/config"
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/mailer"
 	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
 	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
 )
 
+const (
+	// mfaIssuer adalah nama penerbit yang tampil di aplikasi authenticator
+	mfaIssuer = "Jubel"
+	// recoveryCodeCount adalah jumlah kode pemulihan yang dibuat sekaligus
+	recoveryCodeCount = 10
+	// sessionTouchInterval adalah jeda minimum antar pembaruan waktu terakhir aktif sesi
+	sessionTouchInterval = time.Minute
+)
+
 // AuthService adalah interface untuk layanan autentikasi
 type AuthService interface {
 	Register(ctx context.Context, user *domain.User) (uint, error)
-	Login(ctx context.Context, email, password string) (string, *domain.UserResponse, error)
+	Login(ctx context.Context, email, password string, client domain.ClientInfo) (*domain.LoginResult, error)
+	CompleteMFALogin(ctx context.Context, mfaToken, code string, client domain.ClientInfo) (*domain.AuthTokens, *domain.UserResponse, error)
+	LoginExternal(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.LoginResult, error)
+	RefreshToken(ctx context.Context, refreshToken string, client domain.ClientInfo) (*domain.AuthTokens, error)
+	Logout(ctx context.Context, userID uint, tokenID, sessionID string, tokenExpiresAt time.Time, refreshToken string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerificationEmail(ctx context.Context, userID uint) error
+	IsEmailVerified(ctx context.Context, userID uint) (bool, error)
+	EnrollMFA(ctx context.Context, userID uint) (*domain.MFAEnrollment, error)
+	ConfirmMFA(ctx context.Context, userID uint, code string) ([]string, error)
+	DisableMFA(ctx context.Context, userID uint, password, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error)
 	ValidateToken(ctx context.Context, token string) (*utils.JWTClaims, error)
+	TouchSession(ctx context.Context, sessionID string, client domain.ClientInfo) error
 }
 
 // authService adalah implementasi dari AuthService
 type authService struct {
-	userRepo repository.UserRepository
-	config   *config.Config
+	userRepo          repository.UserRepository
+	refreshTokenRepo  repository.RefreshTokenRepository
+	revokedTokenRepo  repository.RevokedTokenRepository
+	passwordResetRepo repository.PasswordResetRepository
+	recoveryCodeRepo  repository.RecoveryCodeRepository
+	loginAttemptRepo  repository.LoginAttemptRepository
+	sessionRepo       repository.SessionRepository
+	mailer            mailer.Mailer
+	keys              *utils.KeyRing
+	config            *config.Config
 }
 
 // NewAuthService membuat instance baru dari AuthService
-func NewAuthService(userRepo repository.UserRepository, config *config.Config) AuthService {
+func NewAuthService(
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	revokedTokenRepo repository.RevokedTokenRepository,
+	passwordResetRepo repository.PasswordResetRepository,
+	recoveryCodeRepo repository.RecoveryCodeRepository,
+	loginAttemptRepo repository.LoginAttemptRepository,
+	sessionRepo repository.SessionRepository,
+	mailer mailer.Mailer,
+	keys *utils.KeyRing,
+	config *config.Config,
+) AuthService {
 	return &authService{
-		userRepo: userRepo,
-		config:   config,
+		userRepo:          userRepo,
+		refreshTokenRepo:  refreshTokenRepo,
+		revokedTokenRepo:  revokedTokenRepo,
+		passwordResetRepo: passwordResetRepo,
+		recoveryCodeRepo:  recoveryCodeRepo,
+		loginAttemptRepo:  loginAttemptRepo,
+		sessionRepo:       sessionRepo,
+		mailer:            mailer,
+		keys:              keys,
+		config:            config,
 	}
 }
 
@@ -55,36 +112,752 @@ func (s *authService) Register(ctx context.Context, user *domain.User) (uint, er
 		return 0, err
 	}
 
+	// Kirim tautan verifikasi email. Kegagalan pengiriman tidak membatalkan pendaftaran
+	// karena pengguna masih bisa meminta tautan baru.
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		log.Printf("Gagal mengirim email verifikasi ke pengguna %d: %v", user.ID, err)
+	}
+
 	return user.ID, nil
 }
 
-// Login melakukan autentikasi pengguna
-func (s *authService) Login(ctx context.Context, email, password string) (string, *domain.UserResponse, error) {
+// Login melakukan autentikasi pengguna.
+// Jika 2FA aktif, hanya token sementara yang dikembalikan dan login harus
+// dilanjutkan dengan CompleteMFALogin.
+func (s *authService) Login(ctx context.Context, email, password string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	// Tolak lebih awal jika email atau IP sedang dikunci
+	if err := s.checkLoginAllowed(ctx, email, client); err != nil {
+		return nil, err
+	}
+
 	// Cari pengguna berdasarkan email
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
-		return "", nil, errors.New("email atau password salah")
+		// Email yang tidak terdaftar tetap dihitung agar tidak bisa dibedakan dari password salah
+		if err := s.recordLoginFailure(ctx, email, nil, client); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("email atau password salah")
 	}
 
 	// Verifikasi password
 	if !user.CheckPassword(password) {
-		return "", nil, errors.New("email atau password salah")
+		if err := s.recordLoginFailure(ctx, email, user, client); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("email atau password salah")
 	}
 
-	// Generate token JWT
-	token, err := utils.GenerateToken(user, s.config.JWT.Secret, s.config.JWT.ExpiryTime)
+	// Login berhasil, hitungan kegagalan untuk email ini dimulai ulang.
+	// Jika 2FA aktif, hitungan baru dimulai ulang setelah kode verifikasi benar.
+	if !user.IsMFAEnabled() {
+		if err := s.loginAttemptRepo.Reset(ctx, emailAttemptKey(email)); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.finishLogin(ctx, user, client)
+}
+
+// LoginExternal menyelesaikan login pengguna yang sudah diautentikasi oleh pihak lain,
+// misalnya penyedia identitas OpenID Connect. Verifikasi dua langkah tetap berlaku.
+func (s *authService) LoginExternal(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.LoginResult, error) {
+	return s.finishLogin(ctx, user, client)
+}
+
+// finishLogin menerbitkan token untuk pengguna yang identitasnya sudah terverifikasi,
+// atau token sementara jika langkah kedua (2FA) masih diperlukan
+func (s *authService) finishLogin(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.LoginResult, error) {
+	// Akun yang ditangguhkan atau dijadwalkan untuk dihapus tidak bisa login
+	if err := accountStatusError(user); err != nil {
+		return nil, err
+	}
+
+	// Langkah kedua diperlukan jika 2FA aktif
+	if user.IsMFAEnabled() {
+		mfaToken, err := utils.GenerateMFAPendingToken(user, s.keys, s.config.Auth.MFAPendingExpiry)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.LoginResult{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		}, nil
+	}
+
+	// Buat sesi baru beserta token akses dan refresh token-nya
+	tokens, err := s.startSession(ctx, user, client, false)
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
 	// Kembalikan token dan data pengguna (tanpa password)
 	userResponse := user.ToResponse()
-	return token, &userResponse, nil
+	return &domain.LoginResult{
+		Tokens:                tokens,
+		User:                  &userResponse,
+		MFAEnrollmentRequired: user.RequiresMFA(),
+	}, nil
+}
+
+// CompleteMFALogin menukar token sementara dari Login dengan token akses
+// setelah kode TOTP atau kode pemulihan terverifikasi
+func (s *authService) CompleteMFALogin(ctx context.Context, mfaToken, code string, client domain.ClientInfo) (*domain.AuthTokens, *domain.UserResponse, error) {
+	claims, err := utils.ValidateToken(mfaToken, s.keys)
+	if err != nil || claims.Purpose != utils.TokenPurposeMFAPending || claims.ID == "" {
+		return nil, nil, errors.New("sesi login tidak valid atau sudah kedaluwarsa, silakan login kembali")
+	}
+
+	// Token sementara hanya bisa ditukar sekali
+	revoked, err := s.revokedTokenRepo.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if revoked {
+		return nil, nil, errors.New("sesi login tidak valid atau sudah kedaluwarsa, silakan login kembali")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil || !user.IsMFAEnabled() {
+		return nil, nil, errors.New("sesi login tidak valid atau sudah kedaluwarsa, silakan login kembali")
+	}
+
+	// Kode verifikasi yang salah dihitung bersama password yang salah
+	if err := s.checkLoginAllowed(ctx, user.Email, client); err != nil {
+		return nil, nil, err
+	}
+
+	ok, err := s.verifySecondFactor(ctx, user, code)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		if err := s.recordLoginFailure(ctx, user.Email, user, client); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, errors.New("kode verifikasi salah")
+	}
+
+	if err := s.loginAttemptRepo.Reset(ctx, emailAttemptKey(user.Email)); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.revokedTokenRepo.Revoke(ctx, claims.ID, user.ID, claims.ExpiresAt.Time); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.startSession(ctx, user, client, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userResponse := user.ToResponse()
+	return tokens, &userResponse, nil
+}
+
+// RefreshToken menukar refresh token dengan token akses dan refresh token baru.
+// Refresh token hanya bisa dipakai sekali; jika token yang sudah dipakai dikirim lagi,
+// seluruh family token dicabut karena kemungkinan token tersebut telah dicuri.
+func (s *authService) RefreshToken(ctx context.Context, refreshToken string, client domain.ClientInfo) (*domain.AuthTokens, error) {
+	storedToken, err := s.refreshTokenRepo.FindByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		return nil, errors.New("refresh token tidak valid")
+	}
+
+	// Deteksi pemakaian ulang token yang sudah dirotasi atau dicabut
+	if storedToken.UsedAt != nil || storedToken.RevokedAt != nil {
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, storedToken.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("refresh token sudah tidak berlaku, silakan login kembali")
+	}
+
+	if !storedToken.IsActive(time.Now()) {
+		return nil, errors.New("refresh token kedaluwarsa, silakan login kembali")
+	}
+
+	// Tandai token sebagai sudah dipakai secara atomik
+	marked, err := s.refreshTokenRepo.MarkUsed(ctx, storedToken.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		// Token dipakai oleh request lain secara bersamaan
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, storedToken.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("refresh token sudah tidak berlaku, silakan login kembali")
+	}
+
+	// Pastikan pengguna masih ada dan tidak sedang ditangguhkan
+	user, err := s.userRepo.FindByID(ctx, storedToken.PenggunaID)
+	if err != nil {
+		return nil, errors.New("pengguna tidak ditemukan")
+	}
+	if err := accountStatusError(user); err != nil {
+		return nil, err
+	}
+
+	// Pastikan sesi belum diakhiri. Family yang dibuat sebelum ada tabel sesi
+	// mendapatkan sesi baru agar tetap muncul di daftar sesi.
+	session, err := s.sessionRepo.FindByID(ctx, storedToken.FamilyID)
+	if err != nil {
+		now := time.Now()
+		if err := s.sessionRepo.Create(ctx, &domain.Session{
+			ID:         storedToken.FamilyID,
+			PenggunaID: user.ID,
+			UserAgent:  client.UserAgent,
+			IPAddress:  client.IPAddress,
+			LastSeenAt: now,
+		}); err != nil {
+			return nil, err
+		}
+	} else if session.IsRevoked() {
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, storedToken.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("sesi sudah berakhir, silakan login kembali")
+	} else if err := s.TouchSession(ctx, session.ID, client); err != nil {
+		return nil, err
+	}
+
+	// Rotasi: buat refresh token baru dalam family yang sama
+	return s.issueTokens(ctx, user, storedToken.FamilyID, storedToken.MFA)
+}
+
+// Logout mengakhiri sesi yang sedang dipakai: token akses, sesi, dan refresh token
+// dari sesi tersebut dicabut. Refresh token yang dikirim ikut dicabut beserta family-nya.
+func (s *authService) Logout(ctx context.Context, userID uint, tokenID, sessionID string, tokenExpiresAt time.Time, refreshToken string) error {
+	// Cabut token akses sampai waktu kedaluwarsanya
+	if err := s.revokedTokenRepo.Revoke(ctx, tokenID, userID, tokenExpiresAt); err != nil {
+		return err
+	}
+
+	if sessionID != "" {
+		if err := s.sessionRepo.Revoke(ctx, sessionID); err != nil {
+			return err
+		}
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, sessionID); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	// Cabut refresh token hanya jika milik pengguna yang sama
+	storedToken, err := s.refreshTokenRepo.FindByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil || storedToken.PenggunaID != userID {
+		return nil
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(ctx, storedToken.FamilyID)
+}
+
+// RequestPasswordReset membuat token reset password dan mengirimkan tautannya ke email pengguna.
+// Jika email tidak terdaftar, tidak ada yang dikirim dan tidak ada error yang dikembalikan
+// agar endpoint ini tidak bisa dipakai untuk menebak email yang terdaftar.
+func (s *authService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+
+	// Token reset sebelumnya tidak berlaku lagi
+	if err := s.passwordResetRepo.InvalidateByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.passwordResetRepo.Create(ctx, &domain.PasswordReset{
+		PenggunaID: user.ID,
+		TokenHash:  utils.HashToken(token),
+		ExpiresAt:  time.Now().Add(s.config.Auth.PasswordResetExpiry),
+	}); err != nil {
+		return err
+	}
+
+	resetURL := fmt.Sprintf("%s/reset-password?token=%s", s.config.Server.FrontendURL, url.QueryEscape(token))
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset password akun Jubel",
+		Body: fmt.Sprintf(
+			"Halo %s,\n\n"+
+				"Kami menerima permintaan untuk mereset password akun Jubel Anda.\n"+
+				"Buka tautan berikut untuk membuat password baru:\n\n%s\n\n"+
+				"Tautan ini hanya bisa dipakai sekali dan berlaku selama %s.\n"+
+				"Jika Anda tidak meminta reset password, abaikan email ini.\n",
+			user.Nama, resetURL, s.config.Auth.PasswordResetExpiry,
+		),
+	})
+}
+
+// ResetPassword mengganti password pengguna menggunakan token reset password
+func (s *authService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	reset, err := s.passwordResetRepo.FindByHash(ctx, utils.HashToken(token))
+	if err != nil || !reset.IsActive(time.Now()) {
+		return errors.New("token reset password tidak valid atau sudah kedaluwarsa")
+	}
+
+	// Tandai token sebagai sudah dipakai secara atomik
+	marked, err := s.passwordResetRepo.MarkUsed(ctx, reset.ID)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return errors.New("token reset password tidak valid atau sudah kedaluwarsa")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, reset.PenggunaID)
+	if err != nil {
+		return errors.New("pengguna tidak ditemukan")
+	}
+
+	// Simpan password baru dan akhiri semua sesi yang sudah ada
+	user.Password = newPassword
+	if err := user.HashPassword(); err != nil {
+		return err
+	}
+	user.InvalidateTokens(time.Now())
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if _, err := s.sessionRepo.RevokeByUserID(ctx, user.ID, ""); err != nil {
+		return err
+	}
+
+	return s.refreshTokenRepo.RevokeByUserID(ctx, user.ID)
+}
+
+// VerifyEmail menandai email pengguna sebagai terverifikasi menggunakan token dari tautan verifikasi
+func (s *authService) VerifyEmail(ctx context.Context, token string) error {
+	invalid := errors.New("tautan verifikasi tidak valid atau sudah kedaluwarsa")
+
+	// Format token: <user_id>.<expires_unix>.<signature>
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return invalid
+	}
+
+	userID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return invalid
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return invalid
+	}
+
+	user, err := s.userRepo.FindByID(ctx, uint(userID))
+	if err != nil {
+		return invalid
+	}
+
+	// Email ikut ditandatangani sehingga tautan tidak berlaku jika email berubah
+	if !utils.VerifySignedValues(s.config.JWT.Secret, parts[2], "verify-email", parts[0], parts[1], user.Email) {
+		return invalid
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	return s.userRepo.Update(ctx, user)
+}
+
+// ResendVerificationEmail mengirim ulang tautan verifikasi email
+func (s *authService) ResendVerificationEmail(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("pengguna tidak ditemukan")
+	}
+
+	if user.IsEmailVerified() {
+		return errors.New("email sudah diverifikasi")
+	}
+
+	return s.sendVerificationEmail(ctx, user)
+}
+
+// IsEmailVerified memeriksa apakah email pengguna sudah diverifikasi
+func (s *authService) IsEmailVerified(ctx context.Context, userID uint) (bool, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return false, errors.New("pengguna tidak ditemukan")
+	}
+
+	return user.IsEmailVerified(), nil
+}
+
+// sendVerificationEmail mengirim tautan verifikasi email yang ditandatangani
+func (s *authService) sendVerificationEmail(ctx context.Context, user *domain.User) error {
+	userID := strconv.FormatUint(uint64(user.ID), 10)
+	expiresAt := strconv.FormatInt(time.Now().Add(s.config.Auth.EmailVerificationExpiry).Unix(), 10)
+	signature := utils.SignValues(s.config.JWT.Secret, "verify-email", userID, expiresAt, user.Email)
+	token := userID + "." + expiresAt + "." + signature
+
+	verifyURL := fmt.Sprintf("%s/verify-email?token=%s", s.config.Server.FrontendURL, url.QueryEscape(token))
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verifikasi email akun Jubel",
+		Body: fmt.Sprintf(
+			"Halo %s,\n\n"+
+				"Terima kasih telah mendaftar di Jubel.\n"+
+				"Buka tautan berikut untuk memverifikasi email Anda:\n\n%s\n\n"+
+				"Tautan ini berlaku selama %s. Sebelum email diverifikasi, Anda belum bisa\n"+
+				"menjual barang, mengirim pesan, atau membuat transaksi.\n",
+			user.Nama, verifyURL, s.config.Auth.EmailVerificationExpiry,
+		),
+	})
+}
+
+// checkLoginAllowed menolak percobaan login jika email atau IP sedang dikunci
+func (s *authService) checkLoginAllowed(ctx context.Context, email string, client domain.ClientInfo) error {
+	now := time.Now()
+	for _, key := range loginAttemptKeys(email, client) {
+		attempt, err := s.loginAttemptRepo.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if retryAfter := attempt.RetryAfter(now); retryAfter > 0 {
+			return errors.TooManyRequestsError("Terlalu banyak percobaan login gagal, silakan coba lagi nanti", retryAfter, nil).
+				WithMetadata("attemptKey", key)
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure mencatat percobaan login gagal per email dan per IP.
+// Email mendapat jeda yang bertambah dua kali lipat setiap kegagalan dan dikunci setelah
+// LoginMaxAttempts kegagalan. IP hanya dikunci setelah LoginMaxAttemptsPerIP kegagalan
+// karena satu IP kampus bisa dipakai banyak mahasiswa.
+func (s *authService) recordLoginFailure(ctx context.Context, email string, user *domain.User, client domain.ClientInfo) error {
+	cfg := s.config.Auth
+	now := time.Now()
+
+	attempt, err := s.loginAttemptRepo.RecordFailure(ctx, emailAttemptKey(email), now, cfg.LoginAttemptWindow)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case attempt.Failures >= cfg.LoginMaxAttempts:
+		if err := s.loginAttemptRepo.Lock(ctx, attempt.Key, now.Add(cfg.LoginLockoutDuration)); err != nil {
+			return err
+		}
+		// Beri tahu pemilik akun sekali saat akun pertama kali dikunci
+		if attempt.Failures == cfg.LoginMaxAttempts && user != nil {
+			if err := s.sendLockoutNotification(ctx, user, client); err != nil {
+				log.Printf("Gagal mengirim notifikasi penguncian ke pengguna %d: %v", user.ID, err)
+			}
+		}
+	case attempt.Failures >= 2:
+		// Jeda digandakan satu per satu dan berhenti di LoginLockoutDuration.
+		// Shift langsung bisa overflow menjadi jeda pendek jika LoginMaxAttempts besar.
+		backoff := cfg.LoginBackoffBase
+		for i := 2; i < attempt.Failures && backoff > 0 && backoff < cfg.LoginLockoutDuration; i++ {
+			backoff *= 2
+		}
+		if backoff <= 0 || backoff > cfg.LoginLockoutDuration {
+			backoff = cfg.LoginLockoutDuration
+		}
+		if err := s.loginAttemptRepo.Lock(ctx, attempt.Key, now.Add(backoff)); err != nil {
+			return err
+		}
+	}
+
+	if client.IPAddress == "" {
+		return nil
+	}
+
+	ipAttempt, err := s.loginAttemptRepo.RecordFailure(ctx, ipAttemptKey(client.IPAddress), now, cfg.LoginAttemptWindow)
+	if err != nil {
+		return err
+	}
+	if ipAttempt.Failures >= cfg.LoginMaxAttemptsPerIP {
+		return s.loginAttemptRepo.Lock(ctx, ipAttempt.Key, now.Add(cfg.LoginLockoutDuration))
+	}
+
+	return nil
+}
+
+// sendLockoutNotification memberi tahu pemilik akun bahwa akunnya dikunci sementara
+func (s *authService) sendLockoutNotification(ctx context.Context, user *domain.User, client domain.ClientInfo) error {
+	resetURL := fmt.Sprintf("%s/forgot-password", s.config.Server.FrontendURL)
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Akun Jubel Anda dikunci sementara",
+		Body: fmt.Sprintf(
+			"Halo %s,\n\n"+
+				"Kami mendeteksi %d percobaan login yang gagal ke akun Jubel Anda.\n"+
+				"Untuk melindungi akun Anda, login dikunci sementara selama %s.\n\n"+
+				"Percobaan terakhir berasal dari:\n"+
+				"  Alamat IP : %s\n"+
+				"  Perangkat : %s\n\n"+
+				"Jika ini bukan Anda, segera ganti password melalui tautan berikut:\n\n%s\n",
+			user.Nama, s.config.Auth.LoginMaxAttempts, s.config.Auth.LoginLockoutDuration,
+			client.IPAddress, client.UserAgent, resetURL,
+		),
+	})
+}
+
+// loginAttemptKeys mengembalikan kunci percobaan login untuk email dan IP
+func loginAttemptKeys(email string, client domain.ClientInfo) []string {
+	keys := []string{emailAttemptKey(email)}
+	if client.IPAddress != "" {
+		keys = append(keys, ipAttemptKey(client.IPAddress))
+	}
+	return keys
+}
+
+// emailAttemptKey membuat kunci percobaan login untuk email
+func emailAttemptKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// ipAttemptKey membuat kunci percobaan login untuk alamat IP
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// EnrollMFA memulai pendaftaran 2FA dengan membuat secret TOTP baru.
+// 2FA baru aktif setelah dikonfirmasi dengan ConfirmMFA.
+func (s *authService) EnrollMFA(ctx context.Context, userID uint) (*domain.MFAEnrollment, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("pengguna tidak ditemukan")
+	}
+
+	if user.IsMFAEnabled() {
+		return nil, errors.New("verifikasi dua langkah sudah aktif")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = secret
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &domain.MFAEnrollment{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(mfaIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA mengaktifkan 2FA setelah kode TOTP pertama benar dan mengembalikan kode pemulihan
+func (s *authService) ConfirmMFA(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("pengguna tidak ditemukan")
+	}
+
+	if user.IsMFAEnabled() {
+		return nil, errors.New("verifikasi dua langkah sudah aktif")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("pendaftaran verifikasi dua langkah belum dimulai")
+	}
+
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, errors.New("kode verifikasi salah")
+	}
+
+	now := time.Now()
+	user.TOTPEnabledAt = &now
+	user.TOTPLastStep = step
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(ctx, user.ID)
+}
+
+// DisableMFA menonaktifkan 2FA. Pengguna yang wajib 2FA (admin) tidak bisa menonaktifkannya.
+func (s *authService) DisableMFA(ctx context.Context, userID uint, password, code string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("pengguna tidak ditemukan")
+	}
+
+	if !user.IsMFAEnabled() {
+		return errors.New("verifikasi dua langkah belum aktif")
+	}
+	if user.RequiresMFA() {
+		return errors.New("admin wajib menggunakan verifikasi dua langkah")
+	}
+
+	if !user.CheckPassword(password) {
+		return errors.New("password salah")
+	}
+
+	ok, err := s.verifySecondFactor(ctx, user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("kode verifikasi salah")
+	}
+
+	user.TOTPSecret = ""
+	user.TOTPEnabledAt = nil
+	user.TOTPLastStep = 0
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return s.recoveryCodeRepo.DeleteByUserID(ctx, user.ID)
+}
+
+// RegenerateRecoveryCodes mengganti semua kode pemulihan dengan kode baru
+func (s *authService) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("pengguna tidak ditemukan")
+	}
+
+	if !user.IsMFAEnabled() {
+		return nil, errors.New("verifikasi dua langkah belum aktif")
+	}
+
+	// Hanya kode TOTP yang diterima agar kode pemulihan tidak bisa dipakai untuk membuat kode baru
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, errors.New("kode verifikasi salah")
+	}
+	fresh, err := s.userRepo.UseTOTPStep(ctx, user.ID, step)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, errors.New("kode verifikasi sudah dipakai")
+	}
+
+	return s.replaceRecoveryCodes(ctx, user.ID)
+}
+
+// verifySecondFactor memeriksa kode TOTP atau, jika bukan kode TOTP, kode pemulihan
+func (s *authService) verifySecondFactor(ctx context.Context, user *domain.User, code string) (bool, error) {
+	if step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now()); ok {
+		// Kode TOTP yang sama tidak boleh dipakai dua kali
+		return s.userRepo.UseTOTPStep(ctx, user.ID, step)
+	}
+
+	return s.recoveryCodeRepo.Consume(ctx, user.ID, utils.HashToken(utils.NormalizeRecoveryCode(code)))
+}
+
+// replaceRecoveryCodes membuat kode pemulihan baru dan menyimpan hash-nya
+func (s *authService) replaceRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, utils.HashToken(utils.NormalizeRecoveryCode(code)))
+	}
+
+	if err := s.recoveryCodeRepo.ReplaceForUser(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// accountStatusError mengembalikan error jika akun tidak boleh dipakai:
+// sedang ditangguhkan, dijadwalkan untuk dihapus, atau sudah dianonimkan
+func accountStatusError(user *domain.User) error {
+	if user.IsDeleted() {
+		return errors.ForbiddenError("akun ini sudah dihapus. Jika masih dalam masa tenggang, gunakan tautan pemulihan yang dikirim ke email anda", nil)
+	}
+	if user.IsSuspended(time.Now()) {
+		return suspensionError(user)
+	}
+	return nil
+}
+
+// suspensionError membuat error untuk pengguna yang sedang ditangguhkan beserta alasan dan masa berlakunya
+func suspensionError(user *domain.User) error {
+	message := "akun anda ditangguhkan"
+	if user.SuspendedUntil != nil {
+		message += " hingga " + user.SuspendedUntil.Format(time.RFC3339)
+	}
+	if user.SuspendReason != "" {
+		message += ": " + user.SuspendReason
+	}
+	return errors.ForbiddenError(message, nil)
+}
+
+// startSession mencatat sesi login baru lalu menerbitkan token untuk sesi tersebut
+func (s *authService) startSession(ctx context.Context, user *domain.User, client domain.ClientInfo, mfa bool) (*domain.AuthTokens, error) {
+	if err := accountStatusError(user); err != nil {
+		return nil, err
+	}
+
+	session := &domain.Session{
+		ID:         uuid.New().String(),
+		PenggunaID: user.ID,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IPAddress,
+		LastSeenAt: time.Now(),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	// ID sesi dipakai sebagai family refresh token
+	return s.issueTokens(ctx, user, session.ID, mfa)
+}
+
+// issueTokens membuat token akses JWT dan refresh token baru untuk pengguna.
+// familyID sama dengan ID sesi, mfa menandai sesi yang diautentikasi dengan verifikasi dua langkah.
+func (s *authService) issueTokens(ctx context.Context, user *domain.User, familyID string, mfa bool) (*domain.AuthTokens, error) {
+	// Generate token JWT
+	accessToken, err := utils.GenerateToken(user, s.keys, s.config.JWT.ExpiryTime, familyID, mfa)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate refresh token dan simpan hash-nya
+	refreshToken, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshTokenRepo.Create(ctx, &domain.RefreshToken{
+		PenggunaID: user.ID,
+		TokenHash:  utils.HashToken(refreshToken),
+		FamilyID:   familyID,
+		MFA:        mfa,
+		ExpiresAt:  time.Now().Add(s.config.JWT.RefreshExpiryTime),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.config.JWT.ExpiryTime.Seconds()),
+	}, nil
 }
 
 // ValidateToken memvalidasi token JWT
 func (s *authService) ValidateToken(ctx context.Context, token string) (*utils.JWTClaims, error) {
-	claims, err := utils.ValidateToken(token, s.config.JWT.Secret)
+	claims, err := utils.ValidateToken(token, s.keys)
 	if err != nil {
 		return nil, err
 	}
@@ -94,11 +867,53 @@ func (s *authService) ValidateToken(ctx context.Context, token string) (*utils.J
 		return nil, errors.New("token kedaluwarsa")
 	}
 
+	// Token tanpa jti tidak dapat dicabut sehingga tidak diterima,
+	// begitu juga token sementara seperti token langkah kedua login
+	if claims.ID == "" || claims.Purpose != "" {
+		return nil, errors.New("token tidak valid")
+	}
+
+	// Cek apakah token sudah dicabut (logout)
+	revoked, err := s.revokedTokenRepo.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errors.New("token sudah tidak berlaku")
+	}
+
 	// Cek apakah pengguna masih ada
-	_, err = s.userRepo.FindByID(ctx, claims.UserID)
+	user, err := s.userRepo.FindByID(ctx, claims.UserID)
 	if err != nil {
 		return nil, errors.New("pengguna tidak ditemukan")
 	}
 
+	// Pengguna yang sedang ditangguhkan atau dihapus ditolak meskipun tokennya masih berlaku
+	if err := accountStatusError(user); err != nil {
+		return nil, err
+	}
+
+	// Token yang diterbitkan sebelum ganti password dianggap tidak berlaku
+	if user.TokenValidAfter != nil && claims.IssuedAt != nil && claims.IssuedAt.Time.Before(*user.TokenValidAfter) {
+		return nil, errors.New("token sudah tidak berlaku")
+	}
+
+	// Token dari sesi yang sudah diakhiri tidak berlaku lagi
+	if claims.SessionID != "" {
+		session, err := s.sessionRepo.FindByID(ctx, claims.SessionID)
+		if err != nil || session.IsRevoked() {
+			return nil, errors.New("sesi sudah berakhir, silakan login kembali")
+		}
+	}
+
 	return claims, nil
+}
+
+// TouchSession memperbarui waktu terakhir aktif sesi, paling sering sekali per sessionTouchInterval
+func (s *authService) TouchSession(ctx context.Context, sessionID string, client domain.ClientInfo) error {
+	if sessionID == "" {
+		return nil
+	}
+	now := time.Now()
+	return s.sessionRepo.Touch(ctx, sessionID, client, now, now.Add(-sessionTouchInterval))
 }
\ No newline at end of file
@@ -0,0 +1,551 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/mailer"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+	"gorm.io/gorm"
+)
+
+// fakeRefreshTokenRepository menyimpan refresh token di memori. Method yang tidak
+// dipakai diteruskan ke interface nil sehingga panic jika terpanggil.
+type fakeRefreshTokenRepository struct {
+	repository.RefreshTokenRepository
+	tokens []domain.RefreshToken
+	// markUsedFails mensimulasikan token yang lebih dulu dipakai oleh request lain
+	markUsedFails bool
+}
+
+func (r *fakeRefreshTokenRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
+	token.ID = uint(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, *token)
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) FindByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			return &token, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRefreshTokenRepository) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	for i := range r.tokens {
+		token := &r.tokens[i]
+		if token.ID == id {
+			if r.markUsedFails || token.UsedAt != nil || token.RevokedAt != nil {
+				return false, nil
+			}
+			now := time.Now()
+			token.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	now := time.Now()
+	for i := range r.tokens {
+		if r.tokens[i].FamilyID == familyID && r.tokens[i].RevokedAt == nil {
+			r.tokens[i].RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// find mengembalikan token tersimpan untuk refresh token mentah
+func (r *fakeRefreshTokenRepository) find(t *testing.T, refreshToken string) *domain.RefreshToken {
+	t.Helper()
+	token, err := r.FindByHash(context.Background(), utils.HashToken(refreshToken))
+	if err != nil {
+		t.Fatalf("refresh token tidak tersimpan: %v", err)
+	}
+	return token
+}
+
+// familyRevoked memeriksa apakah semua token dalam family sudah dicabut
+func (r *fakeRefreshTokenRepository) familyRevoked(familyID string) bool {
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// fakeSessionRepository menyimpan sesi di memori
+type fakeSessionRepository struct {
+	repository.SessionRepository
+	sessions map[string]*domain.Session
+}
+
+func (r *fakeSessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *fakeSessionRepository) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *fakeSessionRepository) Touch(ctx context.Context, id string, client domain.ClientInfo, now, staleBefore time.Time) error {
+	return nil
+}
+
+// fakeRecoveryCodeRepository menyimpan kode pemulihan di memori
+type fakeRecoveryCodeRepository struct {
+	repository.RecoveryCodeRepository
+	codes []domain.RecoveryCode
+}
+
+func (r *fakeRecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID uint, codeHashes []string) error {
+	codes := r.codes[:0]
+	for _, code := range r.codes {
+		if code.PenggunaID != userID {
+			codes = append(codes, code)
+		}
+	}
+	for _, hash := range codeHashes {
+		codes = append(codes, domain.RecoveryCode{ID: uint(len(codes) + 1), PenggunaID: userID, CodeHash: hash})
+	}
+	r.codes = codes
+	return nil
+}
+
+func (r *fakeRecoveryCodeRepository) Consume(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	for i := range r.codes {
+		code := &r.codes[i]
+		if code.PenggunaID == userID && code.CodeHash == codeHash && code.UsedAt == nil {
+			now := time.Now()
+			code.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeRevokedTokenRepository menyimpan jti token yang dicabut di memori
+type fakeRevokedTokenRepository struct {
+	revoked map[string]bool
+}
+
+func (r *fakeRevokedTokenRepository) Revoke(ctx context.Context, jti string, userID uint, expiresAt time.Time) error {
+	r.revoked[jti] = true
+	return nil
+}
+
+func (r *fakeRevokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	return r.revoked[jti], nil
+}
+
+// UseTOTPStep meniru update bersyarat implementasi PostgreSQL: langkah waktu
+// hanya dicatat jika lebih baru dari langkah terakhir yang dipakai
+func (r *fakeUserRepository) UseTOTPStep(ctx context.Context, id uint, step int64) (bool, error) {
+	for i := range r.users {
+		if r.users[i].ID == id && r.users[i].TOTPLastStep < step {
+			r.users[i].TOTPLastStep = step
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeMailer mencatat email yang dikirim
+type fakeMailer struct {
+	sent []mailer.Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// authTestEnv berisi authService dengan repository palsu
+type authTestEnv struct {
+	service       *authService
+	users         *fakeUserRepository
+	refreshTokens *fakeRefreshTokenRepository
+	loginAttempts repository.LoginAttemptRepository
+	mailer        *fakeMailer
+	user          *domain.User
+}
+
+// newAuthTestEnv membuat authService dengan satu pengguna
+func newAuthTestEnv(t *testing.T) *authTestEnv {
+	t.Helper()
+	users := &fakeUserRepository{}
+	user := &domain.User{Nama: "Budi", Email: "budi@example.com", Role: domain.RoleUser}
+	if err := users.Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	refreshTokens := &fakeRefreshTokenRepository{}
+	revokedTokens := &fakeRevokedTokenRepository{revoked: map[string]bool{}}
+	sessions := &fakeSessionRepository{sessions: map[string]*domain.Session{}}
+	cfg := &config.Config{
+		JWT: config.JWTConfig{ExpiryTime: 15 * time.Minute, RefreshExpiryTime: 24 * time.Hour},
+		Auth: config.AuthConfig{
+			MFAPendingExpiry:      5 * time.Minute,
+			LoginMaxAttempts:      5,
+			LoginMaxAttemptsPerIP: 50,
+			LoginAttemptWindow:    15 * time.Minute,
+			LoginLockoutDuration:  15 * time.Minute,
+			LoginBackoffBase:      time.Second,
+		},
+	}
+
+	loginAttempts := repository.NewMemoryLoginAttemptRepository(time.Hour)
+	mail := &fakeMailer{}
+
+	service := NewAuthService(
+		users, refreshTokens, revokedTokens, nil, &fakeRecoveryCodeRepository{},
+		loginAttempts, sessions, mail,
+		utils.NewHMACKeyRing("rahasia"), cfg,
+	).(*authService)
+	return &authTestEnv{
+		service:       service,
+		users:         users,
+		refreshTokens: refreshTokens,
+		loginAttempts: loginAttempts,
+		mailer:        mail,
+		user:          user,
+	}
+}
+
+// login membuat refresh token pertama untuk family baru
+func (e *authTestEnv) login(t *testing.T, familyID string) string {
+	t.Helper()
+	tokens, err := e.service.issueTokens(context.Background(), e.user, familyID, false)
+	if err != nil {
+		t.Fatalf("issueTokens: %v", err)
+	}
+	return tokens.RefreshToken
+}
+
+// rotate menukar refresh token dan menggagalkan test jika rotasi gagal
+func (e *authTestEnv) rotate(t *testing.T, refreshToken string) string {
+	t.Helper()
+	tokens, err := e.service.RefreshToken(context.Background(), refreshToken, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	return tokens.RefreshToken
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	const familyID = "family-1"
+
+	env := newAuthTestEnv(t)
+	oldToken := env.login(t, familyID)
+
+	tokens, err := env.service.RefreshToken(context.Background(), oldToken, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if tokens.AccessToken == "" {
+		t.Error("token akses kosong")
+	}
+	if tokens.RefreshToken == "" || tokens.RefreshToken == oldToken {
+		t.Errorf("refresh token tidak dirotasi: %q", tokens.RefreshToken)
+	}
+
+	claims, err := utils.ValidateToken(tokens.AccessToken, env.service.keys)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if claims.UserID != env.user.ID || claims.SessionID != familyID {
+		t.Errorf("claims = user %d sesi %q, want user %d sesi %q", claims.UserID, claims.SessionID, env.user.ID, familyID)
+	}
+
+	if stored := env.refreshTokens.find(t, oldToken); stored.UsedAt == nil {
+		t.Error("refresh token lama tidak ditandai sudah dipakai")
+	}
+	stored := env.refreshTokens.find(t, tokens.RefreshToken)
+	if stored.FamilyID != familyID {
+		t.Errorf("family refresh token baru = %q, want %q", stored.FamilyID, familyID)
+	}
+	if !stored.IsActive(time.Now()) || stored.UsedAt != nil {
+		t.Errorf("refresh token baru tidak aktif: %+v", stored)
+	}
+}
+
+func TestRefreshTokenRejectsInvalidTokens(t *testing.T) {
+	const familyID = "family-1"
+
+	tests := []struct {
+		name string
+		// prepare menyiapkan refresh token yang akan dikirim. latest adalah
+		// refresh token terbaru dalam family yang seharusnya masih berlaku
+		// kecuali family dicabut.
+		prepare           func(t *testing.T, env *authTestEnv) (token, latest string)
+		wantErr           string
+		wantFamilyRevoked bool
+	}{
+		{
+			name: "pemakaian ulang token yang sudah dirotasi",
+			prepare: func(t *testing.T, env *authTestEnv) (string, string) {
+				first := env.login(t, familyID)
+				return first, env.rotate(t, first)
+			},
+			wantErr:           "refresh token sudah tidak berlaku, silakan login kembali",
+			wantFamilyRevoked: true,
+		},
+		{
+			name: "pemakaian ulang token lama setelah beberapa rotasi",
+			prepare: func(t *testing.T, env *authTestEnv) (string, string) {
+				first := env.login(t, familyID)
+				second := env.rotate(t, first)
+				return second, env.rotate(t, second)
+			},
+			wantErr:           "refresh token sudah tidak berlaku, silakan login kembali",
+			wantFamilyRevoked: true,
+		},
+		{
+			name: "token dipakai bersamaan oleh request lain",
+			prepare: func(t *testing.T, env *authTestEnv) (string, string) {
+				env.refreshTokens.markUsedFails = true
+				token := env.login(t, familyID)
+				return token, token
+			},
+			wantErr:           "refresh token sudah tidak berlaku, silakan login kembali",
+			wantFamilyRevoked: true,
+		},
+		{
+			name: "token kedaluwarsa",
+			prepare: func(t *testing.T, env *authTestEnv) (string, string) {
+				token := env.login(t, familyID)
+				env.refreshTokens.tokens[len(env.refreshTokens.tokens)-1].ExpiresAt = time.Now().Add(-time.Minute)
+				return token, ""
+			},
+			wantErr: "refresh token kedaluwarsa, silakan login kembali",
+		},
+		{
+			name: "token tidak dikenal",
+			prepare: func(t *testing.T, env *authTestEnv) (string, string) {
+				return "token-tidak-dikenal", env.login(t, familyID)
+			},
+			wantErr: "refresh token tidak valid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newAuthTestEnv(t)
+			token, latest := tt.prepare(t, env)
+
+			_, err := env.service.RefreshToken(context.Background(), token, domain.ClientInfo{})
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("RefreshToken error = %v, want %q", err, tt.wantErr)
+			}
+			if got := env.refreshTokens.familyRevoked(familyID); got != tt.wantFamilyRevoked {
+				t.Errorf("family dicabut = %v, want %v", got, tt.wantFamilyRevoked)
+			}
+			if !tt.wantFamilyRevoked && latest != "" {
+				// Token lain dalam family tetap bisa dipakai
+				env.rotate(t, latest)
+			}
+			if tt.wantFamilyRevoked {
+				// Token terbaru yang mungkin dipegang pencuri ikut tidak berlaku
+				if _, err := env.service.RefreshToken(context.Background(), latest, domain.ClientInfo{}); err == nil {
+					t.Error("refresh token terbaru masih bisa dipakai setelah family dicabut")
+				}
+			}
+		})
+	}
+}
+
+// totpCodeAt menghitung kode TOTP (RFC 6238, SHA1, 6 digit, 30 detik) untuk satu langkah waktu
+func totpCodeAt(t *testing.T, secret string, step int64) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret TOTP tidak valid: %v", err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1000000)
+}
+
+func TestCompleteMFALoginRejectsReusedCodes(t *testing.T) {
+	// mfaTestCodes berisi kode yang bisa dipakai oleh satu kasus uji
+	type mfaTestCodes struct {
+		totp     func(offset int64) string
+		recovery []string
+	}
+
+	tests := []struct {
+		name  string
+		codes func(c mfaTestCodes) []string
+		// wantOK adalah hasil yang diharapkan untuk setiap kode secara berurutan
+		wantOK []bool
+	}{
+		{
+			name:   "kode TOTP dipakai ulang pada langkah waktu yang sama",
+			codes:  func(c mfaTestCodes) []string { return []string{c.totp(0), c.totp(0)} },
+			wantOK: []bool{true, false},
+		},
+		{
+			name:   "kode TOTP langkah sebelumnya setelah langkah yang lebih baru dipakai",
+			codes:  func(c mfaTestCodes) []string { return []string{c.totp(0), c.totp(-1)} },
+			wantOK: []bool{true, false},
+		},
+		{
+			name:   "kode TOTP langkah berikutnya",
+			codes:  func(c mfaTestCodes) []string { return []string{c.totp(0), c.totp(1)} },
+			wantOK: []bool{true, true},
+		},
+		{
+			name:   "kode pemulihan dipakai ulang",
+			codes:  func(c mfaTestCodes) []string { return []string{c.recovery[0], c.recovery[0]} },
+			wantOK: []bool{true, false},
+		},
+		{
+			name: "kode pemulihan dipakai ulang dengan format berbeda",
+			codes: func(c mfaTestCodes) []string {
+				return []string{c.recovery[0], strings.ToUpper(strings.ReplaceAll(c.recovery[0], "-", ""))}
+			},
+			wantOK: []bool{true, false},
+		},
+		{
+			name:   "kode pemulihan lain masih berlaku",
+			codes:  func(c mfaTestCodes) []string { return []string{c.recovery[0], c.recovery[1]} },
+			wantOK: []bool{true, true},
+		},
+		{
+			name:   "kode salah",
+			codes:  func(c mfaTestCodes) []string { return []string{"abcde-fghij"} },
+			wantOK: []bool{false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			env := newAuthTestEnv(t)
+
+			secret, err := utils.GenerateTOTPSecret()
+			if err != nil {
+				t.Fatal(err)
+			}
+			enabledAt := time.Now()
+			stored := &env.users.users[0]
+			stored.TOTPSecret = secret
+			stored.TOTPEnabledAt = &enabledAt
+			recovery, err := env.service.replaceRecoveryCodes(ctx, stored.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			step := time.Now().Unix() / 30
+			codes := tt.codes(mfaTestCodes{
+				totp:     func(offset int64) string { return totpCodeAt(t, secret, step+offset) },
+				recovery: recovery,
+			})
+			for i, code := range codes {
+				// Setiap percobaan login memakai token sementara baru karena token hanya bisa ditukar sekali
+				mfaToken, err := utils.GenerateMFAPendingToken(stored, env.service.keys, time.Minute)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, _, err = env.service.CompleteMFALogin(ctx, mfaToken, code, domain.ClientInfo{})
+				if (err == nil) != tt.wantOK[i] {
+					t.Fatalf("kode ke-%d: CompleteMFALogin error = %v, want ok %v", i+1, err, tt.wantOK[i])
+				}
+				if err != nil && err.Error() != "kode verifikasi salah" {
+					t.Errorf("kode ke-%d: CompleteMFALogin error = %q, want kode verifikasi salah", i+1, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordLoginFailureBackoff(t *testing.T) {
+	const lockout = 15 * time.Minute
+
+	tests := []struct {
+		name        string
+		base        time.Duration
+		maxAttempts int
+		failures    int
+		// wantLock adalah lama penguncian setelah kegagalan terakhir, 0 jika tidak dikunci
+		wantLock     time.Duration
+		wantNotified int
+	}{
+		{"kegagalan pertama tanpa jeda", time.Second, 5, 1, 0, 0},
+		{"kegagalan kedua", time.Second, 5, 2, time.Second, 0},
+		{"jeda berlipat dua", time.Second, 5, 3, 2 * time.Second, 0},
+		{"jeda berlipat dua lagi", time.Second, 5, 4, 4 * time.Second, 0},
+		{"dikunci saat batas tercapai", time.Second, 5, 5, lockout, 1},
+		{"notifikasi hanya dikirim sekali", time.Second, 5, 7, lockout, 1},
+		{"jeda dibatasi lama penguncian", time.Minute, 10, 6, lockout, 0},
+		{"shift melebihi lebar bit", time.Second, 100, 70, lockout, 0},
+		{"shift overflow menghasilkan nilai kecil", 1<<28 + 1, 100, 38, lockout, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			env := newAuthTestEnv(t)
+			env.service.config.Auth.LoginBackoffBase = tt.base
+			env.service.config.Auth.LoginMaxAttempts = tt.maxAttempts
+			env.service.config.Auth.LoginLockoutDuration = lockout
+
+			var before time.Time
+			for i := 0; i < tt.failures; i++ {
+				before = time.Now()
+				if err := env.service.recordLoginFailure(ctx, env.user.Email, env.user, domain.ClientInfo{}); err != nil {
+					t.Fatalf("recordLoginFailure: %v", err)
+				}
+			}
+			after := time.Now()
+
+			attempt, err := env.loginAttempts.Get(ctx, emailAttemptKey(env.user.Email))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if attempt.Failures != tt.failures {
+				t.Errorf("Failures = %d, want %d", attempt.Failures, tt.failures)
+			}
+			switch {
+			case tt.wantLock == 0 && attempt.LockedUntil != nil:
+				t.Errorf("dikunci sampai %v, want tidak dikunci", attempt.LockedUntil)
+			case tt.wantLock > 0 && attempt.LockedUntil == nil:
+				t.Errorf("tidak dikunci, want dikunci %v", tt.wantLock)
+			case tt.wantLock > 0:
+				if attempt.LockedUntil.Before(before.Add(tt.wantLock)) || attempt.LockedUntil.After(after.Add(tt.wantLock)) {
+					t.Errorf("dikunci %v, want %v", attempt.LockedUntil.Sub(before), tt.wantLock)
+				}
+			}
+
+			if len(env.mailer.sent) != tt.wantNotified {
+				t.Errorf("notifikasi terkirim = %d, want %d", len(env.mailer.sent), tt.wantNotified)
+			}
+			for _, msg := range env.mailer.sent {
+				if msg.To != env.user.Email {
+					t.Errorf("notifikasi dikirim ke %q, want %q", msg.To, env.user.Email)
+				}
+			}
+		})
+	}
+}
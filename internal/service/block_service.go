@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+)
+
+// BlockService adalah interface untuk layanan pemblokiran pengguna
+type BlockService interface {
+	// Block memblokir pengguna lain dan menghapus hubungan mengikuti di antara keduanya.
+	// Memblokir pengguna yang sudah diblokir tidak dianggap error.
+	Block(ctx context.Context, blockerID, blockedID uint) (*domain.BlockResponse, error)
+	// Unblock membuka blokir pengguna
+	Unblock(ctx context.Context, blockerID, blockedID uint) error
+	// GetBlocked mendapatkan daftar pengguna yang diblokir dengan paginasi
+	GetBlocked(ctx context.Context, blockerID uint, page, limit int) ([]domain.BlockResponse, int, int64, error)
+}
+
+// blockService adalah implementasi dari BlockService
+type blockService struct {
+	blockRepo  repository.BlockRepository
+	userRepo   repository.UserRepository
+	followRepo repository.FollowRepository
+}
+
+// NewBlockService membuat instance baru dari BlockService
+func NewBlockService(blockRepo repository.BlockRepository, userRepo repository.UserRepository, followRepo repository.FollowRepository) BlockService {
+	return &blockService{
+		blockRepo:  blockRepo,
+		userRepo:   userRepo,
+		followRepo: followRepo,
+	}
+}
+
+// Block memblokir pengguna lain
+func (s *blockService) Block(ctx context.Context, blockerID, blockedID uint) (*domain.BlockResponse, error) {
+	if blockerID == blockedID {
+		return nil, errors.ValidationError("Anda tidak dapat memblokir diri sendiri", nil)
+	}
+
+	target, err := s.userRepo.FindByID(ctx, blockedID)
+	if err != nil || target.IsAnonymized() {
+		return nil, errors.NotFoundError("Pengguna tidak ditemukan", err)
+	}
+
+	existing, err := s.blockRepo.Find(ctx, blockerID, blockedID)
+	if err != nil {
+		return nil, errors.InternalError("Gagal memeriksa status blokir", err)
+	}
+	if existing != nil {
+		response := existing.ToResponse()
+		return &response, nil
+	}
+
+	block := &domain.Block{
+		PemblokirID: blockerID,
+		DiblokirID:  blockedID,
+	}
+	if err := s.blockRepo.Create(ctx, block); err != nil {
+		return nil, errors.InternalError("Gagal memblokir pengguna", err)
+	}
+	// Pengguna yang saling memblokir tidak lagi saling mengikuti, sehingga barang baru
+	// keduanya tidak muncul di feed atau notifikasi satu sama lain
+	if err := s.followRepo.DeleteBetween(ctx, blockerID, blockedID); err != nil {
+		return nil, errors.InternalError("Gagal memblokir pengguna", err)
+	}
+	block.Diblokir = *target
+
+	response := block.ToResponse()
+	return &response, nil
+}
+
+// Unblock membuka blokir pengguna
+func (s *blockService) Unblock(ctx context.Context, blockerID, blockedID uint) error {
+	deleted, err := s.blockRepo.Delete(ctx, blockerID, blockedID)
+	if err != nil {
+		return errors.InternalError("Gagal membuka blokir pengguna", err)
+	}
+	if !deleted {
+		return errors.NotFoundError("Pengguna tidak ada dalam daftar blokir", nil)
+	}
+	return nil
+}
+
+// GetBlocked mendapatkan daftar pengguna yang diblokir dengan paginasi
+func (s *blockService) GetBlocked(ctx context.Context, blockerID uint, page, limit int) ([]domain.BlockResponse, int, int64, error) {
+	// Validasi input paginasi
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	blocks, total, err := s.blockRepo.FindByBlockerID(ctx, blockerID, page, limit)
+	if err != nil {
+		return nil, 0, 0, errors.InternalError("Gagal mengambil daftar blokir", err)
+	}
+
+	responses := make([]domain.BlockResponse, 0, len(blocks))
+	for _, block := range blocks {
+		responses = append(responses, block.ToResponse())
+	}
+
+	// Hitung total halaman
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return responses, totalPages, total, nil
+}
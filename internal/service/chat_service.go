@@ -6,6 +6,7 @@ import (
 	"math"
 
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
 	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
 )
 
@@ -15,16 +16,17 @@ type ChatService interface {
 	GetByID(ctx context.Context, id uint, userID uint) (*domain.ChatResponse, error)
 	GetByBarangID(ctx context.Context, barangID uint, page, limit int, userID uint) ([]domain.ChatResponse, int, int64, error)
 	GetConversation(ctx context.Context, pengirimID, penerimaID, barangID uint, page, limit int, userID uint) ([]domain.ChatResponse, int, int64, error)
-	GetChatPartners(ctx context.Context, userID uint) ([]domain.UserResponse, error)
+	GetChatPartners(ctx context.Context, userID uint) ([]domain.PublicUserResponse, error)
 	MarkAsRead(ctx context.Context, chatID uint, userID uint) error
 	Delete(ctx context.Context, id uint, userID uint) error
 }
 
 // chatService adalah implementasi dari ChatService
 type chatService struct {
-	chatRepo repository.ChatRepository
-	userRepo repository.UserRepository
-	itemRepo repository.ItemRepository
+	chatRepo  repository.ChatRepository
+	userRepo  repository.UserRepository
+	itemRepo  repository.ItemRepository
+	blockRepo repository.BlockRepository
 }
 
 // NewChatService membuat instance baru dari ChatService
@@ -32,11 +34,13 @@ func NewChatService(
 	chatRepo repository.ChatRepository,
 	userRepo repository.UserRepository,
 	itemRepo repository.ItemRepository,
+	blockRepo repository.BlockRepository,
 ) ChatService {
 	return &chatService{
-		chatRepo: chatRepo,
-		userRepo: userRepo,
-		itemRepo: itemRepo,
+		chatRepo:  chatRepo,
+		userRepo:  userRepo,
+		itemRepo:  itemRepo,
+		blockRepo: blockRepo,
 	}
 }
 
@@ -47,9 +51,19 @@ func (s *chatService) SendMessage(ctx context.Context, chat *domain.Chat, userID
 		return nil, errors.New("anda hanya dapat mengirim pesan sebagai diri sendiri")
 	}
 
-	// Cek apakah penerima ada
-	_, err := s.userRepo.FindByID(ctx, chat.PenerimaID)
+	// Cek apakah penerima ada dan akunnya tidak sedang dihapus
+	penerima, err := s.userRepo.FindByID(ctx, chat.PenerimaID)
+	if err != nil || penerima.IsDeleted() {
+		return nil, errors.New("penerima tidak ditemukan")
+	}
+
+	// Pesan antara pengguna yang saling memblokir ditolak dengan error yang sama,
+	// agar pengguna yang diblokir tidak tahu bahwa dirinya diblokir
+	blocked, err := s.blockRepo.IsBlockedBetween(ctx, userID, chat.PenerimaID)
 	if err != nil {
+		return nil, err
+	}
+	if blocked {
 		return nil, errors.New("penerima tidak ditemukan")
 	}
 
@@ -89,8 +103,8 @@ func (s *chatService) GetByID(ctx context.Context, id uint, userID uint) (*domai
 		return nil, err
 	}
 
-	// Cek apakah pengguna terlibat dalam chat
-	if chat.PengirimID != userID && chat.PenerimaID != userID {
+	// Cek apakah pengguna terlibat dalam chat atau moderator
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.ChatRead, chat) {
 		return nil, errors.New("anda tidak memiliki akses ke chat ini")
 	}
 
@@ -115,7 +129,7 @@ func (s *chatService) GetByBarangID(ctx context.Context, barangID uint, page, li
 	}
 
 	// Validasi akses (hanya penjual yang bisa melihat semua chat terkait barangnya)
-	if item.PenjualID != userID {
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.ChatListByItem, item) {
 		return nil, 0, 0, errors.New("anda tidak memiliki akses ke chat barang ini")
 	}
 
@@ -147,8 +161,8 @@ func (s *chatService) GetConversation(ctx context.Context, pengirimID, penerimaI
 		limit = 20 // Default lebih besar untuk percakapan
 	}
 
-	// Validasi pengguna terlibat dalam percakapan
-	if pengirimID != userID && penerimaID != userID {
+	// Validasi pengguna terlibat dalam percakapan, atau memiliki izin membaca semua chat
+	if pengirimID != userID && penerimaID != userID && !policy.Can(policy.PrincipalFor(ctx, userID), policy.ChatRead, nil) {
 		return nil, 0, 0, errors.New("anda tidak memiliki akses ke percakapan ini")
 	}
 
@@ -177,17 +191,17 @@ func (s *chatService) GetConversation(ctx context.Context, pengirimID, penerimaI
 	return chatResponses, totalPages, total, nil
 }
 
-// GetChatPartners mendapatkan daftar partner chat
-func (s *chatService) GetChatPartners(ctx context.Context, userID uint) ([]domain.UserResponse, error) {
+// GetChatPartners mendapatkan daftar partner chat. Partner hanya ditampilkan dengan data publiknya.
+func (s *chatService) GetChatPartners(ctx context.Context, userID uint) ([]domain.PublicUserResponse, error) {
 	users, err := s.chatRepo.FindChatPartners(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Konversi ke format respons
-	var userResponses []domain.UserResponse
+	var userResponses []domain.PublicUserResponse
 	for _, user := range users {
-		userResponses = append(userResponses, user.ToResponse())
+		userResponses = append(userResponses, user.ToPublicResponse())
 	}
 
 	return userResponses, nil
@@ -202,7 +216,7 @@ func (s *chatService) MarkAsRead(ctx context.Context, chatID uint, userID uint)
 	}
 
 	// Validasi penerima
-	if chat.PenerimaID != userID {
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.ChatMarkRead, chat) {
 		return errors.New("anda tidak dapat menandai pesan ini sebagai dibaca")
 	}
 
@@ -218,8 +232,8 @@ func (s *chatService) Delete(ctx context.Context, id uint, userID uint) error {
 		return err
 	}
 
-	// Validasi pengguna (hanya pengirim yang dapat menghapus)
-	if chat.PengirimID != userID {
+	// Validasi pengguna (hanya pengirim, moderator atau admin yang dapat menghapus)
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.ChatDelete, chat) {
 		return errors.New("anda tidak memiliki izin untuk menghapus pesan ini")
 	}
 
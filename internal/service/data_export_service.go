@@ -0,0 +1,453 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+const (
+	// exportPageSize adalah jumlah data yang diambil per halaman saat mengumpulkan isi arsip
+	exportPageSize = 100
+	// exportStaleAfter adalah batas waktu ekspor dianggap macet (misalnya server mati saat memproses)
+	exportStaleAfter = 30 * time.Minute
+)
+
+// DataExportService adalah interface untuk layanan ekspor data pribadi pengguna
+type DataExportService interface {
+	// Request membuat permintaan ekspor baru. Jika masih ada ekspor yang menunggu atau
+	// sedang diproses, permintaan tersebut yang dikembalikan.
+	Request(ctx context.Context, userID uint) (*domain.DataExportResponse, error)
+	// GetLatest mengembalikan status ekspor terbaru beserta tautan unduhan jika arsip sudah siap
+	GetLatest(ctx context.Context, userID uint) (*domain.DataExportResponse, error)
+	// OpenDownload memverifikasi token tautan unduhan dan mengembalikan lokasi serta nama file arsip
+	OpenDownload(ctx context.Context, token string) (string, string, error)
+	// Run menjalankan worker pembuat arsip sampai ctx dibatalkan
+	Run(ctx context.Context)
+}
+
+// dataExportService adalah implementasi dari DataExportService
+type dataExportService struct {
+	exportRepo      repository.DataExportRepository
+	userRepo        repository.UserRepository
+	itemRepo        repository.ItemRepository
+	transactionRepo repository.TransactionRepository
+	chatRepo        repository.ChatRepository
+	config          *config.Config
+	// wake membangunkan worker saat ada permintaan baru tanpa menunggu PollInterval
+	wake chan struct{}
+}
+
+// NewDataExportService membuat instance baru dari DataExportService
+func NewDataExportService(
+	exportRepo repository.DataExportRepository,
+	userRepo repository.UserRepository,
+	itemRepo repository.ItemRepository,
+	transactionRepo repository.TransactionRepository,
+	chatRepo repository.ChatRepository,
+	cfg *config.Config,
+) DataExportService {
+	return &dataExportService{
+		exportRepo:      exportRepo,
+		userRepo:        userRepo,
+		itemRepo:        itemRepo,
+		transactionRepo: transactionRepo,
+		chatRepo:        chatRepo,
+		config:          cfg,
+		wake:            make(chan struct{}, 1),
+	}
+}
+
+// Request membuat permintaan ekspor baru untuk pengguna
+func (s *dataExportService) Request(ctx context.Context, userID uint) (*domain.DataExportResponse, error) {
+	latest, err := s.exportRepo.FindLatestByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.IsActive() {
+		response := latest.ToResponse()
+		return &response, nil
+	}
+
+	export := &domain.DataExport{
+		PenggunaID: userID,
+		Status:     domain.DataExportPending,
+	}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	response := export.ToResponse()
+	return &response, nil
+}
+
+// GetLatest mengembalikan status ekspor terbaru beserta tautan unduhan jika arsip sudah siap
+func (s *dataExportService) GetLatest(ctx context.Context, userID uint) (*domain.DataExportResponse, error) {
+	export, err := s.exportRepo.FindLatestByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil {
+		return nil, errors.NotFoundError("belum ada permintaan ekspor data", nil)
+	}
+
+	response := export.ToResponse()
+	now := time.Now()
+	if export.IsDownloadable(now) {
+		// Tautan tidak boleh berlaku lebih lama dari arsipnya
+		linkExpiresAt := now.Add(s.config.Export.LinkExpiry)
+		if export.ExpiresAt.Before(linkExpiresAt) {
+			linkExpiresAt = *export.ExpiresAt
+		}
+		linkExpiresAt = linkExpiresAt.Truncate(time.Second)
+
+		response.DownloadURL = "/api/v1/users/me/export/download?token=" + url.QueryEscape(s.downloadToken(export, linkExpiresAt))
+		response.DownloadURLExpiresAt = &linkExpiresAt
+	}
+	return &response, nil
+}
+
+// OpenDownload memverifikasi token tautan unduhan dan mengembalikan lokasi serta nama file arsip
+func (s *dataExportService) OpenDownload(ctx context.Context, token string) (string, string, error) {
+	invalid := errors.ForbiddenError("tautan unduhan tidak valid atau sudah kedaluwarsa", nil)
+
+	// Format token: <export_id>.<expires_unix>.<signature>
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", "", invalid
+	}
+	exportID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return "", "", invalid
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return "", "", invalid
+	}
+
+	export, err := s.exportRepo.FindByID(ctx, uint(exportID))
+	if err != nil {
+		return "", "", invalid
+	}
+	userID := strconv.FormatUint(uint64(export.PenggunaID), 10)
+	if !utils.VerifySignedValues(s.config.JWT.Secret, parts[2], "data-export", parts[0], parts[1], userID) {
+		return "", "", invalid
+	}
+	if !export.IsDownloadable(time.Now()) {
+		return "", "", errors.NotFoundError("arsip ekspor sudah tidak tersedia, silakan minta ekspor baru", nil)
+	}
+
+	fileName := fmt.Sprintf("jubel-data-%s-%s.zip", userID, export.CreatedAt.Format("20060102"))
+	return export.FilePath, fileName, nil
+}
+
+// downloadToken membuat token tautan unduhan yang ditandatangani dan berlaku sampai expiresAt
+func (s *dataExportService) downloadToken(export *domain.DataExport, expiresAt time.Time) string {
+	exportID := strconv.FormatUint(uint64(export.ID), 10)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	userID := strconv.FormatUint(uint64(export.PenggunaID), 10)
+	signature := utils.SignValues(s.config.JWT.Secret, "data-export", exportID, expires, userID)
+	return exportID + "." + expires + "." + signature
+}
+
+// Run menjalankan worker pembuat arsip sampai ctx dibatalkan.
+// Worker juga menghapus arsip yang sudah melewati masa simpan.
+func (s *dataExportService) Run(ctx context.Context) {
+	if err := os.MkdirAll(s.config.Export.Dir, 0755); err != nil {
+		log.Printf("Gagal membuat direktori ekspor: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(s.config.Export.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.processPending(ctx)
+		s.removeExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// processPending memproses semua permintaan ekspor yang menunggu
+func (s *dataExportService) processPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		export, err := s.exportRepo.ClaimNext(ctx, exportStaleAfter)
+		if err != nil {
+			log.Printf("Gagal mengambil permintaan ekspor: %v", err)
+			return
+		}
+		if export == nil {
+			return
+		}
+
+		filePath, err := s.buildArchive(ctx, export)
+		now := time.Now()
+		if err != nil {
+			log.Printf("Gagal membuat ekspor data #%d untuk pengguna %d: %v", export.ID, export.PenggunaID, err)
+			export.Status = domain.DataExportFailed
+			export.Error = err.Error()
+		} else {
+			expiresAt := now.Add(s.config.Export.Retention)
+			export.Status = domain.DataExportReady
+			export.FilePath = filePath
+			export.ExpiresAt = &expiresAt
+		}
+		export.CompletedAt = &now
+
+		if err := s.exportRepo.Update(ctx, export); err != nil {
+			log.Printf("Gagal menyimpan status ekspor data #%d: %v", export.ID, err)
+		}
+	}
+}
+
+// removeExpired menghapus arsip yang sudah melewati masa simpan
+func (s *dataExportService) removeExpired(ctx context.Context) {
+	exports, err := s.exportRepo.FindExpired(ctx, time.Now())
+	if err != nil {
+		log.Printf("Gagal mencari ekspor data kedaluwarsa: %v", err)
+		return
+	}
+
+	for i := range exports {
+		export := &exports[i]
+		if err := os.Remove(export.FilePath); err != nil && !os.IsNotExist(err) {
+			log.Printf("Gagal menghapus arsip ekspor data #%d: %v", export.ID, err)
+			continue
+		}
+		export.Status = domain.DataExportExpired
+		export.FilePath = ""
+		if err := s.exportRepo.Update(ctx, export); err != nil {
+			log.Printf("Gagal menyimpan status ekspor data #%d: %v", export.ID, err)
+		}
+	}
+}
+
+// exportArchive berisi semua data pengguna yang dimasukkan ke arsip
+type exportArchive struct {
+	profile   domain.UserResponse
+	items     []domain.ExportedItem
+	purchases []domain.ExportedTransaction
+	sales     []domain.ExportedTransaction
+	chats     []domain.ExportedChat
+	images    []domain.ExportedImage
+}
+
+// collect mengumpulkan data pengguna dari repository
+func (s *dataExportService) collect(ctx context.Context, userID uint) (*exportArchive, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	archive := &exportArchive{profile: user.ToResponse()}
+	if user.AvatarURL != "" {
+		archive.images = append(archive.images, domain.ExportedImage{Jenis: "avatar", URL: user.AvatarURL})
+	}
+
+	// Barang termasuk yang sudah dihapus
+	items, err := s.itemRepo.FindAllByPenjualIDWithDeleted(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil barang: %w", err)
+	}
+	itemsByID := make(map[uint]*domain.Item, len(items))
+	for i := range items {
+		item := &items[i]
+		itemsByID[item.ID] = item
+		archive.items = append(archive.items, domain.NewExportedItem(item))
+		for _, image := range item.Images {
+			archive.images = append(archive.images, domain.ExportedImage{Jenis: "barang", BarangID: item.ID, URL: image.URL})
+		}
+	}
+
+	for page := 1; ; page++ {
+		transactions, total, err := s.transactionRepo.FindByPembeliID(ctx, userID, page, exportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil transaksi pembelian: %w", err)
+		}
+		for i := range transactions {
+			archive.purchases = append(archive.purchases, domain.NewExportedTransaction(&transactions[i]))
+		}
+		if int64(page*exportPageSize) >= total {
+			break
+		}
+	}
+
+	for page := 1; ; page++ {
+		transactions, total, err := s.transactionRepo.FindByPenjualID(ctx, userID, page, exportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil transaksi penjualan: %w", err)
+		}
+		for i := range transactions {
+			transaction := &transactions[i]
+			// Relasi barang tidak dimuat untuk barang yang sudah dihapus
+			if item, ok := itemsByID[transaction.BarangID]; ok {
+				transaction.Barang = *item
+			}
+			archive.sales = append(archive.sales, domain.NewExportedTransaction(transaction))
+		}
+		if int64(page*exportPageSize) >= total {
+			break
+		}
+	}
+
+	for page := 1; ; page++ {
+		chats, total, err := s.chatRepo.FindByUserID(ctx, userID, page, exportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil chat: %w", err)
+		}
+		for i := range chats {
+			archive.chats = append(archive.chats, domain.NewExportedChat(&chats[i]))
+		}
+		if int64(page*exportPageSize) >= total {
+			break
+		}
+	}
+
+	return archive, nil
+}
+
+// buildArchive mengumpulkan data pengguna dan menulisnya sebagai arsip ZIP.
+// Arsip ditulis ke file sementara lalu dipindahkan agar tidak pernah terunduh setengah jadi.
+func (s *dataExportService) buildArchive(ctx context.Context, export *domain.DataExport) (string, error) {
+	archive, err := s.collect(ctx, export.PenggunaID)
+	if err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(s.config.Export.Dir, fmt.Sprintf("ekspor_%d_%d.zip", export.PenggunaID, export.ID))
+	tmp, err := os.CreateTemp(s.config.Export.Dir, "ekspor_*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("gagal membuat file arsip: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := zip.NewWriter(tmp)
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profil.json", archive.profile},
+		{"barang.json", nonNil(archive.items)},
+		{"transaksi_pembelian.json", nonNil(archive.purchases)},
+		{"transaksi_penjualan.json", nonNil(archive.sales)},
+		{"chat.json", nonNil(archive.chats)},
+		{"gambar.json", nonNil(archive.images)},
+	}
+	for _, file := range files {
+		if err := writeZipJSON(zw, file.name, file.data); err != nil {
+			tmp.Close()
+			return "", err
+		}
+	}
+	if err := writeZipFile(zw, "RINGKASAN.txt", []byte(archive.summary(time.Now()))); err != nil {
+		tmp.Close()
+		return "", err
+	}
+
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("gagal menulis arsip: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("gagal menulis arsip: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return "", fmt.Errorf("gagal menyimpan arsip: %w", err)
+	}
+	return filePath, nil
+}
+
+// summary membuat ringkasan isi arsip yang mudah dibaca
+func (a *exportArchive) summary(now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Ekspor data pribadi Jubel\n")
+	fmt.Fprintf(&b, "Dibuat pada: %s\n\n", now.Format("02 January 2006 15:04 MST"))
+
+	fmt.Fprintf(&b, "Profil\n")
+	fmt.Fprintf(&b, "  Nama          : %s\n", a.profile.Nama)
+	fmt.Fprintf(&b, "  Email         : %s\n", a.profile.Email)
+	fmt.Fprintf(&b, "  No. HP        : %s\n", a.profile.NoHP)
+	fmt.Fprintf(&b, "  Alamat        : %s\n", a.profile.Alamat)
+	fmt.Fprintf(&b, "  Bergabung     : %s\n\n", a.profile.CreatedAt.Format("02 January 2006"))
+
+	deleted := 0
+	for _, item := range a.items {
+		if item.DeletedAt != nil {
+			deleted++
+		}
+	}
+
+	fmt.Fprintf(&b, "Isi arsip\n")
+	fmt.Fprintf(&b, "  profil.json               : data akun\n")
+	fmt.Fprintf(&b, "  barang.json               : %d barang (%d sudah dihapus)\n", len(a.items), deleted)
+	fmt.Fprintf(&b, "  transaksi_pembelian.json  : %d transaksi sebagai pembeli\n", len(a.purchases))
+	fmt.Fprintf(&b, "  transaksi_penjualan.json  : %d transaksi sebagai penjual\n", len(a.sales))
+	fmt.Fprintf(&b, "  chat.json                 : %d pesan dikirim dan diterima\n", len(a.chats))
+	fmt.Fprintf(&b, "  gambar.json               : %d referensi gambar yang diunggah\n", len(a.images))
+
+	if len(a.items) > 0 {
+		fmt.Fprintf(&b, "\nBarang\n")
+		for _, item := range a.items {
+			status := string(item.Status)
+			if item.DeletedAt != nil {
+				status = "dihapus " + item.DeletedAt.Format("02-01-2006")
+			}
+			fmt.Fprintf(&b, "  - #%d %s, Rp%.0f (%s)\n", item.ID, item.NamaBarang, item.Harga, status)
+		}
+	}
+
+	return b.String()
+}
+
+// writeZipJSON menulis data sebagai file JSON di dalam arsip
+func writeZipJSON(zw *zip.Writer, name string, data interface{}) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("gagal mengubah %s ke JSON: %w", name, err)
+	}
+	return writeZipFile(zw, name, content)
+}
+
+// writeZipFile menulis satu file ke dalam arsip
+func writeZipFile(zw *zip.Writer, name string, content []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("gagal menambahkan %s ke arsip: %w", name, err)
+	}
+	if _, err := w.Write(content); err != nil {
+		return fmt.Errorf("gagal menulis %s ke arsip: %w", name, err)
+	}
+	return nil
+}
+
+// nonNil memastikan slice kosong ditulis sebagai [] dan bukan null
+func nonNil[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return values
+}
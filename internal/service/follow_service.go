@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+)
+
+// maxFeedLimit adalah jumlah barang maksimal per halaman feed
+const maxFeedLimit = 50
+
+// FollowService adalah interface untuk layanan mengikuti penjual dan feed barang
+type FollowService interface {
+	// Follow mengikuti penjual. Mengikuti penjual yang sudah diikuti tidak dianggap error.
+	Follow(ctx context.Context, followerID, followedID uint) (*domain.FollowResponse, error)
+	// Unfollow berhenti mengikuti penjual
+	Unfollow(ctx context.Context, followerID, followedID uint) error
+	// GetFollowers mendapatkan daftar pengikut pengguna dengan paginasi
+	GetFollowers(ctx context.Context, userID uint, page, limit int) ([]domain.FollowResponse, int, int64, error)
+	// GetFollowing mendapatkan daftar penjual yang diikuti pengguna dengan paginasi
+	GetFollowing(ctx context.Context, userID uint, page, limit int) ([]domain.FollowResponse, int, int64, error)
+	// GetFeed mendapatkan barang Tersedia terbaru dari penjual yang diikuti.
+	// Mengembalikan cursor halaman berikutnya, kosong jika tidak ada lagi barang.
+	GetFeed(ctx context.Context, followerID uint, cursor string, limit int) ([]domain.ItemResponse, string, error)
+}
+
+// followService adalah implementasi dari FollowService
+type followService struct {
+	followRepo repository.FollowRepository
+	userRepo   repository.UserRepository
+	blockRepo  repository.BlockRepository
+	itemRepo   repository.ItemRepository
+}
+
+// NewFollowService membuat instance baru dari FollowService
+func NewFollowService(followRepo repository.FollowRepository, userRepo repository.UserRepository, blockRepo repository.BlockRepository, itemRepo repository.ItemRepository) FollowService {
+	return &followService{
+		followRepo: followRepo,
+		userRepo:   userRepo,
+		blockRepo:  blockRepo,
+		itemRepo:   itemRepo,
+	}
+}
+
+// Follow mengikuti penjual
+func (s *followService) Follow(ctx context.Context, followerID, followedID uint) (*domain.FollowResponse, error) {
+	if followerID == followedID {
+		return nil, errors.ValidationError("Anda tidak dapat mengikuti diri sendiri", nil)
+	}
+
+	target, err := s.userRepo.FindByID(ctx, followedID)
+	if err != nil || target.IsAnonymized() || target.IsHidden() {
+		return nil, errors.NotFoundError("Pengguna tidak ditemukan", err)
+	}
+
+	// Pengguna yang saling memblokir tidak dapat saling mengikuti. Error yang sama dengan
+	// pengguna yang tidak ada dipakai agar pemblokiran tidak diketahui pengguna yang diblokir.
+	blocked, err := s.blockRepo.IsBlockedBetween(ctx, followerID, followedID)
+	if err != nil {
+		return nil, errors.InternalError("Gagal memeriksa status blokir", err)
+	}
+	if blocked {
+		return nil, errors.NotFoundError("Pengguna tidak ditemukan", nil)
+	}
+
+	existing, err := s.followRepo.Find(ctx, followerID, followedID)
+	if err != nil {
+		return nil, errors.InternalError("Gagal memeriksa status mengikuti", err)
+	}
+	if existing != nil {
+		response := domain.NewFollowResponse(target, existing.CreatedAt)
+		return &response, nil
+	}
+
+	follow := &domain.Follow{
+		PengikutID: followerID,
+		DiikutiID:  followedID,
+	}
+	if err := s.followRepo.Create(ctx, follow); err != nil {
+		return nil, errors.InternalError("Gagal mengikuti pengguna", err)
+	}
+
+	response := domain.NewFollowResponse(target, follow.CreatedAt)
+	return &response, nil
+}
+
+// Unfollow berhenti mengikuti penjual
+func (s *followService) Unfollow(ctx context.Context, followerID, followedID uint) error {
+	deleted, err := s.followRepo.Delete(ctx, followerID, followedID)
+	if err != nil {
+		return errors.InternalError("Gagal berhenti mengikuti pengguna", err)
+	}
+	if !deleted {
+		return errors.NotFoundError("Anda tidak mengikuti pengguna ini", nil)
+	}
+	return nil
+}
+
+// GetFollowers mendapatkan daftar pengikut pengguna dengan paginasi
+func (s *followService) GetFollowers(ctx context.Context, userID uint, page, limit int) ([]domain.FollowResponse, int, int64, error) {
+	return s.getPage(ctx, s.followRepo.FindFollowers, userID, page, limit, func(f *domain.Follow) *domain.User {
+		return &f.Pengikut
+	})
+}
+
+// GetFollowing mendapatkan daftar penjual yang diikuti pengguna dengan paginasi
+func (s *followService) GetFollowing(ctx context.Context, userID uint, page, limit int) ([]domain.FollowResponse, int, int64, error) {
+	return s.getPage(ctx, s.followRepo.FindFollowing, userID, page, limit, func(f *domain.Follow) *domain.User {
+		return &f.Diikuti
+	})
+}
+
+// getPage mengambil satu halaman hubungan mengikuti dan mengubahnya ke FollowResponse
+// dengan pengguna di sisi lain hubungan
+func (s *followService) getPage(
+	ctx context.Context,
+	find func(ctx context.Context, userID uint, page, limit int) ([]domain.Follow, int64, error),
+	userID uint, page, limit int,
+	other func(f *domain.Follow) *domain.User,
+) ([]domain.FollowResponse, int, int64, error) {
+	// Validasi input paginasi
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	follows, total, err := find(ctx, userID, page, limit)
+	if err != nil {
+		return nil, 0, 0, errors.InternalError("Gagal mengambil daftar pengguna", err)
+	}
+
+	responses := make([]domain.FollowResponse, 0, len(follows))
+	for i := range follows {
+		responses = append(responses, domain.NewFollowResponse(other(&follows[i]), follows[i].CreatedAt))
+	}
+
+	// Hitung total halaman
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return responses, totalPages, total, nil
+}
+
+// GetFeed mendapatkan barang Tersedia terbaru dari penjual yang diikuti
+func (s *followService) GetFeed(ctx context.Context, followerID uint, cursor string, limit int) ([]domain.ItemResponse, string, error) {
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > maxFeedLimit {
+		limit = maxFeedLimit
+	}
+
+	var after *domain.FeedCursor
+	if cursor != "" {
+		parsed, err := domain.ParseFeedCursor(cursor)
+		if err != nil {
+			return nil, "", errors.ValidationError("cursor tidak valid", err)
+		}
+		after = parsed
+	}
+
+	// Ambil satu barang lebih banyak untuk mengetahui apakah masih ada halaman berikutnya
+	items, err := s.itemRepo.FindFeed(ctx, followerID, after, limit+1)
+	if err != nil {
+		return nil, "", errors.InternalError("Gagal mengambil feed", err)
+	}
+
+	var nextCursor string
+	if len(items) > limit {
+		items = items[:limit]
+		last := items[len(items)-1]
+		nextCursor = domain.FeedCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	responses := make([]domain.ItemResponse, 0, len(items))
+	for i := range items {
+		responses = append(responses, items[i].ToResponse(true))
+	}
+
+	return responses, nextCursor, nil
+}
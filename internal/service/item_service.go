@@ -1,49 +1,70 @@
 package service
 
 import (
-	"bytes"
 	"context"
 	stdErrors "errors"
 	"fmt"
 	"io"
+	"log"
 	"math"
 	"mime/multipart"
-	"net/http"
-	"os"
-	"path/filepath"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mfuadfakhruzzaki/jubel/internal/config"
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
 	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/imaging"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
 	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+	"github.com/mfuadfakhruzzaki/jubel/internal/storage"
 )
 
+// maxImageCaptionLength adalah panjang maksimal keterangan gambar barang
+const maxImageCaptionLength = 255
+
 // ItemService adalah interface untuk layanan barang
 type ItemService interface {
 	Create(ctx context.Context, item *domain.Item, userID uint) (*domain.ItemResponse, error)
 	GetByID(ctx context.Context, id uint) (*domain.ItemResponse, error)
-	GetAll(ctx context.Context, page, limit int, search, kategori, status string) ([]domain.ItemResponse, int, int64, error)
+	// GetAll mendapatkan daftar barang. Jika filter.Search diisi, setiap barang dilengkapi cuplikan hasil pencarian.
+	GetAll(ctx context.Context, filter domain.ItemFilter, page, limit int) ([]domain.ItemResponse, int, int64, domain.SearchMatch, error)
 	GetByPenjualID(ctx context.Context, penjualID uint, page, limit int) ([]domain.ItemResponse, int, int64, error)
 	Update(ctx context.Context, id uint, item *domain.Item, userID uint) (*domain.ItemResponse, error)
 	UpdateStatus(ctx context.Context, id uint, status domain.ItemStatus, userID uint) error
 	Delete(ctx context.Context, id uint, userID uint) error
-	// UploadImage mengembalikan string dalam format "fileID|fileName|viewURL"
+	// UploadImage menambahkan satu gambar ke galeri dan menjadikannya sampul.
+	// Mengembalikan string dalam format "fileID|fileName|viewURL"
 	UploadImage(ctx *gin.Context, itemID uint, userID uint) (string, error)
+	// UploadImages menambahkan beberapa gambar sekaligus ke galeri barang. captions[i] adalah keterangan files[i].
+	UploadImages(ctx context.Context, itemID, userID uint, files []*multipart.FileHeader, captions []string) ([]domain.ItemImageResponse, error)
+	// ReorderImages mengubah urutan galeri barang
+	ReorderImages(ctx context.Context, itemID, userID uint, imageIDs []uint) ([]domain.ItemImageResponse, error)
+	// UpdateImage mengubah keterangan gambar atau menjadikannya sampul
+	UpdateImage(ctx context.Context, itemID, imageID, userID uint, req domain.UpdateItemImageRequest) ([]domain.ItemImageResponse, error)
+	// DeleteImage menghapus gambar dari galeri barang dan storage
+	DeleteImage(ctx context.Context, itemID, imageID, userID uint) ([]domain.ItemImageResponse, error)
 }
 
 // itemService adalah implementasi dari ItemService
 type itemService struct {
-	itemRepo repository.ItemRepository
-	config   *config.Config
+	itemRepo            repository.ItemRepository
+	itemImageRepo       repository.ItemImageRepository
+	notificationService NotificationService
+	store               storage.Store
+	config              *config.Config
 }
 
 // NewItemService membuat instance baru dari ItemService
-func NewItemService(itemRepo repository.ItemRepository, config *config.Config) ItemService {
+func NewItemService(itemRepo repository.ItemRepository, itemImageRepo repository.ItemImageRepository, notificationService NotificationService, store storage.Store, config *config.Config) ItemService {
 	return &itemService{
-		itemRepo: itemRepo,
-		config:   config,
+		itemRepo:            itemRepo,
+		itemImageRepo:       itemImageRepo,
+		notificationService: notificationService,
+		store:               store,
+		config:              config,
 	}
 }
 
@@ -66,6 +87,11 @@ func (s *itemService) Create(ctx context.Context, item *domain.Item, userID uint
 		return nil, errors.InternalError("Gagal mendapatkan data barang yang baru dibuat", err)
 	}
 
+	// Beri tahu pengikut penjual. Barang tetap dibuat walaupun notifikasi gagal dikirim.
+	if err := s.notificationService.NotifyNewListing(ctx, createdItem); err != nil {
+		log.Printf("Gagal mengirim notifikasi barang baru: %v", err)
+	}
+
 	// Kembalikan response
 	response := createdItem.ToResponse(true)
 	return &response, nil
@@ -82,12 +108,19 @@ func (s *itemService) GetByID(ctx context.Context, id uint) (*domain.ItemRespons
 		return nil, errors.InternalError("Gagal mendapatkan data barang", err)
 	}
 
+	// Barang yang disembunyikan karena laporan, termasuk semua barang milik penjual yang disembunyikan,
+	// hanya terlihat oleh pemilik dan moderator
+	if (item.IsHidden() || item.Penjual.IsHidden()) && !canSeeHidden(ctx, item.PenjualID) {
+		return nil, errors.NotFoundError(fmt.Sprintf("Barang dengan ID %d tidak ditemukan", id), nil)
+	}
+
 	response := item.ToResponse(true)
 	return &response, nil
 }
 
-// GetAll mendapatkan semua barang dengan paginasi dan filter
-func (s *itemService) GetAll(ctx context.Context, page, limit int, search, kategori, status string) ([]domain.ItemResponse, int, int64, error) {
+// GetAll mendapatkan semua barang dengan paginasi dan filter.
+// SearchMatch menunjukkan cara pencarian teks menemukan barang.
+func (s *itemService) GetAll(ctx context.Context, filter domain.ItemFilter, page, limit int) ([]domain.ItemResponse, int, int64, domain.SearchMatch, error) {
 	// Validasi input paginasi
 	if page < 1 {
 		page = 1
@@ -96,22 +129,48 @@ func (s *itemService) GetAll(ctx context.Context, page, limit int, search, kateg
 		limit = 10
 	}
 
+	if !filter.Sort.IsValid() {
+		return nil, 0, 0, domain.SearchMatchNone, errors.ValidationError("sort harus newest atau relevance", nil)
+	}
+
 	// Dapatkan barang dari repository
-	items, total, err := s.itemRepo.FindAll(ctx, page, limit, search, kategori, status)
+	// Pengguna yang login tidak melihat barang milik pengguna yang diblokirnya
+	if principal, ok := policy.FromContext(ctx); ok {
+		filter.ViewerID = principal.UserID
+	}
+
+	items, total, match, err := s.itemRepo.FindAll(ctx, filter, page, limit)
 	if err != nil {
-		return nil, 0, 0, err
+		return nil, 0, 0, domain.SearchMatchNone, errors.InternalError("Gagal mengambil daftar barang", err)
+	}
+
+	// Cuplikan hasil pencarian dengan kata yang cocok ditandai
+	var highlights map[uint]domain.ItemHighlight
+	if filter.Search != "" && len(items) > 0 {
+		ids := make([]uint, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		highlights, err = s.itemRepo.FindHighlights(ctx, ids, filter.Search)
+		if err != nil {
+			return nil, 0, 0, domain.SearchMatchNone, errors.InternalError("Gagal membuat cuplikan hasil pencarian", err)
+		}
 	}
 
 	// Konversi ke format respons
 	var itemResponses []domain.ItemResponse
 	for _, item := range items {
-		itemResponses = append(itemResponses, item.ToResponse(true))
+		response := item.ToResponse(true)
+		if highlight, ok := highlights[item.ID]; ok {
+			response.Sorotan = &highlight
+		}
+		itemResponses = append(itemResponses, response)
 	}
 
 	// Hitung total halaman
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
 
-	return itemResponses, totalPages, total, nil
+	return itemResponses, totalPages, total, match, nil
 }
 
 // GetByPenjualID mendapatkan barang berdasarkan ID penjual
@@ -125,7 +184,7 @@ func (s *itemService) GetByPenjualID(ctx context.Context, penjualID uint, page,
 	}
 
 	// Dapatkan barang dari repository
-	items, total, err := s.itemRepo.FindByPenjualID(ctx, penjualID, page, limit)
+	items, total, err := s.itemRepo.FindByPenjualID(ctx, penjualID, page, limit, canSeeHidden(ctx, penjualID))
 	if err != nil {
 		return nil, 0, 0, err
 	}
@@ -150,8 +209,8 @@ func (s *itemService) Update(ctx context.Context, id uint, itemData *domain.Item
 		return nil, err
 	}
 
-	// Cek apakah pengguna adalah pemilik barang
-	if existingItem.PenjualID != userID {
+	// Cek apakah pengguna boleh mengubah barang
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.ItemUpdate, existingItem) {
 		return nil, errors.New("anda tidak memiliki izin untuk mengubah barang ini")
 	}
 
@@ -187,8 +246,8 @@ func (s *itemService) UpdateStatus(ctx context.Context, id uint, status domain.I
 		return err
 	}
 
-	// Cek apakah pengguna adalah pemilik barang
-	if existingItem.PenjualID != userID {
+	// Cek apakah pengguna boleh mengubah status barang
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.ItemUpdateStatus, existingItem) {
 		return errors.New("anda tidak memiliki izin untuk mengubah status barang ini")
 	}
 
@@ -201,19 +260,11 @@ func (s *itemService) Delete(ctx context.Context, id uint, userID uint) error {
 	// Dapatkan barang yang ada
 	existingItem, err := s.itemRepo.FindByID(ctx, id)
 	if err != nil {
-		if stdErrors.Is(err, stdErrors.New("record not found")) {
-			return errors.NotFoundError(fmt.Sprintf("Barang dengan ID %d tidak ditemukan", id), err)
-		}
-		return errors.InternalError("Gagal mendapatkan data barang", err)
+		return errors.NotFoundError(fmt.Sprintf("Barang dengan ID %d tidak ditemukan", id), err)
 	}
 
-	// Cek apakah pengguna adalah pemilik barang atau admin
-	isAdmin, exists := ctx.Value("isAdmin").(bool)
-	if !exists {
-		isAdmin = false
-	}
-	
-	if existingItem.PenjualID != userID && !isAdmin {
+	// Pemilik barang, admin dan moderator boleh menghapus barang
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.ItemDelete, existingItem) {
 		return errors.ForbiddenError(
 			"Anda tidak memiliki izin untuk menghapus barang ini", 
 			stdErrors.New("unauthorized access attempt"),
@@ -229,188 +280,363 @@ func (s *itemService) Delete(ctx context.Context, id uint, userID uint) error {
 	return nil
 }
 
-// UploadImage mengupload gambar untuk barang
+// UploadImage mengupload gambar untuk barang dan menjadikannya sampul
 func (s *itemService) UploadImage(ctx *gin.Context, itemID uint, userID uint) (string, error) {
-	// Dapatkan barang yang ada
-	existingItem, err := s.itemRepo.FindByID(ctx, itemID)
+	reqCtx := ctx.Request.Context()
+	if _, err := s.findEditableItem(reqCtx, itemID, userID, "Anda tidak memiliki izin untuk mengupload gambar barang ini"); err != nil {
+		return "", err
+	}
+
+	// Dapatkan file dari form
+	file, err := ctx.FormFile("gambar")
 	if err != nil {
-		if stdErrors.Is(err, stdErrors.New("record not found")) {
-			return "", errors.NotFoundError(fmt.Sprintf("Barang dengan ID %d tidak ditemukan", itemID), err)
+		return "", errors.ValidationError("Gagal mendapatkan file gambar", err)
+	}
+	if err := s.checkGalleryCapacity(reqCtx, itemID, 1); err != nil {
+		return "", err
+	}
+
+	fileBytes, err := s.readImageFile(file)
+	if err != nil {
+		return "", err
+	}
+	image, err := s.storeImage(reqCtx, itemID, 0, fileBytes)
+	if err != nil {
+		return "", err
+	}
+
+	// Simpan gambar di galeri sebagai sampul, sehingga kolom gambar barang ikut diperbarui
+	if err := s.itemImageRepo.Create(reqCtx, itemID, []domain.ItemImage{image}, true); err != nil {
+		s.deleteImageFiles(reqCtx, image.FileKey)
+		return "", galleryCreateError(err)
+	}
+
+	// Return format fileID|fileName|viewURL untuk penggunaan di handler
+	gambarInfo := fmt.Sprintf("%s|%s|%s", image.FileKey, image.FileKey, image.URL)
+	return gambarInfo, nil
+}
+
+// UploadImages menambahkan beberapa gambar sekaligus ke galeri barang
+func (s *itemService) UploadImages(ctx context.Context, itemID, userID uint, files []*multipart.FileHeader, captions []string) ([]domain.ItemImageResponse, error) {
+	if _, err := s.findEditableItem(ctx, itemID, userID, "Anda tidak memiliki izin untuk mengupload gambar barang ini"); err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, errors.ValidationError("Minimal satu file gambar harus diupload", nil)
+	}
+	if len(captions) > len(files) {
+		return nil, errors.ValidationError("Jumlah keterangan melebihi jumlah gambar", nil)
+	}
+	for _, caption := range captions {
+		if err := validateImageCaption(caption); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkGalleryCapacity(ctx, itemID, len(files)); err != nil {
+		return nil, err
+	}
+
+	// Semua file diperiksa lebih dulu agar tidak ada file yang tersimpan jika salah satunya tidak valid
+	contents := make([][]byte, len(files))
+	for i, file := range files {
+		fileBytes, err := s.readImageFile(file)
+		if err != nil {
+			return nil, err
+		}
+		contents[i] = fileBytes
+	}
+
+	// Upload file; file yang sudah tersimpan dihapus kembali jika ada yang gagal
+	images := make([]domain.ItemImage, 0, len(files))
+	keys := make([]string, 0, len(files))
+	for i := range files {
+		image, err := s.storeImage(ctx, itemID, i, contents[i])
+		if err != nil {
+			s.deleteImageFiles(ctx, keys...)
+			return nil, err
+		}
+		keys = append(keys, image.FileKey)
+
+		if i < len(captions) {
+			image.Keterangan = strings.TrimSpace(captions[i])
+		}
+		images = append(images, image)
+	}
+
+	if err := s.itemImageRepo.Create(ctx, itemID, images, false); err != nil {
+		s.deleteImageFiles(ctx, keys...)
+		return nil, galleryCreateError(err)
+	}
+
+	return s.gallery(ctx, itemID)
+}
+
+// ReorderImages mengubah urutan galeri barang
+func (s *itemService) ReorderImages(ctx context.Context, itemID, userID uint, imageIDs []uint) ([]domain.ItemImageResponse, error) {
+	if _, err := s.findEditableItem(ctx, itemID, userID, "Anda tidak memiliki izin untuk mengubah gambar barang ini"); err != nil {
+		return nil, err
+	}
+
+	images, err := s.itemImageRepo.FindByBarangID(ctx, itemID)
+	if err != nil {
+		return nil, errors.InternalError("Gagal mengambil gambar barang", err)
+	}
+
+	// Urutan baru harus menyebut setiap gambar barang tepat satu kali
+	remaining := make(map[uint]bool, len(images))
+	for _, image := range images {
+		remaining[image.ID] = true
+	}
+	if len(imageIDs) != len(images) {
+		return nil, errors.ValidationError("image_ids harus berisi semua gambar barang tepat satu kali", nil)
+	}
+	for _, id := range imageIDs {
+		if !remaining[id] {
+			return nil, errors.ValidationError("image_ids harus berisi semua gambar barang tepat satu kali", nil).
+				WithMetadata("imageID", id)
+		}
+		delete(remaining, id)
+	}
+
+	if err := s.itemImageRepo.Reorder(ctx, itemID, imageIDs); err != nil {
+		return nil, errors.InternalError("Gagal mengubah urutan gambar barang", err)
+	}
+
+	return s.gallery(ctx, itemID)
+}
+
+// UpdateImage mengubah keterangan gambar atau menjadikannya sampul
+func (s *itemService) UpdateImage(ctx context.Context, itemID, imageID, userID uint, req domain.UpdateItemImageRequest) ([]domain.ItemImageResponse, error) {
+	if _, err := s.findEditableItem(ctx, itemID, userID, "Anda tidak memiliki izin untuk mengubah gambar barang ini"); err != nil {
+		return nil, err
+	}
+
+	if req.Keterangan == nil && req.Sampul == nil {
+		return nil, errors.ValidationError("Isi keterangan atau sampul yang ingin diubah", nil)
+	}
+
+	image, err := s.findImage(ctx, itemID, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Keterangan != nil {
+		keterangan := strings.TrimSpace(*req.Keterangan)
+		if err := validateImageCaption(keterangan); err != nil {
+			return nil, err
+		}
+		if err := s.itemImageRepo.UpdateKeterangan(ctx, image.ID, keterangan); err != nil {
+			return nil, errors.InternalError("Gagal mengubah keterangan gambar", err)
+		}
+	}
+
+	if req.Sampul != nil {
+		switch {
+		case *req.Sampul && !image.Sampul:
+			if err := s.itemImageRepo.SetCover(ctx, itemID, image.ID); err != nil {
+				return nil, errors.InternalError("Gagal mengubah sampul barang", err)
+			}
+		case !*req.Sampul && image.Sampul:
+			// Galeri yang tidak kosong selalu memiliki sampul
+			return nil, errors.ValidationError("Pilih gambar lain sebagai sampul untuk mengganti sampul barang", nil)
 		}
-		return "", errors.InternalError("Gagal mendapatkan data barang", err)
 	}
 
-	// Cek apakah pengguna adalah pemilik barang
-	if existingItem.PenjualID != userID {
-		return "", errors.ForbiddenError(
-			"Anda tidak memiliki izin untuk mengupload gambar barang ini", 
+	return s.gallery(ctx, itemID)
+}
+
+// DeleteImage menghapus gambar dari galeri barang dan storage
+func (s *itemService) DeleteImage(ctx context.Context, itemID, imageID, userID uint) ([]domain.ItemImageResponse, error) {
+	if _, err := s.findEditableItem(ctx, itemID, userID, "Anda tidak memiliki izin untuk menghapus gambar barang ini"); err != nil {
+		return nil, err
+	}
+
+	image, err := s.findImage(ctx, itemID, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.itemImageRepo.Delete(ctx, itemID, image.ID); err != nil {
+		return nil, errors.InternalError("Gagal menghapus gambar barang", err)
+	}
+
+	// Gambar lama yang disimpan sebelum galeri ada tidak memiliki key file
+	if image.FileKey != "" {
+		s.deleteImageFiles(ctx, image.FileKey)
+	}
+
+	return s.gallery(ctx, itemID)
+}
+
+// findEditableItem mencari barang dan memastikan pengguna boleh mengelola gambarnya
+func (s *itemService) findEditableItem(ctx context.Context, itemID, userID uint, forbiddenMessage string) (*domain.Item, error) {
+	item, err := s.itemRepo.FindByID(ctx, itemID)
+	if err != nil {
+		return nil, errors.NotFoundError(fmt.Sprintf("Barang dengan ID %d tidak ditemukan", itemID), err)
+	}
+
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.ItemUploadImage, item) {
+		return nil, errors.ForbiddenError(
+			forbiddenMessage,
 			stdErrors.New("unauthorized access attempt"),
 		).WithMetadata("itemID", itemID).WithMetadata("userID", userID)
 	}
 
-	// Dapatkan file dari form
-	file, err := ctx.FormFile("gambar")
+	return item, nil
+}
+
+// findImage mencari gambar milik barang
+func (s *itemService) findImage(ctx context.Context, itemID, imageID uint) (*domain.ItemImage, error) {
+	image, err := s.itemImageRepo.FindByID(ctx, itemID, imageID)
 	if err != nil {
-		return "", errors.ValidationError("Gagal mendapatkan file gambar", err)
+		return nil, errors.InternalError("Gagal mengambil gambar barang", err)
+	}
+	if image == nil {
+		return nil, errors.NotFoundError("Gambar tidak ditemukan", nil).WithMetadata("imageID", imageID)
+	}
+	return image, nil
+}
+
+// checkGalleryCapacity memastikan galeri barang masih dapat menampung sejumlah gambar baru
+// sebelum file diupload. Batas galeri tetap diperiksa ulang saat gambar disimpan,
+// karena upload lain ke barang yang sama dapat selesai lebih dulu.
+func (s *itemService) checkGalleryCapacity(ctx context.Context, itemID uint, added int) error {
+	count, err := s.itemImageRepo.CountByBarangID(ctx, itemID)
+	if err != nil {
+		return errors.InternalError("Gagal menghitung gambar barang", err)
 	}
+	if int(count)+added > domain.MaxItemImages {
+		return errors.ValidationError(
+			fmt.Sprintf("Galeri barang maksimal berisi %d gambar (saat ini %d)", domain.MaxItemImages, count),
+			nil,
+		)
+	}
+	return nil
+}
 
+// galleryCreateError mengubah error penyimpanan gambar ke galeri menjadi error service
+func galleryCreateError(err error) error {
+	if stdErrors.Is(err, repository.ErrGalleryFull) {
+		return errors.ValidationError(
+			fmt.Sprintf("Galeri barang maksimal berisi %d gambar", domain.MaxItemImages),
+			err,
+		)
+	}
+	return errors.InternalError("Gagal menyimpan gambar barang", err)
+}
+
+// readImageFile membaca file gambar yang diupload dan memeriksa ukuran, tipe dan resolusinya.
+// Tipe file ditentukan dari isinya, bukan dari nama file atau header Content-Type.
+func (s *itemService) readImageFile(file *multipart.FileHeader) ([]byte, error) {
 	// Cek ukuran file
 	if file.Size > s.config.Upload.MaxSize {
-		return "", errors.ValidationError(
+		return nil, errors.ValidationError(
 			fmt.Sprintf("Ukuran file terlalu besar (maksimal %d bytes)", s.config.Upload.MaxSize),
 			stdErrors.New("file size exceeds maximum allowed"),
-		)
+		).WithMetadata("fileName", file.Filename)
 	}
 
 	// Buka file untuk dibaca
 	src, err := file.Open()
 	if err != nil {
-		return "", errors.InternalError("Gagal membuka file", err)
+		return nil, errors.InternalError("Gagal membuka file", err)
 	}
 	defer src.Close()
 
 	// Baca file ke dalam byte array
 	fileBytes, err := io.ReadAll(src)
 	if err != nil {
-		return "", errors.InternalError("Gagal membaca file", err)
+		return nil, errors.InternalError("Gagal membaca file", err)
 	}
 
-	// Buat nama file unik
-	ext := filepath.Ext(file.Filename)
-	fileName := fmt.Sprintf("%d_%d%s", itemID, time.Now().Unix(), ext)
-	fileID := fmt.Sprintf("item_%d_%d", itemID, time.Now().Unix())
-
-	// Buat endpoint Appwrite
-	appwriteEndpoint := s.config.Appwrite.Endpoint
-	projectID := s.config.Appwrite.ProjectID
-	bucketID := s.config.Appwrite.BucketID
-	apiKey := s.config.Appwrite.APIKey
-	
-	// Tampilkan log untuk debug
-	fmt.Printf("Appwrite Config:\n")
-	fmt.Printf("  Endpoint: %s\n", appwriteEndpoint)
-	fmt.Printf("  Project ID: %s\n", projectID)
-	fmt.Printf("  Bucket ID: %s\n", bucketID)
-	fmt.Printf("  File ID: %s\n", fileID)
-	
-	// Cek konfigurasi
-	if projectID == "" {
-		return "", fmt.Errorf("project ID tidak boleh kosong")
+	contentType := imaging.DetectContentType(fileBytes)
+	if !s.config.Upload.IsAllowedType(contentType) {
+		return nil, errors.ValidationError(
+			fmt.Sprintf("Tipe file tidak didukung, gunakan salah satu dari: %s", strings.Join(s.config.Upload.AllowedTypes, ", ")),
+			nil,
+		).WithMetadata("fileName", file.Filename).WithMetadata("contentType", contentType)
 	}
-	if bucketID == "" {
-		return "", fmt.Errorf("bucket ID tidak boleh kosong")
+	if err := imaging.Validate(fileBytes); err != nil {
+		return nil, errors.ValidationError("File bukan gambar yang valid", err).WithMetadata("fileName", file.Filename)
 	}
-	if apiKey == "" {
-		return "", fmt.Errorf("API key tidak boleh kosong")
-	}
-	
-	// Simpan file ke sistem lokal sementara (sebagai backup)
-	tempDir := "./temp"
-	if _, err := os.Stat(tempDir); os.IsNotExist(err) {
-		if err := os.MkdirAll(tempDir, 0755); err != nil {
-			return "", fmt.Errorf("gagal membuat direktori temp: %v", err)
-		}
-	}
-	
-	// Simpan file ke lokal sementara
-	tempFilePath := filepath.Join(tempDir, fileName)
-	if err := ctx.SaveUploadedFile(file, tempFilePath); err != nil {
-		return "", fmt.Errorf("gagal menyimpan file sementara: %v", err)
-	}
-	defer os.Remove(tempFilePath) // Hapus file sementara setelah selesai
-	
-	// Persiapkan URL upload Appwrite
-	uploadURL := fmt.Sprintf("%s/storage/buckets/%s/files", 
-		appwriteEndpoint, 
-		bucketID)
-		
-	// Buat body untuk request
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	
-	// Tambahkan fileId field
-	if err := writer.WriteField("fileId", fileID); err != nil {
-		return "", fmt.Errorf("gagal membuat field fileId: %v", err)
-	}
-	
-	// Tambahkan file
-	part, err := writer.CreateFormFile("file", fileName)
-	if err != nil {
-		return "", fmt.Errorf("gagal membuat form file: %v", err)
-	}
-	
-	// Copy file bytes ke form
-	if _, err := io.Copy(part, bytes.NewReader(fileBytes)); err != nil {
-		return "", fmt.Errorf("gagal menulis file ke form: %v", err)
-	}
-	
-	// Tutup writer multipart
-	if err := writer.Close(); err != nil {
-		return "", fmt.Errorf("gagal menutup writer: %v", err)
-	}
-	
-	// Buat request
-	req, err := http.NewRequest("POST", uploadURL, body)
+
+	return fileBytes, nil
+}
+
+// storeImage membuat varian gambar barang dan menyimpannya ke storage. Gambar di-encode ulang
+// sehingga metadata EXIF, termasuk lokasi GPS dari foto ponsel, tidak ikut tersimpan.
+func (s *itemService) storeImage(ctx context.Context, itemID uint, index int, data []byte) (domain.ItemImage, error) {
+	img, format, err := imaging.Decode(data)
 	if err != nil {
-		return "", fmt.Errorf("gagal membuat request: %v", err)
+		return domain.ItemImage{}, errors.ValidationError("File bukan gambar yang valid", err)
 	}
-	
-	// Set headers
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Add("X-Appwrite-Project", projectID)
-	req.Header.Add("X-Appwrite-Key", apiKey)
-	
-	// Print semua header untuk debug
-	fmt.Printf("Request Headers:\n")
-	for name, values := range req.Header {
-		for _, value := range values {
-			fmt.Printf("  %s: %s\n", name, value)
+
+	// Encode semua varian lebih dulu karena ekstensi key ditentukan oleh format hasil encode
+	encoded := make([][]byte, len(domain.ItemImageVariants))
+	var ext, contentType string
+	for i, variant := range domain.ItemImageVariants {
+		encoded[i], ext, contentType, err = imaging.Encode(imaging.Fit(img, variant.MaxSide), format)
+		if err != nil {
+			return domain.ItemImage{}, errors.InternalError("Gagal memproses gambar", err)
 		}
 	}
-	
-	// Kirim request
-	client := &http.Client{
-		Timeout: 60 * time.Second,
+
+	// Buat key file unik untuk ukuran penuh, key varian lain diturunkan darinya
+	key := domain.NewItemImageKey(itemID, time.Now(), index, ext)
+	for i, variant := range domain.ItemImageVariants {
+		if err := s.store.Put(ctx, variant.Key(key), encoded[i], contentType); err != nil {
+			s.deleteImageFiles(ctx, key)
+			return domain.ItemImage{}, errors.InternalError("Gagal mengupload gambar", err)
+		}
 	}
-	
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("gagal mengirim request: %v", err)
+
+	return domain.ItemImage{
+		FileKey:   key,
+		URL:       s.store.URL(domain.ItemImageFull.Key(key)),
+		ThumbURL:  s.store.URL(domain.ItemImageThumb.Key(key)),
+		MediumURL: s.store.URL(domain.ItemImageMedium.Key(key)),
+	}, nil
+}
+
+// deleteImageFiles menghapus file gambar barang beserta semua variannya dari storage.
+// Kegagalan hanya dicatat karena tidak memengaruhi data barang.
+func (s *itemService) deleteImageFiles(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		for _, variant := range domain.ItemImageVariants {
+			if err := s.store.Delete(ctx, variant.Key(key)); err != nil {
+				log.Printf("Gagal menghapus file gambar barang %s: %v", variant.Key(key), err)
+			}
+		}
 	}
-	defer resp.Body.Close()
-	
-	// Baca response
-	respBody, err := io.ReadAll(resp.Body)
+}
+
+// gallery mengambil galeri barang dalam format respons
+func (s *itemService) gallery(ctx context.Context, itemID uint) ([]domain.ItemImageResponse, error) {
+	images, err := s.itemImageRepo.FindByBarangID(ctx, itemID)
 	if err != nil {
-		return "", fmt.Errorf("gagal membaca response: %v", err)
+		return nil, errors.InternalError("Gagal mengambil gambar barang", err)
 	}
-	
-	// Print response untuk debug
-	fmt.Printf("Response Status: %d\n", resp.StatusCode)
-	fmt.Printf("Response Body: %s\n", string(respBody))
-	
-	// Cek response status
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return "", fmt.Errorf("gagal upload file ke Appwrite: HTTP %d - %s", resp.StatusCode, string(respBody))
+	return domain.NewItemImageResponses(images), nil
+}
+
+// validateImageCaption memeriksa panjang keterangan gambar
+func validateImageCaption(caption string) error {
+	if utf8.RuneCountInString(strings.TrimSpace(caption)) > maxImageCaptionLength {
+		return errors.ValidationError(
+			fmt.Sprintf("Keterangan gambar maksimal %d karakter", maxImageCaptionLength),
+			nil,
+		)
 	}
-	
-	// Buat URL view untuk gambar - menggunakan format yang dapat diakses publik
-	// Gunakan /view endpoint
-	viewURL := fmt.Sprintf("%s/storage/buckets/%s/files/%s/view?project=%s", 
-		appwriteEndpoint,
-		bucketID,
-		fileID,
-		projectID)
-		
-	fmt.Printf("View URL: %s\n", viewURL)
-	
-	// Simpan URL gambar di database
-	existingItem.Gambar = viewURL
-	if err := s.itemRepo.Update(ctx, existingItem); err != nil {
-		return "", err
+	return nil
+}
+
+// canSeeHidden memeriksa apakah principal pada context boleh melihat konten milik ownerID
+// yang disembunyikan karena laporan, yaitu pemiliknya sendiri atau moderator
+func canSeeHidden(ctx context.Context, ownerID uint) bool {
+	principal, ok := policy.FromContext(ctx)
+	if !ok {
+		return false
 	}
-	
-	// Return format fileID|fileName|viewURL untuk penggunaan di handler
-	gambarInfo := fmt.Sprintf("%s|%s|%s", fileID, fileName, viewURL)
-	return gambarInfo, nil
-}
\ No newline at end of file
+	return principal.UserID == ownerID || policy.Can(principal, policy.ReportModerate, nil)
+}
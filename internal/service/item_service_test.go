@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
+)
+
+func TestItemGetByIDHidesHiddenContent(t *testing.T) {
+	const (
+		sellerID uint = 2
+		otherID  uint = 3
+	)
+	hiddenAt := time.Now().Add(-time.Hour)
+	visible := domain.Item{ID: 1, PenjualID: sellerID, Penjual: domain.User{ID: sellerID}}
+	hiddenItem := domain.Item{ID: 2, PenjualID: sellerID, HiddenAt: &hiddenAt, Penjual: domain.User{ID: sellerID}}
+	hiddenSeller := domain.Item{ID: 3, PenjualID: sellerID, Penjual: domain.User{ID: sellerID, HiddenAt: &hiddenAt}}
+
+	seller := &policy.Principal{UserID: sellerID, Role: domain.RoleUser}
+	other := &policy.Principal{UserID: otherID, Role: domain.RoleUser}
+	moderator := &policy.Principal{UserID: otherID, Role: domain.RoleModerator, MFA: true}
+
+	tests := []struct {
+		name      string
+		item      domain.Item
+		principal *policy.Principal
+		wantFound bool
+	}{
+		{"barang tampil tanpa login", visible, nil, true},
+		{"barang disembunyikan tanpa login", hiddenItem, nil, false},
+		{"barang disembunyikan untuk pengguna lain", hiddenItem, other, false},
+		{"barang disembunyikan untuk penjual", hiddenItem, seller, true},
+		{"penjual disembunyikan tanpa login", hiddenSeller, nil, false},
+		{"penjual disembunyikan untuk pengguna lain", hiddenSeller, other, false},
+		{"penjual disembunyikan untuk penjual", hiddenSeller, seller, true},
+		{"penjual disembunyikan untuk moderator", hiddenSeller, moderator, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := tt.item
+			items := &fakeItemRepository{items: map[uint]*domain.Item{item.ID: &item}}
+			service := NewItemService(items, nil, nil, nil, nil)
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = policy.WithPrincipal(ctx, *tt.principal)
+			}
+			response, err := service.GetByID(ctx, item.ID)
+			if tt.wantFound {
+				if err != nil || response == nil || response.ID != item.ID {
+					t.Errorf("GetByID = %+v, %v, want barang %d", response, err, item.ID)
+				}
+				return
+			}
+			var appErr *errors.StandardError
+			if !stdErrors.As(err, &appErr) || appErr.Code != http.StatusNotFound {
+				t.Errorf("GetByID error = %v, want not found", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+)
+
+// NotificationService adalah interface untuk layanan notifikasi dalam aplikasi
+type NotificationService interface {
+	// NotifyNewListing memberi tahu pengikut penjual bahwa penjual memasang barang baru
+	NotifyNewListing(ctx context.Context, item *domain.Item) error
+	// GetMine mendapatkan notifikasi pengguna dengan paginasi
+	GetMine(ctx context.Context, userID uint, unreadOnly bool, page, limit int) ([]domain.Notification, int, int64, error)
+	// CountUnread menghitung notifikasi pengguna yang belum dibaca
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	// MarkAsRead menandai notifikasi sebagai sudah dibaca
+	MarkAsRead(ctx context.Context, id, userID uint) error
+	// MarkAllAsRead menandai semua notifikasi pengguna sebagai sudah dibaca
+	MarkAllAsRead(ctx context.Context, userID uint) (int64, error)
+}
+
+// notificationService adalah implementasi dari NotificationService
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewNotificationService membuat instance baru dari NotificationService
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// NotifyNewListing memberi tahu pengikut penjual bahwa penjual memasang barang baru.
+// Relasi Penjual pada item harus sudah dimuat.
+func (s *notificationService) NotifyNewListing(ctx context.Context, item *domain.Item) error {
+	barangID := item.ID
+	notification := &domain.Notification{
+		Jenis:    domain.NotificationNewListing,
+		Judul:    fmt.Sprintf("%s memasang barang baru", item.Penjual.Nama),
+		Pesan:    fmt.Sprintf("%s - Rp%.0f", item.NamaBarang, item.Harga),
+		BarangID: &barangID,
+	}
+
+	if _, err := s.notificationRepo.CreateForFollowers(ctx, item.PenjualID, notification); err != nil {
+		return fmt.Errorf("gagal membuat notifikasi barang baru %d: %w", item.ID, err)
+	}
+	return nil
+}
+
+// GetMine mendapatkan notifikasi pengguna dengan paginasi
+func (s *notificationService) GetMine(ctx context.Context, userID uint, unreadOnly bool, page, limit int) ([]domain.Notification, int, int64, error) {
+	// Validasi input paginasi
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	notifications, total, err := s.notificationRepo.FindByPenggunaID(ctx, userID, unreadOnly, page, limit)
+	if err != nil {
+		return nil, 0, 0, errors.InternalError("Gagal mengambil notifikasi", err)
+	}
+	if notifications == nil {
+		notifications = []domain.Notification{}
+	}
+
+	// Hitung total halaman
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return notifications, totalPages, total, nil
+}
+
+// CountUnread menghitung notifikasi pengguna yang belum dibaca
+func (s *notificationService) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	count, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, errors.InternalError("Gagal menghitung notifikasi", err)
+	}
+	return count, nil
+}
+
+// MarkAsRead menandai notifikasi sebagai sudah dibaca
+func (s *notificationService) MarkAsRead(ctx context.Context, id, userID uint) error {
+	updated, err := s.notificationRepo.MarkAsRead(ctx, id, userID)
+	if err != nil {
+		return errors.InternalError("Gagal menandai notifikasi", err)
+	}
+	// Notifikasi milik pengguna lain diperlakukan sama seperti notifikasi yang tidak ada
+	if !updated {
+		return errors.NotFoundError("Notifikasi tidak ditemukan", nil)
+	}
+	return nil
+}
+
+// MarkAllAsRead menandai semua notifikasi pengguna sebagai sudah dibaca
+func (s *notificationService) MarkAllAsRead(ctx context.Context, userID uint) (int64, error) {
+	count, err := s.notificationRepo.MarkAllAsRead(ctx, userID)
+	if err != nil {
+		return 0, errors.InternalError("Gagal menandai notifikasi", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/oidc"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+// maxOIDCUserNameLength adalah panjang maksimum nama pengguna baru dari penyedia identitas (karakter)
+const maxOIDCUserNameLength = 100
+
+// OIDCService adalah interface untuk layanan login dengan penyedia identitas OpenID Connect
+type OIDCService interface {
+	Providers() []string
+	AuthorizationURL(ctx context.Context, provider string) (string, error)
+	Callback(ctx context.Context, provider, code, state string, client domain.ClientInfo) (*domain.LoginResult, error)
+}
+
+// oidcService adalah implementasi dari OIDCService
+type oidcService struct {
+	providers    map[string]*oidc.Provider
+	stateRepo    repository.OIDCStateRepository
+	identityRepo repository.OIDCIdentityRepository
+	userRepo     repository.UserRepository
+	authService  AuthService
+	config       *config.Config
+}
+
+// NewOIDCService membuat instance baru dari OIDCService
+func NewOIDCService(
+	providers map[string]*oidc.Provider,
+	stateRepo repository.OIDCStateRepository,
+	identityRepo repository.OIDCIdentityRepository,
+	userRepo repository.UserRepository,
+	authService AuthService,
+	config *config.Config,
+) OIDCService {
+	return &oidcService{
+		providers:    providers,
+		stateRepo:    stateRepo,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		config:       config,
+	}
+}
+
+// Providers mengembalikan nama penyedia identitas yang dikonfigurasi
+func (s *oidcService) Providers() []string {
+	names := make([]string, 0, len(s.config.OIDC.Providers))
+	for _, provider := range s.config.OIDC.Providers {
+		names = append(names, provider.Name)
+	}
+	return names
+}
+
+// AuthorizationURL memulai login OIDC: state, nonce dan code_verifier PKCE disimpan,
+// lalu URL halaman login penyedia dikembalikan
+func (s *oidcService) AuthorizationURL(ctx context.Context, providerName string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", oidc.ErrUnknownProvider
+	}
+
+	state, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	codeVerifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if err := s.stateRepo.Create(ctx, &domain.OIDCState{
+		StateHash:    utils.HashToken(state),
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    now.Add(s.config.OIDC.StateExpiry),
+	}); err != nil {
+		return "", err
+	}
+
+	// Bersihkan state dari login yang tidak pernah diselesaikan
+	if err := s.stateRepo.DeleteExpired(ctx, now); err != nil {
+		log.Printf("Gagal membersihkan state OIDC kedaluwarsa: %v", err)
+	}
+
+	return authURL, nil
+}
+
+// Callback menyelesaikan login OIDC: authorization code ditukar dengan ID token,
+// ID token diverifikasi, lalu identitas dihubungkan dengan pengguna Jubel
+func (s *oidcService) Callback(ctx context.Context, providerName, code, state string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, oidc.ErrUnknownProvider
+	}
+
+	// State hanya bisa dipakai sekali dan harus berasal dari penyedia yang sama
+	storedState, err := s.stateRepo.Consume(ctx, utils.HashToken(state))
+	if err != nil || storedState.Provider != providerName || time.Now().After(storedState.ExpiresAt) {
+		return nil, errors.New("sesi login tidak valid atau sudah kedaluwarsa, silakan ulangi login")
+	}
+
+	rawIDToken, err := provider.Exchange(ctx, code, storedState.CodeVerifier)
+	if err != nil {
+		log.Printf("Login OIDC %s gagal: %v", providerName, err)
+		return nil, errors.New("login dengan penyedia identitas gagal")
+	}
+
+	claims, err := provider.VerifyIDToken(ctx, rawIDToken, storedState.Nonce)
+	if err != nil {
+		log.Printf("Login OIDC %s gagal: %v", providerName, err)
+		return nil, errors.New("login dengan penyedia identitas gagal")
+	}
+
+	user, err := s.resolveUser(ctx, providerName, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.LoginExternal(ctx, user, client)
+}
+
+// resolveUser mencari pengguna untuk identitas OIDC. Identitas yang belum pernah dipakai
+// dihubungkan ke pengguna dengan email yang sama, atau dibuatkan akun baru.
+func (s *oidcService) resolveUser(ctx context.Context, providerName string, claims *oidc.IDTokenClaims) (*domain.User, error) {
+	now := time.Now()
+
+	identity, err := s.identityRepo.FindBySubject(ctx, providerName, claims.Subject)
+	if err == nil {
+		user, err := s.userRepo.FindByID(ctx, identity.PenggunaID)
+		if err != nil {
+			return nil, errors.New("akun tidak ditemukan")
+		}
+		if err := s.identityRepo.TouchLogin(ctx, identity.ID, now); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	// Penghubungan berdasarkan email hanya aman jika penyedia menjamin email tersebut milik pengguna
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, errors.New("email akun penyedia identitas belum terverifikasi")
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, claims.Email)
+	if err == nil {
+		// Akun dengan email yang belum diverifikasi bisa saja didaftarkan orang lain,
+		// sehingga tidak boleh dihubungkan otomatis
+		if !user.IsEmailVerified() {
+			return nil, errors.New("akun dengan email ini belum diverifikasi, verifikasi email atau reset password terlebih dahulu")
+		}
+	} else {
+		user, err = s.createUser(ctx, claims, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.identityRepo.Create(ctx, &domain.OIDCIdentity{
+		PenggunaID:  user.ID,
+		Provider:    providerName,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: &now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createUser membuat akun baru untuk identitas OIDC.
+// Akun ini tidak memiliki password yang bisa dipakai sampai pengguna melakukan reset password.
+func (s *oidcService) createUser(ctx context.Context, claims *oidc.IDTokenClaims, now time.Time) (*domain.User, error) {
+	password, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+	// Nama dipotong per karakter agar karakter multibyte tidak terpotong di tengah
+	if utf8.RuneCountInString(name) > maxOIDCUserNameLength {
+		name = string([]rune(name)[:maxOIDCUserNameLength])
+	}
+
+	user := &domain.User{
+		Nama:            name,
+		Email:           claims.Email,
+		Password:        password,
+		Role:            domain.RoleUser,
+		EmailVerifiedAt: &now,
+	}
+	if err := user.HashPassword(); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,435 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/oidc"
+	"github.com/mfuadfakhruzzaki/jubel/internal/oidc/oidctest"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+	"gorm.io/gorm"
+)
+
+// fakeOIDCStateRepository menyimpan state OIDC di memori
+type fakeOIDCStateRepository struct {
+	mu     sync.Mutex
+	states map[string]domain.OIDCState
+}
+
+func (r *fakeOIDCStateRepository) Create(ctx context.Context, state *domain.OIDCState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[state.StateHash] = *state
+	return nil
+}
+
+func (r *fakeOIDCStateRepository) Consume(ctx context.Context, stateHash string) (*domain.OIDCState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, ok := r.states[stateHash]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	delete(r.states, stateHash)
+	return &state, nil
+}
+
+func (r *fakeOIDCStateRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for hash, state := range r.states {
+		if state.ExpiresAt.Before(now) {
+			delete(r.states, hash)
+		}
+	}
+	return nil
+}
+
+// fakeOIDCIdentityRepository menyimpan identitas OIDC di memori
+type fakeOIDCIdentityRepository struct {
+	identities []domain.OIDCIdentity
+	touched    []uint
+}
+
+func (r *fakeOIDCIdentityRepository) Create(ctx context.Context, identity *domain.OIDCIdentity) error {
+	identity.ID = uint(len(r.identities) + 1)
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func (r *fakeOIDCIdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (*domain.OIDCIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return &identity, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeOIDCIdentityRepository) TouchLogin(ctx context.Context, id uint, now time.Time) error {
+	r.touched = append(r.touched, id)
+	return nil
+}
+
+// fakeUserRepository menyimpan pengguna di memori. Method yang tidak dipakai OIDCService
+// diteruskan ke interface nil sehingga panic jika terpanggil.
+type fakeUserRepository struct {
+	repository.UserRepository
+	users []domain.User
+}
+
+func (r *fakeUserRepository) Create(ctx context.Context, user *domain.User) error {
+	user.ID = uint(len(r.users) + 1)
+	r.users = append(r.users, *user)
+	return nil
+}
+
+func (r *fakeUserRepository) FindByID(ctx context.Context, id uint) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.ID == id {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeAuthService mencatat pengguna yang login melalui LoginExternal
+type fakeAuthService struct {
+	AuthService
+	loggedIn []uint
+}
+
+func (s *fakeAuthService) LoginExternal(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.LoginResult, error) {
+	s.loggedIn = append(s.loggedIn, user.ID)
+	response := user.ToResponse()
+	return &domain.LoginResult{User: &response}, nil
+}
+
+// oidcTestEnv berisi OIDCService yang terhubung ke dua penyedia identitas tiruan
+type oidcTestEnv struct {
+	service    OIDCService
+	idps       map[string]*oidctest.Server
+	states     *fakeOIDCStateRepository
+	identities *fakeOIDCIdentityRepository
+	users      *fakeUserRepository
+	auth       *fakeAuthService
+}
+
+func newOIDCTestEnv(t *testing.T) *oidcTestEnv {
+	t.Helper()
+
+	env := &oidcTestEnv{
+		idps:       make(map[string]*oidctest.Server),
+		states:     &fakeOIDCStateRepository{states: make(map[string]domain.OIDCState)},
+		identities: &fakeOIDCIdentityRepository{},
+		users:      &fakeUserRepository{},
+		auth:       &fakeAuthService{},
+	}
+	cfg := &config.Config{OIDC: config.OIDCConfig{StateExpiry: 10 * time.Minute}}
+	providers := make(map[string]*oidc.Provider)
+	for _, name := range []string{"google", "gitlab"} {
+		server := oidctest.NewServer(t, "jubel-"+name)
+		providerConfig := config.OIDCProviderConfig{
+			Name:        name,
+			Issuer:      server.Issuer(),
+			ClientID:    "jubel-" + name,
+			RedirectURL: "http://localhost/api/v1/auth/oidc/" + name + "/callback",
+			Scopes:      []string{"openid", "email"},
+		}
+		cfg.OIDC.Providers = append(cfg.OIDC.Providers, providerConfig)
+		providers[name] = oidc.NewProvider(providerConfig, server.Client())
+		env.idps[name] = server
+	}
+
+	env.service = NewOIDCService(providers, env.states, env.identities, env.users, env.auth, cfg)
+	return env
+}
+
+// login menjalankan alur login lengkap di penyedia dan mengembalikan hasil callback
+func (env *oidcTestEnv) login(t *testing.T, provider string, identity oidctest.Identity) (*domain.LoginResult, error) {
+	t.Helper()
+	ctx := context.Background()
+	authURL, err := env.service.AuthorizationURL(ctx, provider)
+	if err != nil {
+		t.Fatalf("AuthorizationURL: %v", err)
+	}
+	code, state := env.idps[provider].Authorize(t, authURL, identity)
+	return env.service.Callback(ctx, provider, code, state, domain.ClientInfo{IPAddress: "127.0.0.1"})
+}
+
+func TestOIDCCallbackCreatesUserAndReusesIdentity(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	identity := oidctest.Identity{Subject: "sub-1", Email: "Baru@Example.com", EmailVerified: true, Name: "Pengguna Baru"}
+
+	result, err := env.login(t, "google", identity)
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if len(env.users.users) != 1 {
+		t.Fatalf("jumlah pengguna = %d, want 1", len(env.users.users))
+	}
+	user := env.users.users[0]
+	if user.Email != "baru@example.com" || user.Nama != "Pengguna Baru" || !user.IsEmailVerified() {
+		t.Errorf("pengguna baru = %+v", user)
+	}
+	if user.Password == "" || user.CheckPassword("") {
+		t.Error("pengguna baru seharusnya memiliki password acak yang di-hash")
+	}
+	if result.User == nil || result.User.ID != user.ID {
+		t.Errorf("hasil login = %+v, want pengguna %d", result, user.ID)
+	}
+	if len(env.identities.identities) != 1 || env.identities.identities[0].PenggunaID != user.ID {
+		t.Fatalf("identitas = %+v, want satu identitas untuk pengguna %d", env.identities.identities, user.ID)
+	}
+
+	// Login berikutnya memakai identitas yang sudah terhubung, meskipun email di penyedia berubah
+	identity.Email = "ganti@example.com"
+	identity.EmailVerified = false
+	if _, err := env.login(t, "google", identity); err != nil {
+		t.Fatalf("Callback kedua: %v", err)
+	}
+	if len(env.users.users) != 1 || len(env.identities.identities) != 1 {
+		t.Errorf("login kedua membuat pengguna atau identitas baru: %d pengguna, %d identitas", len(env.users.users), len(env.identities.identities))
+	}
+	if len(env.identities.touched) != 1 || env.identities.touched[0] != env.identities.identities[0].ID {
+		t.Errorf("TouchLogin = %v, want identitas %d", env.identities.touched, env.identities.identities[0].ID)
+	}
+	if len(env.auth.loggedIn) != 2 || env.auth.loggedIn[1] != user.ID {
+		t.Errorf("LoginExternal = %v, want pengguna %d dua kali", env.auth.loggedIn, user.ID)
+	}
+
+	// Subject yang sama di penyedia lain adalah identitas yang berbeda
+	if _, err := env.login(t, "gitlab", oidctest.Identity{Subject: "sub-1", Email: "lain@example.com", EmailVerified: true}); err != nil {
+		t.Fatalf("Callback gitlab: %v", err)
+	}
+	if len(env.users.users) != 2 {
+		t.Errorf("jumlah pengguna = %d, want 2", len(env.users.users))
+	}
+}
+
+func TestOIDCCallbackLinksByEmail(t *testing.T) {
+	verifiedAt := time.Now().Add(-24 * time.Hour)
+	tests := []struct {
+		name          string
+		localVerified bool
+		idpVerified   bool
+		wantLinked    bool
+	}{
+		{"email lokal dan penyedia terverifikasi", true, true, true},
+		{"email lokal belum diverifikasi", false, true, false},
+		{"email penyedia belum diverifikasi", true, false, false},
+		{"keduanya belum diverifikasi", false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newOIDCTestEnv(t)
+			local := &domain.User{Nama: "Budi", Email: "budi@example.com", Role: domain.RoleUser}
+			if tt.localVerified {
+				local.EmailVerifiedAt = &verifiedAt
+			}
+			if err := env.users.Create(context.Background(), local); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+
+			_, err := env.login(t, "google", oidctest.Identity{Subject: "sub-budi", Email: "BUDI@example.com", EmailVerified: tt.idpVerified})
+			if tt.wantLinked {
+				if err != nil {
+					t.Fatalf("Callback: %v", err)
+				}
+				if len(env.identities.identities) != 1 || env.identities.identities[0].PenggunaID != local.ID {
+					t.Errorf("identitas = %+v, want terhubung ke pengguna %d", env.identities.identities, local.ID)
+				}
+				if len(env.auth.loggedIn) != 1 || env.auth.loggedIn[0] != local.ID {
+					t.Errorf("LoginExternal = %v, want pengguna %d", env.auth.loggedIn, local.ID)
+				}
+			} else {
+				if err == nil {
+					t.Fatal("Callback seharusnya ditolak")
+				}
+				if len(env.identities.identities) != 0 || len(env.auth.loggedIn) != 0 {
+					t.Errorf("identitas %+v dan login %v seharusnya kosong", env.identities.identities, env.auth.loggedIn)
+				}
+			}
+			if len(env.users.users) != 1 {
+				t.Errorf("jumlah pengguna = %d, want 1", len(env.users.users))
+			}
+		})
+	}
+}
+
+func TestOIDCCallbackNewUserName(t *testing.T) {
+	tests := []struct {
+		name     string
+		claim    string
+		wantName string
+	}{
+		{"nama dari penyedia", "  Siti Aminah ", "Siti Aminah"},
+		{"tanpa nama memakai bagian lokal email", "", "baru"},
+		{"nama ASCII panjang", strings.Repeat("a", 150), strings.Repeat("a", 100)},
+		// Nama dipotong per karakter, bukan per byte
+		{"nama multibyte panjang", strings.Repeat("é", 150), strings.Repeat("é", 100)},
+		{"nama multibyte tepat batas", strings.Repeat("山", 100), strings.Repeat("山", 100)},
+		{"nama emoji panjang", strings.Repeat("😀", 101), strings.Repeat("😀", 100)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newOIDCTestEnv(t)
+			identity := oidctest.Identity{Subject: "sub-1", Email: "baru@example.com", EmailVerified: true, Name: tt.claim}
+			if _, err := env.login(t, "google", identity); err != nil {
+				t.Fatalf("Callback: %v", err)
+			}
+			if len(env.users.users) != 1 {
+				t.Fatalf("jumlah pengguna = %d, want 1", len(env.users.users))
+			}
+			got := env.users.users[0].Nama
+			if !utf8.ValidString(got) {
+				t.Errorf("nama %q bukan UTF-8 yang valid", got)
+			}
+			if got != tt.wantName {
+				t.Errorf("nama = %q (%d karakter), want %q (%d karakter)", got, utf8.RuneCountInString(got), tt.wantName, utf8.RuneCountInString(tt.wantName))
+			}
+		})
+	}
+}
+
+func TestOIDCCallbackRejectsUnverifiedEmailForNewUser(t *testing.T) {
+	env := newOIDCTestEnv(t)
+	for _, identity := range []oidctest.Identity{
+		{Subject: "sub-1", Email: "baru@example.com", EmailVerified: false},
+		{Subject: "sub-2", Email: "", EmailVerified: true},
+	} {
+		if _, err := env.login(t, "google", identity); err == nil {
+			t.Errorf("Callback %+v seharusnya ditolak", identity)
+		}
+	}
+	if len(env.users.users) != 0 || len(env.identities.identities) != 0 {
+		t.Errorf("tidak boleh ada pengguna (%d) atau identitas (%d) yang dibuat", len(env.users.users), len(env.identities.identities))
+	}
+}
+
+func TestOIDCCallbackState(t *testing.T) {
+	ctx := context.Background()
+	identity := oidctest.Identity{Subject: "sub-1", Email: "budi@example.com", EmailVerified: true}
+	client := domain.ClientInfo{}
+
+	t.Run("state tidak bisa dipakai ulang", func(t *testing.T) {
+		env := newOIDCTestEnv(t)
+		authURL, err := env.service.AuthorizationURL(ctx, "google")
+		if err != nil {
+			t.Fatalf("AuthorizationURL: %v", err)
+		}
+		code, state := env.idps["google"].Authorize(t, authURL, identity)
+		if _, err := env.service.Callback(ctx, "google", code, state, client); err != nil {
+			t.Fatalf("Callback: %v", err)
+		}
+
+		// Authorization code baru untuk state yang sama tetap ditolak sebelum menghubungi penyedia
+		replayCode, _ := env.idps["google"].Authorize(t, authURL, identity)
+		tokenRequests := env.idps["google"].TokenRequests()
+		if _, err := env.service.Callback(ctx, "google", replayCode, state, client); err == nil {
+			t.Error("Callback dengan state yang sudah dipakai seharusnya ditolak")
+		}
+		if got := env.idps["google"].TokenRequests(); got != tokenRequests {
+			t.Errorf("token endpoint dipanggil %d kali untuk state yang dipakai ulang", got-tokenRequests)
+		}
+	})
+
+	t.Run("state tidak dikenal", func(t *testing.T) {
+		env := newOIDCTestEnv(t)
+		authURL, _ := env.service.AuthorizationURL(ctx, "google")
+		code, _ := env.idps["google"].Authorize(t, authURL, identity)
+		if _, err := env.service.Callback(ctx, "google", code, "state-palsu", client); err == nil {
+			t.Error("Callback dengan state tidak dikenal seharusnya ditolak")
+		}
+	})
+
+	t.Run("state milik penyedia lain", func(t *testing.T) {
+		env := newOIDCTestEnv(t)
+		authURL, _ := env.service.AuthorizationURL(ctx, "google")
+		code, state := env.idps["google"].Authorize(t, authURL, identity)
+		if _, err := env.service.Callback(ctx, "gitlab", code, state, client); err == nil {
+			t.Error("Callback dengan state penyedia lain seharusnya ditolak")
+		}
+		// State sudah terpakai sehingga tidak bisa dicoba ulang di penyedia asalnya
+		if _, err := env.service.Callback(ctx, "google", code, state, client); err == nil {
+			t.Error("state yang sudah dikonsumsi seharusnya ditolak")
+		}
+	})
+
+	t.Run("state kedaluwarsa", func(t *testing.T) {
+		env := newOIDCTestEnv(t)
+		authURL, _ := env.service.AuthorizationURL(ctx, "google")
+		code, state := env.idps["google"].Authorize(t, authURL, identity)
+
+		hash := utils.HashToken(state)
+		stored := env.states.states[hash]
+		stored.ExpiresAt = time.Now().Add(-time.Second)
+		env.states.states[hash] = stored
+
+		if _, err := env.service.Callback(ctx, "google", code, state, client); err == nil {
+			t.Error("Callback dengan state kedaluwarsa seharusnya ditolak")
+		}
+		if got := env.idps["google"].TokenRequests(); got != 0 {
+			t.Errorf("token endpoint dipanggil %d kali untuk state kedaluwarsa", got)
+		}
+	})
+
+	t.Run("penyedia tidak dikenal", func(t *testing.T) {
+		env := newOIDCTestEnv(t)
+		if _, err := env.service.AuthorizationURL(ctx, "tidak-ada"); err != oidc.ErrUnknownProvider {
+			t.Errorf("AuthorizationURL error = %v, want ErrUnknownProvider", err)
+		}
+		if _, err := env.service.Callback(ctx, "tidak-ada", "kode", "state", client); err != oidc.ErrUnknownProvider {
+			t.Errorf("Callback error = %v, want ErrUnknownProvider", err)
+		}
+	})
+}
+
+func TestOIDCCallbackRejectsInvalidIDToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     func(c jwt.MapClaims)
+		signingKey func(t *testing.T) *rsa.PrivateKey
+	}{
+		{name: "tanda tangan kunci lain", signingKey: func(t *testing.T) *rsa.PrivateKey { return oidctest.NewKey(t) }},
+		{name: "nonce lain", claims: func(c jwt.MapClaims) { c["nonce"] = "nonce-lain" }},
+		{name: "issuer lain", claims: func(c jwt.MapClaims) { c["iss"] = "https://issuer-lain.example.com" }},
+		{name: "audience lain", claims: func(c jwt.MapClaims) { c["aud"] = "jubel-gitlab" }},
+		{name: "kedaluwarsa", claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newOIDCTestEnv(t)
+			idp := env.idps["google"]
+			idp.TokenClaims = tt.claims
+			if tt.signingKey != nil {
+				idp.SigningKey = tt.signingKey(t)
+			}
+
+			if _, err := env.login(t, "google", oidctest.Identity{Subject: "sub-1", Email: "budi@example.com", EmailVerified: true}); err == nil {
+				t.Fatal("Callback seharusnya ditolak")
+			}
+			if len(env.users.users) != 0 || len(env.identities.identities) != 0 || len(env.auth.loggedIn) != 0 {
+				t.Error("ID token tidak valid tidak boleh membuat pengguna, identitas atau sesi login")
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+)
+
+// maxReportTextLength adalah panjang maksimum deskripsi laporan dan catatan penyelesaian (karakter)
+const maxReportTextLength = 1000
+
+// ReportService adalah interface untuk layanan laporan pengguna
+type ReportService interface {
+	// Create membuat laporan terhadap barang, pesan chat atau pengguna
+	Create(ctx context.Context, pelaporID uint, req domain.CreateReportRequest) (*domain.ReportResponse, error)
+	// GetMine mendapatkan laporan yang dibuat pengguna
+	GetMine(ctx context.Context, pelaporID uint, page, limit int) ([]domain.ReportResponse, int, int64, error)
+	// GetQueue mendapatkan antrean laporan untuk moderator
+	GetQueue(ctx context.Context, filter domain.ReportFilter, page, limit int) ([]domain.AdminReportResponse, int, int64, error)
+	// GetByID mendapatkan detail laporan untuk moderator
+	GetByID(ctx context.Context, id uint) (*domain.AdminReportResponse, error)
+	// Assign menugaskan laporan ke moderator dan mengubah statusnya menjadi reviewing
+	Assign(ctx context.Context, id uint, req domain.AssignReportRequest) (*domain.AdminReportResponse, error)
+	// Resolve menyelesaikan laporan beserta laporan terbuka lain terhadap target yang sama
+	Resolve(ctx context.Context, id uint, req domain.ResolveReportRequest) (*domain.AdminReportResponse, error)
+}
+
+// reportService adalah implementasi dari ReportService
+type reportService struct {
+	reportRepo   repository.ReportRepository
+	itemRepo     repository.ItemRepository
+	chatRepo     repository.ChatRepository
+	userRepo     repository.UserRepository
+	adminLogRepo repository.AdminLogRepository
+	config       *config.Config
+}
+
+// NewReportService membuat instance baru dari ReportService
+func NewReportService(
+	reportRepo repository.ReportRepository,
+	itemRepo repository.ItemRepository,
+	chatRepo repository.ChatRepository,
+	userRepo repository.UserRepository,
+	adminLogRepo repository.AdminLogRepository,
+	cfg *config.Config,
+) ReportService {
+	return &reportService{
+		reportRepo:   reportRepo,
+		itemRepo:     itemRepo,
+		chatRepo:     chatRepo,
+		userRepo:     userRepo,
+		adminLogRepo: adminLogRepo,
+		config:       cfg,
+	}
+}
+
+// Create membuat laporan. Jika jumlah pelapor berbeda mencapai Report.AutoHideThreshold,
+// target langsung disembunyikan sampai laporan ditinjau moderator.
+func (s *reportService) Create(ctx context.Context, pelaporID uint, req domain.CreateReportRequest) (*domain.ReportResponse, error) {
+	if !req.TargetType.IsValid() {
+		return nil, errors.ValidationError("target_type harus salah satu dari: item, chat, user", nil)
+	}
+	if !req.Alasan.IsValid() {
+		return nil, errors.ValidationError("alasan harus salah satu dari: spam, scam, prohibited, harassment, other", nil)
+	}
+	req.Deskripsi = strings.TrimSpace(req.Deskripsi)
+	if req.Alasan == domain.ReportReasonOther && req.Deskripsi == "" {
+		return nil, errors.ValidationError("deskripsi wajib diisi untuk alasan other", nil)
+	}
+	if utf8.RuneCountInString(req.Deskripsi) > maxReportTextLength {
+		return nil, errors.ValidationError(fmt.Sprintf("deskripsi maksimal %d karakter", maxReportTextLength), nil)
+	}
+
+	ownerID, snapshot, err := s.resolveTarget(ctx, pelaporID, req.TargetType, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID == pelaporID {
+		return nil, errors.ValidationError("anda tidak dapat melaporkan diri sendiri atau konten milik sendiri", nil)
+	}
+
+	pending, err := s.reportRepo.HasPendingReport(ctx, pelaporID, req.TargetType, req.TargetID)
+	if err != nil {
+		return nil, errors.InternalError("Gagal memeriksa laporan", err)
+	}
+	if pending {
+		return nil, errors.ConflictError("anda sudah melaporkan konten ini dan laporan masih ditinjau", nil)
+	}
+
+	report := &domain.Report{
+		PelaporID:       pelaporID,
+		TargetType:      req.TargetType,
+		TargetID:        req.TargetID,
+		PemilikTargetID: ownerID,
+		Alasan:          req.Alasan,
+		Deskripsi:       req.Deskripsi,
+		Cuplikan:        snapshot,
+		Status:          domain.ReportOpen,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, errors.InternalError("Gagal menyimpan laporan", err)
+	}
+
+	// Laporan sudah tersimpan; kegagalan menyembunyikan target hanya dicatat
+	if err := s.autoHide(ctx, report); err != nil {
+		log.Printf("Gagal menyembunyikan target laporan %d: %v", report.ID, err)
+	}
+
+	response := report.ToResponse()
+	return &response, nil
+}
+
+// GetMine mendapatkan laporan yang dibuat pengguna
+func (s *reportService) GetMine(ctx context.Context, pelaporID uint, page, limit int) ([]domain.ReportResponse, int, int64, error) {
+	// Validasi input paginasi
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	reports, total, err := s.reportRepo.FindByPelaporID(ctx, pelaporID, page, limit)
+	if err != nil {
+		return nil, 0, 0, errors.InternalError("Gagal mengambil laporan", err)
+	}
+
+	responses := make([]domain.ReportResponse, 0, len(reports))
+	for _, report := range reports {
+		responses = append(responses, report.ToResponse())
+	}
+
+	// Hitung total halaman
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return responses, totalPages, total, nil
+}
+
+// GetQueue mendapatkan antrean laporan untuk moderator.
+// Principal pada context harus memiliki izin policy.ReportModerate.
+func (s *reportService) GetQueue(ctx context.Context, filter domain.ReportFilter, page, limit int) ([]domain.AdminReportResponse, int, int64, error) {
+	if _, err := s.authorize(ctx); err != nil {
+		return nil, 0, 0, err
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, 0, 0, errors.ValidationError("status harus salah satu dari: open, reviewing, actioned, dismissed", nil)
+	}
+	if filter.TargetType != "" && !filter.TargetType.IsValid() {
+		return nil, 0, 0, errors.ValidationError("target_type harus salah satu dari: item, chat, user", nil)
+	}
+
+	// Validasi input paginasi
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	reports, total, err := s.reportRepo.FindQueue(ctx, filter, page, limit)
+	if err != nil {
+		return nil, 0, 0, errors.InternalError("Gagal mengambil antrean laporan", err)
+	}
+
+	responses := make([]domain.AdminReportResponse, 0, len(reports))
+	for i := range reports {
+		response, err := s.adminResponse(ctx, &reports[i])
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		responses = append(responses, *response)
+	}
+
+	// Hitung total halaman
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return responses, totalPages, total, nil
+}
+
+// GetByID mendapatkan detail laporan untuk moderator
+func (s *reportService) GetByID(ctx context.Context, id uint) (*domain.AdminReportResponse, error) {
+	if _, err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	report, err := s.findReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.adminResponse(ctx, report)
+}
+
+// Assign menugaskan laporan ke moderator. Tanpa moderator_id, laporan ditugaskan ke diri sendiri.
+func (s *reportService) Assign(ctx context.Context, id uint, req domain.AssignReportRequest) (*domain.AdminReportResponse, error) {
+	principal, err := s.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.findReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !report.Status.IsPending() {
+		return nil, errors.ConflictError("laporan sudah diselesaikan", nil)
+	}
+
+	moderatorID := req.ModeratorID
+	if moderatorID == 0 {
+		moderatorID = principal.UserID
+	}
+	if moderatorID != principal.UserID {
+		moderator, err := s.userRepo.FindByID(ctx, moderatorID)
+		if err != nil || moderator.IsAnonymized() {
+			return nil, errors.NotFoundError("moderator tidak ditemukan", err)
+		}
+		if moderator.Role != domain.RoleModerator && moderator.Role != domain.RoleAdmin {
+			return nil, errors.ValidationError("laporan hanya dapat ditugaskan ke moderator atau admin", nil)
+		}
+	}
+
+	report.ModeratorID = &moderatorID
+	report.Status = domain.ReportReviewing
+	if err := s.reportRepo.Update(ctx, report); err != nil {
+		return nil, errors.InternalError("Gagal menugaskan laporan", err)
+	}
+
+	return s.reload(ctx, report.ID)
+}
+
+// Resolve menyelesaikan laporan. Laporan terbuka lain terhadap target yang sama ikut diselesaikan.
+// Target tetap disembunyikan jika laporan ditindaklanjuti (actioned) dan ditampilkan kembali jika ditolak (dismissed).
+func (s *reportService) Resolve(ctx context.Context, id uint, req domain.ResolveReportRequest) (*domain.AdminReportResponse, error) {
+	principal, err := s.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status != domain.ReportActioned && req.Status != domain.ReportDismissed {
+		return nil, errors.ValidationError("status harus actioned atau dismissed", nil)
+	}
+	req.Catatan = strings.TrimSpace(req.Catatan)
+	if req.Catatan == "" {
+		return nil, errors.ValidationError("catatan penyelesaian wajib diisi", nil)
+	}
+	if utf8.RuneCountInString(req.Catatan) > maxReportTextLength {
+		return nil, errors.ValidationError(fmt.Sprintf("catatan maksimal %d karakter", maxReportTextLength), nil)
+	}
+
+	report, err := s.findReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !report.Status.IsPending() {
+		return nil, errors.ConflictError("laporan sudah diselesaikan", nil)
+	}
+
+	// Hubungkan tindakan yang sudah dilakukan (misalnya penangguhan) dengan laporan ini
+	linked, err := s.reportRepo.LinkAdminLogs(ctx, report.ID, report.PemilikTargetID, req.AdminLogIDs)
+	if err != nil {
+		return nil, errors.InternalError("Gagal menghubungkan catatan tindakan", err)
+	}
+	if linked != int64(len(req.AdminLogIDs)) {
+		return nil, errors.ValidationError("sebagian catatan tindakan tidak ditemukan, sudah terhubung dengan laporan lain, atau bukan tindakan terhadap pemilik target", nil)
+	}
+
+	now := time.Now()
+	report.Status = req.Status
+	report.ModeratorID = &principal.UserID
+	report.CatatanPenyelesaian = req.Catatan
+	report.ResolvedAt = &now
+	if err := s.reportRepo.Update(ctx, report); err != nil {
+		return nil, errors.InternalError("Gagal menyelesaikan laporan", err)
+	}
+	if err := s.reportRepo.ResolvePending(ctx, report); err != nil {
+		return nil, errors.InternalError("Gagal menyelesaikan laporan terkait", err)
+	}
+
+	// Sesuaikan visibilitas target dengan keputusan moderator
+	var hiddenAt *time.Time
+	visibilityAction := domain.AdminActionShowContent
+	if req.Status == domain.ReportActioned {
+		hiddenAt = &now
+		visibilityAction = domain.AdminActionHideContent
+	}
+	changed, err := s.reportRepo.SetTargetHidden(ctx, report.TargetType, report.TargetID, hiddenAt)
+	if err != nil {
+		return nil, errors.InternalError("Gagal mengubah visibilitas target laporan", err)
+	}
+	if changed {
+		if err := s.record(ctx, principal, report, visibilityAction, req.Catatan); err != nil {
+			return nil, errors.InternalError("Gagal mencatat tindakan", err)
+		}
+	}
+	if err := s.record(ctx, principal, report, domain.AdminActionResolveReport, req.Catatan); err != nil {
+		return nil, errors.InternalError("Gagal mencatat tindakan", err)
+	}
+
+	return s.reload(ctx, report.ID)
+}
+
+// resolveTarget memastikan target laporan ada dan dapat dilihat pelapor, lalu mengembalikan
+// pemilik target dan cuplikan kontennya
+func (s *reportService) resolveTarget(ctx context.Context, pelaporID uint, targetType domain.ReportTargetType, targetID uint) (uint, string, error) {
+	switch targetType {
+	case domain.ReportTargetItem:
+		item, err := s.itemRepo.FindByID(ctx, targetID)
+		if err != nil {
+			return 0, "", errors.NotFoundError("barang tidak ditemukan", err)
+		}
+		return item.PenjualID, item.NamaBarang + "\n\n" + item.Deskripsi, nil
+	case domain.ReportTargetChat:
+		chat, err := s.chatRepo.FindByID(ctx, targetID)
+		// Pengguna hanya dapat melaporkan pesan yang ia terima
+		if err != nil || chat.PenerimaID != pelaporID {
+			return 0, "", errors.NotFoundError("pesan tidak ditemukan", err)
+		}
+		return chat.PengirimID, chat.Pesan, nil
+	case domain.ReportTargetUser:
+		user, err := s.userRepo.FindByID(ctx, targetID)
+		if err != nil || user.IsAnonymized() {
+			return 0, "", errors.NotFoundError("pengguna tidak ditemukan", err)
+		}
+		return user.ID, user.Nama, nil
+	}
+	return 0, "", errors.ValidationError("target_type tidak dikenal", nil)
+}
+
+// autoHide menyembunyikan target jika jumlah pelapor berbeda mencapai batas
+func (s *reportService) autoHide(ctx context.Context, report *domain.Report) error {
+	threshold := s.config.Report.AutoHideThreshold
+	if threshold <= 0 {
+		return nil
+	}
+
+	reporters, err := s.reportRepo.CountPendingReporters(ctx, report.TargetType, report.TargetID)
+	if err != nil {
+		return err
+	}
+	if reporters < int64(threshold) {
+		return nil
+	}
+
+	now := time.Now()
+	hidden, err := s.reportRepo.SetTargetHidden(ctx, report.TargetType, report.TargetID, &now)
+	if err != nil {
+		return err
+	}
+	if hidden {
+		log.Printf("Target laporan %s #%d disembunyikan otomatis setelah dilaporkan %d pengguna", report.TargetType, report.TargetID, reporters)
+	}
+	return nil
+}
+
+// authorize memastikan principal pada context boleh memoderasi laporan
+func (s *reportService) authorize(ctx context.Context) (policy.Principal, error) {
+	principal, ok := policy.FromContext(ctx)
+	if !ok || !policy.Can(principal, policy.ReportModerate, nil) {
+		return principal, errors.ForbiddenError("akses ditolak: tidak memiliki izin", policy.ErrForbidden)
+	}
+	return principal, nil
+}
+
+// findReport mencari laporan dan mengembalikan NotFoundError jika tidak ada
+func (s *reportService) findReport(ctx context.Context, id uint) (*domain.Report, error) {
+	report, err := s.reportRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NotFoundError("laporan tidak ditemukan", err)
+	}
+	return report, nil
+}
+
+// reload memuat ulang laporan beserta relasinya lalu mengubahnya ke format respons moderator
+func (s *reportService) reload(ctx context.Context, id uint) (*domain.AdminReportResponse, error) {
+	report, err := s.findReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.adminResponse(ctx, report)
+}
+
+// adminResponse melengkapi laporan dengan status target untuk antrean moderasi
+func (s *reportService) adminResponse(ctx context.Context, report *domain.Report) (*domain.AdminReportResponse, error) {
+	hidden, err := s.reportRepo.IsTargetHidden(ctx, report.TargetType, report.TargetID)
+	if err != nil {
+		return nil, errors.InternalError("Gagal memeriksa target laporan", err)
+	}
+	reporters, err := s.reportRepo.CountPendingReporters(ctx, report.TargetType, report.TargetID)
+	if err != nil {
+		return nil, errors.InternalError("Gagal menghitung laporan target", err)
+	}
+
+	response := domain.NewAdminReportResponse(report, hidden, reporters)
+	return &response, nil
+}
+
+// record mencatat tindakan moderator terhadap laporan
+func (s *reportService) record(ctx context.Context, principal policy.Principal, report *domain.Report, action domain.AdminAction, reason string) error {
+	return s.adminLogRepo.Create(ctx, &domain.AdminLog{
+		AdminID:   principal.UserID,
+		TargetID:  report.PemilikTargetID,
+		Action:    action,
+		Detail:    fmt.Sprintf("laporan #%d: %s #%d (%s)", report.ID, report.TargetType, report.TargetID, report.Status),
+		Reason:    reason,
+		LaporanID: &report.ID,
+	})
+}
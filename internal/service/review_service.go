@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+)
+
+// maxReviewTextLength adalah panjang maksimum komentar dan balasan ulasan (karakter)
+const maxReviewTextLength = 1000
+
+// ReviewService adalah interface untuk layanan ulasan transaksi
+type ReviewService interface {
+	Create(ctx context.Context, transactionID, userID uint, req domain.ReviewRequest) (*domain.ReviewResponse, error)
+	Update(ctx context.Context, reviewID, userID uint, req domain.ReviewRequest) (*domain.ReviewResponse, error)
+	Reply(ctx context.Context, reviewID, userID uint, req domain.ReplyReviewRequest) (*domain.ReviewResponse, error)
+	GetByTransactionID(ctx context.Context, transactionID, userID uint) ([]domain.ReviewResponse, error)
+	GetByUserID(ctx context.Context, userID uint, page, limit int) ([]domain.ReviewResponse, int, int64, error)
+	Hide(ctx context.Context, reviewID uint, req domain.HideReviewRequest) (*domain.ReviewResponse, error)
+	Unhide(ctx context.Context, reviewID uint) (*domain.ReviewResponse, error)
+}
+
+// reviewService adalah implementasi dari ReviewService
+type reviewService struct {
+	reviewRepo      repository.ReviewRepository
+	transactionRepo repository.TransactionRepository
+	adminLogRepo    repository.AdminLogRepository
+	config          *config.Config
+}
+
+// NewReviewService membuat instance baru dari ReviewService
+func NewReviewService(
+	reviewRepo repository.ReviewRepository,
+	transactionRepo repository.TransactionRepository,
+	adminLogRepo repository.AdminLogRepository,
+	cfg *config.Config,
+) ReviewService {
+	return &reviewService{
+		reviewRepo:      reviewRepo,
+		transactionRepo: transactionRepo,
+		adminLogRepo:    adminLogRepo,
+		config:          cfg,
+	}
+}
+
+// Create membuat ulasan untuk pihak lain dalam transaksi yang sudah selesai.
+// Pembeli mengulas penjual dan sebaliknya, masing-masing satu kali per transaksi.
+func (s *reviewService) Create(ctx context.Context, transactionID, userID uint, req domain.ReviewRequest) (*domain.ReviewResponse, error) {
+	komentar, err := validateReviewRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.transactionRepo.FindByID(ctx, transactionID)
+	if err != nil {
+		return nil, errors.NotFoundError("transaksi tidak ditemukan", err)
+	}
+
+	// Tentukan peran penulis dan pengguna yang diulas
+	var peran domain.ReviewerRole
+	var targetID uint
+	switch userID {
+	case transaction.PembeliID:
+		peran, targetID = domain.ReviewerPembeli, transaction.Barang.PenjualID
+	case transaction.Barang.PenjualID:
+		peran, targetID = domain.ReviewerPenjual, transaction.PembeliID
+	default:
+		return nil, errors.ForbiddenError("anda bukan pihak dalam transaksi ini", nil)
+	}
+
+	if transaction.StatusTransaksi != domain.StatusSelesai {
+		return nil, errors.ValidationError("ulasan hanya dapat diberikan untuk transaksi yang sudah selesai", nil)
+	}
+	// Waktu transaksi terakhir diperbarui dipakai sebagai waktu transaksi selesai
+	if time.Now().After(transaction.UpdatedAt.Add(s.config.Review.SubmitWindow)) {
+		return nil, errors.ValidationError("batas waktu memberi ulasan untuk transaksi ini sudah lewat", nil)
+	}
+
+	exists, err := s.reviewRepo.ExistsByTransactionAndAuthor(ctx, transaction.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.ConflictError("anda sudah memberi ulasan untuk transaksi ini", nil)
+	}
+
+	review := &domain.Review{
+		TransaksiID: transaction.ID,
+		PenulisID:   userID,
+		TargetID:    targetID,
+		Peran:       peran,
+		Rating:      req.Rating,
+		Komentar:    komentar,
+	}
+	if err := s.reviewRepo.Create(ctx, review); err != nil {
+		return nil, err
+	}
+	if err := s.reviewRepo.RefreshUserRating(ctx, targetID); err != nil {
+		return nil, err
+	}
+
+	return s.response(ctx, review.ID)
+}
+
+// Update mengubah rating dan komentar ulasan selama masih dalam batas waktu edit
+func (s *reviewService) Update(ctx context.Context, reviewID, userID uint, req domain.ReviewRequest) (*domain.ReviewResponse, error) {
+	komentar, err := validateReviewRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.findReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.ReviewUpdate, review) {
+		return nil, errors.ForbiddenError("anda hanya dapat mengubah ulasan milik sendiri", policy.ErrForbidden)
+	}
+	if review.IsHidden() {
+		return nil, errors.ForbiddenError("ulasan yang disembunyikan tidak dapat diubah", nil)
+	}
+	if time.Now().After(review.CreatedAt.Add(s.config.Review.EditWindow)) {
+		return nil, errors.ValidationError("batas waktu mengubah ulasan sudah lewat", nil)
+	}
+
+	review.Rating = req.Rating
+	review.Komentar = komentar
+	if err := s.reviewRepo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+	if err := s.reviewRepo.RefreshUserRating(ctx, review.TargetID); err != nil {
+		return nil, err
+	}
+
+	response := review.ToResponse()
+	return &response, nil
+}
+
+// Reply menambahkan atau mengubah balasan dari pengguna yang diulas.
+// Balasan yang sudah ada hanya bisa diubah selama masih dalam batas waktu edit.
+func (s *reviewService) Reply(ctx context.Context, reviewID, userID uint, req domain.ReplyReviewRequest) (*domain.ReviewResponse, error) {
+	balasan := strings.TrimSpace(req.Balasan)
+	if balasan == "" {
+		return nil, errors.ValidationError("balasan wajib diisi", nil)
+	}
+	if utf8.RuneCountInString(balasan) > maxReviewTextLength {
+		return nil, errors.ValidationError(fmt.Sprintf("balasan maksimal %d karakter", maxReviewTextLength), nil)
+	}
+
+	review, err := s.findReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.ReviewReply, review) {
+		return nil, errors.ForbiddenError("hanya pengguna yang diulas yang dapat membalas ulasan ini", policy.ErrForbidden)
+	}
+
+	now := time.Now()
+	if review.BalasanAt != nil && now.After(review.BalasanAt.Add(s.config.Review.EditWindow)) {
+		return nil, errors.ValidationError("batas waktu mengubah balasan sudah lewat", nil)
+	}
+
+	review.Balasan = balasan
+	review.BalasanAt = &now
+	if err := s.reviewRepo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+
+	response := review.ToResponse()
+	return &response, nil
+}
+
+// GetByTransactionID mendapatkan ulasan untuk satu transaksi.
+// Hanya pihak dalam transaksi (dan admin) yang boleh melihatnya, termasuk ulasan yang disembunyikan.
+func (s *reviewService) GetByTransactionID(ctx context.Context, transactionID, userID uint) ([]domain.ReviewResponse, error) {
+	transaction, err := s.transactionRepo.FindByID(ctx, transactionID)
+	if err != nil {
+		return nil, errors.NotFoundError("transaksi tidak ditemukan", err)
+	}
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.TransactionRead, transaction) {
+		return nil, errors.ForbiddenError("anda tidak memiliki akses ke transaksi ini", policy.ErrForbidden)
+	}
+
+	reviews, err := s.reviewRepo.FindByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]domain.ReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		responses = append(responses, review.ToResponse())
+	}
+	return responses, nil
+}
+
+// GetByUserID mendapatkan ulasan yang tidak disembunyikan untuk pengguna dengan paginasi
+func (s *reviewService) GetByUserID(ctx context.Context, userID uint, page, limit int) ([]domain.ReviewResponse, int, int64, error) {
+	// Validasi input paginasi
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	reviews, total, err := s.reviewRepo.FindVisibleByTargetID(ctx, userID, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	responses := make([]domain.ReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		responses = append(responses, review.ToResponse())
+	}
+
+	// Hitung total halaman
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return responses, totalPages, total, nil
+}
+
+// Hide menyembunyikan ulasan yang melanggar aturan. Ulasan tersembunyi tidak dihitung dalam rating.
+// Principal pada context harus memiliki izin policy.ReviewModerate.
+func (s *reviewService) Hide(ctx context.Context, reviewID uint, req domain.HideReviewRequest) (*domain.ReviewResponse, error) {
+	principal, err := s.authorizeModeration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.ValidationError("alasan wajib diisi", nil)
+	}
+
+	review, err := s.findReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.IsHidden() {
+		return nil, errors.ConflictError("ulasan sudah disembunyikan", nil)
+	}
+
+	now := time.Now()
+	review.HiddenAt = &now
+	review.HiddenReason = reason
+	if err := s.moderate(ctx, principal, review, domain.AdminActionHideReview, reason); err != nil {
+		return nil, err
+	}
+
+	response := review.ToResponse()
+	return &response, nil
+}
+
+// Unhide menampilkan kembali ulasan yang disembunyikan
+func (s *reviewService) Unhide(ctx context.Context, reviewID uint) (*domain.ReviewResponse, error) {
+	principal, err := s.authorizeModeration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.findReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !review.IsHidden() {
+		return nil, errors.ConflictError("ulasan tidak sedang disembunyikan", nil)
+	}
+
+	review.HiddenAt = nil
+	review.HiddenReason = ""
+	if err := s.moderate(ctx, principal, review, domain.AdminActionShowReview, ""); err != nil {
+		return nil, err
+	}
+
+	response := review.ToResponse()
+	return &response, nil
+}
+
+// authorizeModeration memastikan principal pada context boleh memoderasi ulasan
+func (s *reviewService) authorizeModeration(ctx context.Context) (policy.Principal, error) {
+	principal, ok := policy.FromContext(ctx)
+	if !ok || !policy.Can(principal, policy.ReviewModerate, nil) {
+		return principal, errors.ForbiddenError("akses ditolak: tidak memiliki izin", policy.ErrForbidden)
+	}
+	return principal, nil
+}
+
+// moderate menyimpan perubahan moderasi, menghitung ulang rating dan mencatat tindakannya
+func (s *reviewService) moderate(ctx context.Context, principal policy.Principal, review *domain.Review, action domain.AdminAction, reason string) error {
+	if err := s.reviewRepo.Update(ctx, review); err != nil {
+		return err
+	}
+	if err := s.reviewRepo.RefreshUserRating(ctx, review.TargetID); err != nil {
+		return err
+	}
+	return s.adminLogRepo.Create(ctx, &domain.AdminLog{
+		AdminID:  principal.UserID,
+		TargetID: review.PenulisID,
+		Action:   action,
+		Detail:   fmt.Sprintf("ulasan #%d", review.ID),
+		Reason:   reason,
+	})
+}
+
+// findReview mencari ulasan dan mengembalikan NotFoundError jika tidak ada
+func (s *reviewService) findReview(ctx context.Context, id uint) (*domain.Review, error) {
+	review, err := s.reviewRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NotFoundError("ulasan tidak ditemukan", err)
+	}
+	return review, nil
+}
+
+// response memuat ulang ulasan beserta penulisnya lalu mengubahnya ke format respons
+func (s *reviewService) response(ctx context.Context, id uint) (*domain.ReviewResponse, error) {
+	review, err := s.reviewRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	response := review.ToResponse()
+	return &response, nil
+}
+
+// validateReviewRequest memeriksa rating dan komentar, lalu mengembalikan komentar yang sudah dirapikan
+func validateReviewRequest(req domain.ReviewRequest) (string, error) {
+	if req.Rating < 1 || req.Rating > 5 {
+		return "", errors.ValidationError("rating harus antara 1 dan 5", nil)
+	}
+	komentar := strings.TrimSpace(req.Komentar)
+	if utf8.RuneCountInString(komentar) > maxReviewTextLength {
+		return "", errors.ValidationError(fmt.Sprintf("komentar maksimal %d karakter", maxReviewTextLength), nil)
+	}
+	return komentar, nil
+}
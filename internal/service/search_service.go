@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
+)
+
+const (
+	// suggestionLimit adalah jumlah maksimal saran untuk setiap jenis saran
+	suggestionLimit = 5
+
+	// minSuggestionLength adalah panjang minimal teks sebelum saran dicari,
+	// teks yang lebih pendek mencocokkan terlalu banyak barang untuk berguna
+	minSuggestionLength = 2
+
+	// suggestionCacheMaxEntries adalah batas jumlah entri sebelum cache saran dibersihkan
+	suggestionCacheMaxEntries = 10000
+
+	// searchLogQueueSize adalah kapasitas antrean pencarian yang menunggu dicatat worker.
+	// Jika antrean penuh, pencarian tidak dicatat agar GET /items tidak ikut tertahan.
+	searchLogQueueSize = 1024
+	// searchLogBatchSize adalah jumlah pencarian yang dicatat dalam satu transaksi
+	searchLogBatchSize = 100
+	// searchLogFlushInterval adalah jeda maksimal pencarian menunggu di antrean sebelum dicatat
+	searchLogFlushInterval = 5 * time.Second
+	// searchLogFlushTimeout membatasi lama satu kali pencatatan, termasuk saat server berhenti
+	searchLogFlushTimeout = 5 * time.Second
+	// searchLogPurgeInterval adalah jeda worker menghapus log pencarian yang melewati masa simpan
+	searchLogPurgeInterval = time.Hour
+)
+
+// searchWordPattern memecah teks menjadi kata untuk pencocokan kata terlarang dan kategori
+var searchWordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// SearchService adalah interface untuk pencatatan pencarian dan saran pencarian barang
+type SearchService interface {
+	// RecordSearch memasukkan pencarian barang beserta jumlah hasilnya ke antrean pencatatan
+	// tanpa menunggu database. Pencarian dicatat oleh worker Run. Hanya pencarian yang ditemukan
+	// melalui full-text search yang dihitung di kueri populer, sekali per pengguna atau alamat IP
+	// dalam jendela deduplikasi.
+	RecordSearch(ctx context.Context, search string, results int64, match domain.SearchMatch, client domain.ClientInfo)
+	// Run menjalankan worker pencatat pencarian sampai ctx dibatalkan.
+	// Sisa antrean tetap dicatat sebelum Run selesai. Worker juga menghapus log pencarian
+	// yang sudah melewati masa simpan.
+	Run(ctx context.Context)
+	// Suggest memberikan saran kueri populer, nama barang dan kategori untuk teks yang sedang diketik.
+	// Saran yang mengandung kata terlarang tidak pernah dikembalikan.
+	Suggest(ctx context.Context, query string) (*domain.SearchSuggestionResponse, error)
+}
+
+// searchService adalah implementasi dari SearchService
+type searchService struct {
+	searchRepo   repository.SearchRepository
+	itemRepo     repository.ItemRepository
+	blockedTerms []string
+	config       *config.Config
+
+	mu    sync.RWMutex
+	cache map[string]suggestionCacheEntry
+
+	// queue berisi pencarian yang menunggu dicatat worker
+	queue chan domain.SearchLog
+}
+
+// suggestionCacheEntry adalah saran pencarian yang disimpan di cache sampai waktu until
+type suggestionCacheEntry struct {
+	response *domain.SearchSuggestionResponse
+	until    time.Time
+}
+
+// NewSearchService membuat instance baru dari SearchService
+func NewSearchService(searchRepo repository.SearchRepository, itemRepo repository.ItemRepository, config *config.Config) SearchService {
+	blockedTerms := make([]string, 0, len(config.Search.BlockedTerms))
+	for _, term := range config.Search.BlockedTerms {
+		if words := searchWords(term); len(words) > 0 {
+			blockedTerms = append(blockedTerms, strings.Join(words, " "))
+		}
+	}
+
+	return &searchService{
+		searchRepo:   searchRepo,
+		itemRepo:     itemRepo,
+		blockedTerms: blockedTerms,
+		config:       config,
+		cache:        make(map[string]suggestionCacheEntry),
+		queue:        make(chan domain.SearchLog, searchLogQueueSize),
+	}
+}
+
+// RecordSearch memasukkan pencarian barang ke antrean pencatatan
+func (s *searchService) RecordSearch(ctx context.Context, search string, results int64, match domain.SearchMatch, client domain.ClientInfo) {
+	query := domain.NormalizeSearchQuery(search)
+	if query == "" {
+		return
+	}
+
+	// Hanya kueri yang menemukan barang melalui full-text search dan bebas kata terlarang yang layak
+	// disarankan ke pengguna lain. Barang yang ditemukan dari kemiripan nama berarti kueri salah ketik.
+	entry := domain.SearchLog{
+		Kueri:       query,
+		JumlahHasil: results,
+		PencariHash: s.searcherHash(ctx, client),
+		Dihitung:    results > 0 && match == domain.SearchMatchFullText && !s.isBlocked(query),
+		CreatedAt:   time.Now(),
+	}
+	select {
+	case s.queue <- entry:
+	default:
+		log.Printf("Antrean log pencarian penuh, pencarian tidak dicatat")
+	}
+}
+
+// Run menjalankan worker pencatat pencarian. Pencarian dicatat per batch,
+// setiap searchLogBatchSize pencarian atau setiap searchLogFlushInterval.
+func (s *searchService) Run(ctx context.Context) {
+	ticker := time.NewTicker(searchLogFlushInterval)
+	defer ticker.Stop()
+	purgeTicker := time.NewTicker(searchLogPurgeInterval)
+	defer purgeTicker.Stop()
+
+	s.purgeSearchLogs(ctx)
+
+	batch := make([]domain.SearchLog, 0, searchLogBatchSize)
+	for {
+		select {
+		case entry := <-s.queue:
+			batch = append(batch, entry)
+			if len(batch) < searchLogBatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case <-purgeTicker.C:
+			s.purgeSearchLogs(ctx)
+			continue
+		case <-ctx.Done():
+			// Pencarian yang sudah masuk antrean tetap dicatat saat server berhenti
+			for {
+				select {
+				case entry := <-s.queue:
+					batch = append(batch, entry)
+				default:
+					s.flushSearchLogs(ctx, batch)
+					return
+				}
+			}
+		}
+
+		s.flushSearchLogs(ctx, batch)
+		batch = batch[:0]
+	}
+}
+
+// flushSearchLogs mencatat satu batch pencarian.
+// Pencatatan tidak ikut dibatalkan bersama ctx agar batch terakhir tetap tersimpan saat server berhenti.
+func (s *searchService) flushSearchLogs(ctx context.Context, batch []domain.SearchLog) {
+	if len(batch) == 0 {
+		return
+	}
+
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), searchLogFlushTimeout)
+	defer cancel()
+	if err := s.searchRepo.LogBatch(flushCtx, batch, s.config.Search.PopularDedupeWindow); err != nil {
+		log.Printf("Gagal mencatat %d pencarian: %v", len(batch), err)
+	}
+}
+
+// purgeSearchLogs menghapus log pencarian yang sudah melewati masa simpan.
+// Jumlah pencarian di kueri populer tidak berubah.
+func (s *searchService) purgeSearchLogs(ctx context.Context) {
+	if s.config.Search.LogRetention <= 0 {
+		return
+	}
+
+	if _, err := s.searchRepo.DeleteLogsBefore(ctx, time.Now().Add(-s.config.Search.LogRetention)); err != nil {
+		log.Printf("Gagal menghapus log pencarian lama: %v", err)
+	}
+}
+
+// searcherHash membuat kunci pencari untuk deduplikasi kueri populer: ID pengguna jika login,
+// atau alamat IP. Kunci di-HMAC dengan secret server sehingga log tidak berisi identitas pencari
+// dan alamat IP tidak dapat ditebak dari hash-nya.
+func (s *searchService) searcherHash(ctx context.Context, client domain.ClientInfo) string {
+	if principal, ok := policy.FromContext(ctx); ok && principal.UserID != 0 {
+		return utils.SignValues(s.config.JWT.Secret, "pencarian", "pengguna", strconv.FormatUint(uint64(principal.UserID), 10))
+	}
+	if client.IPAddress != "" {
+		return utils.SignValues(s.config.JWT.Secret, "pencarian", "ip", client.IPAddress)
+	}
+	return ""
+}
+
+// Suggest memberikan saran pencarian untuk teks yang sedang diketik
+func (s *searchService) Suggest(ctx context.Context, query string) (*domain.SearchSuggestionResponse, error) {
+	query = domain.NormalizeSearchQuery(query)
+	if utf8.RuneCountInString(query) < minSuggestionLength || s.isBlocked(query) {
+		return domain.NewSearchSuggestionResponse(), nil
+	}
+
+	// Saran dipanggil di setiap ketikan, sehingga teks yang sama dari banyak pengguna dilayani dari memori
+	now := time.Now()
+	s.mu.RLock()
+	entry, ok := s.cache[query]
+	s.mu.RUnlock()
+	if ok && now.Before(entry.until) {
+		return entry.response, nil
+	}
+
+	response := domain.NewSearchSuggestionResponse()
+
+	// Kueri yang diblokir setelah tercatat tetap disaring, sehingga diambil lebih banyak dari batas
+	queries, err := s.searchRepo.FindPopularQueries(ctx, query, s.config.Search.PopularMinCount, suggestionLimit*2)
+	if err != nil {
+		return nil, errors.InternalError("Gagal mengambil kueri populer", err)
+	}
+	for _, popular := range queries {
+		if len(response.Kueri) == suggestionLimit {
+			break
+		}
+		if !s.isBlocked(popular) {
+			response.Kueri = append(response.Kueri, popular)
+		}
+	}
+
+	// Beberapa barang dapat memiliki nama yang sama, nama yang sama hanya disarankan sekali
+	items, err := s.itemRepo.FindNameSuggestions(ctx, query, suggestionLimit*2)
+	if err != nil {
+		return nil, errors.InternalError("Gagal mengambil saran barang", err)
+	}
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if len(response.Barang) == suggestionLimit {
+			break
+		}
+		name := domain.NormalizeSearchQuery(item.NamaBarang)
+		if seen[name] || s.isBlocked(name) {
+			continue
+		}
+		seen[name] = true
+		response.Barang = append(response.Barang, domain.ItemSuggestion{
+			ID:         item.ID,
+			NamaBarang: item.NamaBarang,
+			Kategori:   item.Kategori,
+		})
+	}
+
+	response.Kategori = matchCategories(query)
+
+	s.storeSuggestion(query, response, now)
+
+	return response, nil
+}
+
+// isBlocked memeriksa apakah teks mengandung kata atau frasa terlarang sebagai kata utuh,
+// sehingga kata terlarang tidak ikut memblokir kata lain yang kebetulan mengandungnya
+func (s *searchService) isBlocked(text string) bool {
+	if len(s.blockedTerms) == 0 {
+		return false
+	}
+
+	padded := " " + strings.Join(searchWords(text), " ") + " "
+	for _, term := range s.blockedTerms {
+		if strings.Contains(padded, " "+term+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// storeSuggestion menyimpan saran ke cache dan membuang entri yang sudah kedaluwarsa jika cache penuh
+func (s *searchService) storeSuggestion(query string, response *domain.SearchSuggestionResponse, now time.Time) {
+	if s.config.Search.SuggestCacheTTL <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.cache) >= suggestionCacheMaxEntries {
+		for key, existing := range s.cache {
+			if !now.Before(existing.until) {
+				delete(s.cache, key)
+			}
+		}
+		// Semua entri masih berlaku, cache dikosongkan agar memori tetap terbatas
+		if len(s.cache) >= suggestionCacheMaxEntries {
+			s.cache = make(map[string]suggestionCacheEntry)
+		}
+	}
+	s.cache[query] = suggestionCacheEntry{response: response, until: now.Add(s.config.Search.SuggestCacheTTL)}
+}
+
+// matchCategories mencari kategori yang namanya diawali salah satu kata di query,
+// misalnya "laptop elek" menyarankan kategori Elektronik
+func matchCategories(query string) []domain.ItemCategory {
+	categories := []domain.ItemCategory{}
+	words := searchWords(query)
+	for _, category := range domain.ItemCategories {
+		name := strings.ToLower(string(category))
+		for _, word := range words {
+			if utf8.RuneCountInString(word) >= minSuggestionLength && strings.HasPrefix(name, word) {
+				categories = append(categories, category)
+				break
+			}
+		}
+	}
+	return categories
+}
+
+// searchWords memecah teks menjadi kata-kata huruf kecil
+func searchWords(text string) []string {
+	return searchWordPattern.FindAllString(strings.ToLower(text), -1)
+}
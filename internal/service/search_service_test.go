@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+)
+
+// fakeSearchRepository mencatat setiap batch yang disimpan worker
+type fakeSearchRepository struct {
+	repository.SearchRepository
+
+	mu           sync.Mutex
+	entries      []domain.SearchLog
+	batches      int
+	dedupeWindow time.Duration
+	purgedBefore []time.Time
+}
+
+func (r *fakeSearchRepository) LogBatch(ctx context.Context, entries []domain.SearchLog, dedupeWindow time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches++
+	r.entries = append(r.entries, entries...)
+	r.dedupeWindow = dedupeWindow
+	return nil
+}
+
+func (r *fakeSearchRepository) DeleteLogsBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.purgedBefore = append(r.purgedBefore, before)
+	return 0, nil
+}
+
+// counted menghitung pencarian yang ditandai Dihitung per kueri
+func (r *fakeSearchRepository) counted() map[string]int64 {
+	counts := make(map[string]int64)
+	for _, entry := range r.entries {
+		if entry.Dihitung {
+			counts[entry.Kueri]++
+		}
+	}
+	return counts
+}
+
+func newTestSearchService(blockedTerms ...string) (*searchService, *fakeSearchRepository) {
+	repo := &fakeSearchRepository{}
+	cfg := &config.Config{
+		JWT: config.JWTConfig{Secret: "rahasia"},
+		Search: config.SearchConfig{
+			BlockedTerms:        blockedTerms,
+			PopularDedupeWindow: time.Hour,
+			LogRetention:        90 * 24 * time.Hour,
+		},
+	}
+	return NewSearchService(repo, nil, cfg).(*searchService), repo
+}
+
+// runUntilStopped menjalankan worker dengan ctx yang sudah dibatalkan sehingga antrean langsung dicatat
+func runUntilStopped(t *testing.T, s *searchService) {
+	t.Helper()
+	ctx, stop := context.WithCancel(context.Background())
+	stop()
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run tidak berhenti setelah ctx dibatalkan")
+	}
+}
+
+var testClient = domain.ClientInfo{IPAddress: "203.0.113.7"}
+
+func TestRecordSearchDoesNotWriteSynchronously(t *testing.T) {
+	s, repo := newTestSearchService()
+
+	s.RecordSearch(context.Background(), "Laptop", 3, domain.SearchMatchFullText, testClient)
+	if repo.batches != 0 {
+		t.Errorf("RecordSearch menulis %d batch langsung, want 0", repo.batches)
+	}
+	if got := len(s.queue); got != 1 {
+		t.Errorf("antrean berisi %d pencarian, want 1", got)
+	}
+
+	// Antrean penuh tidak membuat request tertahan
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < searchLogQueueSize+10; i++ {
+			s.RecordSearch(context.Background(), "laptop", 1, domain.SearchMatchFullText, testClient)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RecordSearch tertahan saat antrean penuh")
+	}
+	if got := len(s.queue); got != searchLogQueueSize {
+		t.Errorf("antrean berisi %d pencarian, want %d", got, searchLogQueueSize)
+	}
+}
+
+func TestRunFlushesBatchesAndQueueOnShutdown(t *testing.T) {
+	s, repo := newTestSearchService()
+
+	// Request yang sudah dibatalkan tidak membatalkan pencatatan
+	requestCtx, cancelRequest := context.WithCancel(context.Background())
+	cancelRequest()
+
+	for i := 0; i < searchLogBatchSize+1; i++ {
+		s.RecordSearch(requestCtx, "  Laptop  ASUS ", 2, domain.SearchMatchFullText, testClient)
+	}
+	s.RecordSearch(requestCtx, "   ", 5, domain.SearchMatchFullText, testClient)
+
+	runUntilStopped(t, s)
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if got, want := len(repo.entries), searchLogBatchSize+1; got != want {
+		t.Errorf("jumlah pencarian tercatat = %d, want %d", got, want)
+	}
+	if repo.dedupeWindow != time.Hour {
+		t.Errorf("jendela deduplikasi = %v, want 1h", repo.dedupeWindow)
+	}
+	for _, entry := range repo.entries {
+		if entry.Kueri != "laptop asus" || entry.CreatedAt.IsZero() {
+			t.Fatalf("pencarian tercatat = %+v, want kueri ternormalisasi dengan waktu pencarian", entry)
+		}
+	}
+}
+
+func TestRecordSearchCountsOnlyFullTextMatches(t *testing.T) {
+	s, repo := newTestSearchService("judi")
+	ctx := context.Background()
+
+	s.RecordSearch(ctx, "laptop", 4, domain.SearchMatchFullText, testClient)
+	// Barang yang ditemukan dari kemiripan nama berarti kueri salah ketik
+	s.RecordSearch(ctx, "laptpo", 4, domain.SearchMatchSimilar, testClient)
+	s.RecordSearch(ctx, "kulkas", 0, domain.SearchMatchNone, testClient)
+	s.RecordSearch(ctx, "judi online", 5, domain.SearchMatchFullText, testClient)
+
+	runUntilStopped(t, s)
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	// Semua pencarian tetap dicatat, tetapi hanya yang cocok melalui full-text search yang dihitung
+	if len(repo.entries) != 4 {
+		t.Fatalf("jumlah pencarian tercatat = %d, want 4", len(repo.entries))
+	}
+	if got := repo.counted(); len(got) != 1 || got["laptop"] != 1 {
+		t.Errorf("pencarian yang dihitung = %v, want hanya laptop", got)
+	}
+}
+
+func TestSearcherHash(t *testing.T) {
+	s, _ := newTestSearchService()
+	anonymous := context.Background()
+	user := policy.WithPrincipal(context.Background(), policy.Principal{UserID: 7, Role: domain.RoleUser})
+	otherUser := policy.WithPrincipal(context.Background(), policy.Principal{UserID: 8, Role: domain.RoleUser})
+	otherIP := domain.ClientInfo{IPAddress: "198.51.100.1"}
+
+	ipHash := s.searcherHash(anonymous, testClient)
+	if ipHash == "" || ipHash == testClient.IPAddress || len(ipHash) > 64 {
+		t.Errorf("hash IP = %q, want HMAC maksimal 64 karakter", ipHash)
+	}
+	if got := s.searcherHash(anonymous, testClient); got != ipHash {
+		t.Error("hash IP yang sama seharusnya stabil")
+	}
+	if s.searcherHash(anonymous, otherIP) == ipHash {
+		t.Error("IP berbeda seharusnya menghasilkan hash berbeda")
+	}
+
+	// Pengguna yang login dikenali dari ID-nya meskipun berpindah jaringan
+	userHash := s.searcherHash(user, testClient)
+	if userHash == ipHash || s.searcherHash(user, otherIP) != userHash {
+		t.Error("hash pengguna seharusnya berdasarkan ID pengguna, bukan IP")
+	}
+	if s.searcherHash(otherUser, testClient) == userHash {
+		t.Error("pengguna berbeda seharusnya menghasilkan hash berbeda")
+	}
+
+	if got := s.searcherHash(anonymous, domain.ClientInfo{}); got != "" {
+		t.Errorf("hash tanpa pengguna dan IP = %q, want kosong", got)
+	}
+
+	// Hash bergantung pada secret server sehingga IP tidak dapat ditebak dari log
+	other, _ := newTestSearchService()
+	other.config.JWT.Secret = "rahasia-lain"
+	if other.searcherHash(anonymous, testClient) == ipHash {
+		t.Error("secret berbeda seharusnya menghasilkan hash berbeda")
+	}
+}
+
+func TestRunPurgesOldSearchLogs(t *testing.T) {
+	s, repo := newTestSearchService()
+	before := time.Now()
+	runUntilStopped(t, s)
+
+	repo.mu.Lock()
+	if len(repo.purgedBefore) != 1 {
+		t.Fatalf("DeleteLogsBefore dipanggil %d kali, want 1", len(repo.purgedBefore))
+	}
+	cutoff := before.Add(-90 * 24 * time.Hour)
+	if got := repo.purgedBefore[0]; got.Before(cutoff) || got.After(time.Now().Add(-90*24*time.Hour)) {
+		t.Errorf("batas penghapusan = %v, want sekitar %v", got, cutoff)
+	}
+	repo.mu.Unlock()
+
+	// Masa simpan 0 menonaktifkan penghapusan
+	s, repo = newTestSearchService()
+	s.config.Search.LogRetention = 0
+	runUntilStopped(t, s)
+	if len(repo.purgedBefore) != 0 {
+		t.Errorf("DeleteLogsBefore dipanggil %d kali, want 0", len(repo.purgedBefore))
+	}
+}
+
+func TestRunFlushesFullBatch(t *testing.T) {
+	s, repo := newTestSearchService()
+	ctx, stop := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	for i := 0; i < searchLogBatchSize; i++ {
+		s.RecordSearch(context.Background(), "sepeda", 1, domain.SearchMatchFullText, testClient)
+	}
+
+	// Batch penuh dicatat tanpa menunggu searchLogFlushInterval
+	deadline := time.Now().Add(searchLogFlushInterval / 2)
+	for {
+		repo.mu.Lock()
+		batches, entries := repo.batches, len(repo.entries)
+		repo.mu.Unlock()
+		if batches == 1 && entries == searchLogBatchSize {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("batch penuh belum dicatat: %d batch, %d pencarian", batches, entries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stop()
+	<-done
+}
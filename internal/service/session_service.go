@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+)
+
+// SessionService adalah interface untuk layanan pengelolaan sesi login
+type SessionService interface {
+	List(ctx context.Context, userID uint, currentSessionID string) ([]domain.SessionResponse, error)
+	Revoke(ctx context.Context, userID uint, sessionID string) error
+	RevokeOthers(ctx context.Context, userID uint, currentSessionID string) (int, error)
+}
+
+// sessionService adalah implementasi dari SessionService
+type sessionService struct {
+	sessionRepo      repository.SessionRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	config           *config.Config
+}
+
+// NewSessionService membuat instance baru dari SessionService
+func NewSessionService(
+	sessionRepo repository.SessionRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	config *config.Config,
+) SessionService {
+	return &sessionService{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		config:           config,
+	}
+}
+
+// List mendapatkan sesi aktif milik pengguna.
+// Sesi yang tidak aktif lebih lama dari masa berlaku refresh token tidak ditampilkan.
+func (s *sessionService) List(ctx context.Context, userID uint, currentSessionID string) ([]domain.SessionResponse, error) {
+	activeSince := time.Now().Add(-s.config.JWT.RefreshExpiryTime)
+	sessions, err := s.sessionRepo.FindActiveByUserID(ctx, userID, activeSince)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]domain.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, session.ToResponse(currentSessionID))
+	}
+
+	return responses, nil
+}
+
+// Revoke mengakhiri satu sesi milik pengguna beserta refresh token-nya
+func (s *sessionService) Revoke(ctx context.Context, userID uint, sessionID string) error {
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil || session.PenggunaID != userID {
+		return errors.New("sesi tidak ditemukan")
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, session.ID); err != nil {
+		return err
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(ctx, session.ID)
+}
+
+// RevokeOthers mengakhiri semua sesi milik pengguna kecuali sesi yang sedang dipakai
+func (s *sessionService) RevokeOthers(ctx context.Context, userID uint, currentSessionID string) (int, error) {
+	ids, err := s.sessionRepo.RevokeByUserID(ctx, userID, currentSessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(ids), nil
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"math"
+	"time"
 
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
 	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
 )
 
+// ErrTransactionFrozen dikembalikan jika transaksi pending melibatkan pengguna yang sedang ditangguhkan
+var ErrTransactionFrozen = errors.New("transaksi dibekukan karena salah satu pihak sedang ditangguhkan")
+
 // TransactionService adalah interface untuk layanan transaksi
 type TransactionService interface {
 	Create(ctx context.Context, transaction *domain.Transaction, userID uint) (*domain.TransactionResponse, error)
@@ -24,16 +29,19 @@ type TransactionService interface {
 type transactionService struct {
 	transactionRepo repository.TransactionRepository
 	itemRepo        repository.ItemRepository
+	blockRepo       repository.BlockRepository
 }
 
 // NewTransactionService membuat instance baru dari TransactionService
 func NewTransactionService(
 	transactionRepo repository.TransactionRepository, 
 	itemRepo repository.ItemRepository,
+	blockRepo repository.BlockRepository,
 ) TransactionService {
 	return &transactionService{
 		transactionRepo: transactionRepo,
 		itemRepo:        itemRepo,
+		blockRepo:       blockRepo,
 	}
 }
 
@@ -50,8 +58,15 @@ func (s *transactionService) Create(ctx context.Context, transaction *domain.Tra
 		return nil, err
 	}
 
-	// Cek status barang
-	if item.Status != domain.StatusTersedia {
+	// Cek status barang. Barang milik akun yang sedang dihapus atau ditangguhkan juga tidak bisa dibeli.
+	// Barang yang disembunyikan karena laporan juga tidak bisa dibeli sampai ditinjau moderator.
+	if item.Status != domain.StatusTersedia || item.Penjual.IsDeleted() || item.Penjual.IsSuspended(time.Now()) || item.IsHidden() {
+		return nil, errors.New("barang tidak tersedia untuk dibeli")
+	}
+
+	// Barang milik penjual yang disembunyikan karena laporan tidak tampil di daftar barang,
+	// sehingga hanya pemilik dan moderator yang dapat melihat atau membelinya
+	if item.Penjual.IsHidden() && !canSeeHidden(ctx, item.PenjualID) {
 		return nil, errors.New("barang tidak tersedia untuk dibeli")
 	}
 
@@ -60,6 +75,15 @@ func (s *transactionService) Create(ctx context.Context, transaction *domain.Tra
 		return nil, errors.New("anda tidak dapat membeli barang anda sendiri")
 	}
 
+	// Pembelian antara pengguna yang saling memblokir ditolak seolah barang tidak tersedia
+	blocked, err := s.blockRepo.IsBlockedBetween(ctx, userID, item.PenjualID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, errors.New("barang tidak tersedia untuk dibeli")
+	}
+
 	// Cek apakah barang sudah ada transaksi pending
 	existingTransaction, err := s.transactionRepo.FindByBarangID(ctx, transaction.BarangID)
 	if err != nil {
@@ -100,8 +124,8 @@ func (s *transactionService) GetByID(ctx context.Context, id uint, userID uint)
 		return nil, err
 	}
 
-	// Cek apakah pengguna terlibat dalam transaksi
-	if transaction.PembeliID != userID && transaction.Barang.PenjualID != userID {
+	// Cek apakah pengguna terlibat dalam transaksi atau admin
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.TransactionRead, transaction) {
 		return nil, errors.New("anda tidak memiliki akses ke transaksi ini")
 	}
 
@@ -111,6 +135,12 @@ func (s *transactionService) GetByID(ctx context.Context, id uint, userID uint)
 
 // GetAll mendapatkan semua transaksi dengan paginasi
 func (s *transactionService) GetAll(ctx context.Context, page, limit int) ([]domain.TransactionResponse, int, int64, error) {
+	// Hanya role dengan izin melihat semua transaksi (admin)
+	principal, _ := policy.FromContext(ctx)
+	if !policy.Can(principal, policy.TransactionListAll, nil) {
+		return nil, 0, 0, policy.ErrForbidden
+	}
+
 	// Validasi input paginasi
 	if page < 1 {
 		page = 1
@@ -199,10 +229,15 @@ func (s *transactionService) UpdateStatus(ctx context.Context, id uint, status d
 	}
 
 	// Validasi pengguna
-	if transaction.PembeliID != userID && transaction.Barang.PenjualID != userID {
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.TransactionUpdateStatus, transaction) {
 		return errors.New("anda tidak memiliki akses ke transaksi ini")
 	}
 
+	// Transaksi dengan pihak yang sedang ditangguhkan tidak dapat diproses
+	if transaction.IsFrozen(time.Now()) {
+		return ErrTransactionFrozen
+	}
+
 	// Validasi perubahan status
 	if transaction.StatusTransaksi == domain.StatusSelesai || transaction.StatusTransaksi == domain.StatusDibatalkan {
 		return errors.New("tidak dapat mengubah status transaksi yang sudah selesai atau dibatalkan")
@@ -231,11 +266,15 @@ func (s *transactionService) Delete(ctx context.Context, id uint, userID uint) e
 		return err
 	}
 
-	// Validasi pengguna (hanya admin atau penjual yang dapat menghapus)
-	if transaction.Barang.PenjualID != userID {
+	// Validasi pengguna (hanya penjual yang dapat menghapus)
+	if !policy.Can(policy.PrincipalFor(ctx, userID), policy.TransactionDelete, transaction) {
 		return errors.New("anda tidak memiliki izin untuk menghapus transaksi ini")
 	}
 
+	if transaction.IsFrozen(time.Now()) {
+		return ErrTransactionFrozen
+	}
+
 	// Hapus transaksi
 	return s.transactionRepo.Delete(ctx, id)
 }
\ No newline at end of file
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
+	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+	"gorm.io/gorm"
+)
+
+// fakeItemRepository menyimpan barang di memori. Method yang tidak dipakai
+// diteruskan ke interface nil sehingga panic jika terpanggil.
+type fakeItemRepository struct {
+	repository.ItemRepository
+	items map[uint]*domain.Item
+}
+
+func (r *fakeItemRepository) FindByID(ctx context.Context, id uint) (*domain.Item, error) {
+	item, ok := r.items[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *item
+	return &copied, nil
+}
+
+func (r *fakeItemRepository) UpdateStatus(ctx context.Context, id uint, status domain.ItemStatus) error {
+	r.items[id].Status = status
+	return nil
+}
+
+// fakeTransactionRepository menyimpan transaksi di memori
+type fakeTransactionRepository struct {
+	repository.TransactionRepository
+	items        *fakeItemRepository
+	transactions []domain.Transaction
+}
+
+func (r *fakeTransactionRepository) Create(ctx context.Context, transaction *domain.Transaction) error {
+	transaction.ID = uint(len(r.transactions) + 1)
+	r.transactions = append(r.transactions, *transaction)
+	return nil
+}
+
+func (r *fakeTransactionRepository) FindByID(ctx context.Context, id uint) (*domain.Transaction, error) {
+	for _, transaction := range r.transactions {
+		if transaction.ID == id {
+			item, err := r.items.FindByID(ctx, transaction.BarangID)
+			if err != nil {
+				return nil, err
+			}
+			transaction.Barang = *item
+			return &transaction, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeTransactionRepository) FindByBarangID(ctx context.Context, barangID uint) (*domain.Transaction, error) {
+	for i := len(r.transactions) - 1; i >= 0; i-- {
+		if r.transactions[i].BarangID == barangID {
+			return &r.transactions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// fakeBlockRepository tidak memiliki blokir apa pun
+type fakeBlockRepository struct {
+	repository.BlockRepository
+}
+
+func (r *fakeBlockRepository) IsBlockedBetween(ctx context.Context, userA, userB uint) (bool, error) {
+	return false, nil
+}
+
+func TestTransactionCreateRejectsUnavailableItems(t *testing.T) {
+	const (
+		buyerID  uint = 1
+		sellerID uint = 2
+	)
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		item    domain.Item
+		wantErr bool
+	}{
+		{"tersedia", domain.Item{}, false},
+		{"sudah terjual", domain.Item{Status: domain.StatusTerjual}, true},
+		{"barang disembunyikan", domain.Item{HiddenAt: &past}, true},
+		{"penjual dihapus", domain.Item{Penjual: domain.User{DeletionScheduledAt: &past}}, true},
+		{"penjual ditangguhkan", domain.Item{Penjual: domain.User{SuspendedAt: &past}}, true},
+		{"penjual ditangguhkan sementara", domain.Item{Penjual: domain.User{SuspendedAt: &past, SuspendedUntil: &future}}, true},
+		{"penangguhan penjual sudah berakhir", domain.Item{Penjual: domain.User{SuspendedAt: &past, SuspendedUntil: &past}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := tt.item
+			item.ID = 10
+			item.PenjualID = sellerID
+			item.Penjual.ID = sellerID
+			if item.Status == "" {
+				item.Status = domain.StatusTersedia
+			}
+			items := &fakeItemRepository{items: map[uint]*domain.Item{item.ID: &item}}
+			transactions := &fakeTransactionRepository{items: items}
+			service := NewTransactionService(transactions, items, &fakeBlockRepository{})
+
+			_, err := service.Create(context.Background(), &domain.Transaction{PembeliID: buyerID, BarangID: item.ID}, buyerID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Create error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if err.Error() != "barang tidak tersedia untuk dibeli" {
+					t.Errorf("Create error = %q, want barang tidak tersedia untuk dibeli", err)
+				}
+				if len(transactions.transactions) != 0 {
+					t.Errorf("transaksi dibuat untuk barang yang tidak tersedia: %+v", transactions.transactions)
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionCreateRejectsHiddenSeller(t *testing.T) {
+	const (
+		buyerID  uint = 1
+		sellerID uint = 2
+	)
+	hiddenAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		principal *policy.Principal
+		wantErr   bool
+	}{
+		{"pembeli biasa", &policy.Principal{UserID: buyerID, Role: domain.RoleUser}, true},
+		{"tanpa principal", nil, true},
+		{"moderator", &policy.Principal{UserID: buyerID, Role: domain.RoleModerator, MFA: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := domain.Item{
+				ID:        10,
+				PenjualID: sellerID,
+				Status:    domain.StatusTersedia,
+				Penjual:   domain.User{ID: sellerID, HiddenAt: &hiddenAt},
+			}
+			items := &fakeItemRepository{items: map[uint]*domain.Item{item.ID: &item}}
+			transactions := &fakeTransactionRepository{items: items}
+			service := NewTransactionService(transactions, items, &fakeBlockRepository{})
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = policy.WithPrincipal(ctx, *tt.principal)
+			}
+			_, err := service.Create(ctx, &domain.Transaction{PembeliID: buyerID, BarangID: item.ID}, buyerID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Create error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,44 +2,114 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"io"
 	"math"
+	"mime/multipart"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
+	"github.com/mfuadfakhruzzaki/jubel/internal/errors"
+	"github.com/mfuadfakhruzzaki/jubel/internal/imaging"
+	"github.com/mfuadfakhruzzaki/jubel/internal/mailer"
+	"github.com/mfuadfakhruzzaki/jubel/internal/policy"
 	"github.com/mfuadfakhruzzaki/jubel/internal/repository"
+	"github.com/mfuadfakhruzzaki/jubel/internal/storage"
+	"github.com/mfuadfakhruzzaki/jubel/internal/utils"
 )
 
 // UserService adalah interface untuk layanan pengguna
 type UserService interface {
 	GetByID(ctx context.Context, id uint) (*domain.UserResponse, error)
+	GetPublicProfile(ctx context.Context, id uint) (*domain.PublicProfile, error)
 	GetAll(ctx context.Context, page, limit int, search string) ([]domain.UserResponse, int, int64, error)
 	Update(ctx context.Context, id uint, user *domain.User) (*domain.UserResponse, error)
-	Delete(ctx context.Context, id uint) error
-	HardDelete(ctx context.Context, id uint) error
+	// Delete menjadwalkan penghapusan akun setelah masa tenggang
+	Delete(ctx context.Context, id uint) (*domain.UserResponse, error)
+	// Restore membatalkan penghapusan akun menggunakan token dari email pemulihan
+	Restore(ctx context.Context, token string) (*domain.UserResponse, error)
+	// Anonymize langsung menghapus data pribadi pengguna
+	Anonymize(ctx context.Context, id uint) error
+	// RunDeletionWorker menganonimkan akun yang masa tenggangnya habis sampai ctx dibatalkan
+	RunDeletionWorker(ctx context.Context)
+	// UploadAvatar mengganti foto profil pengguna; file lama dihapus dari storage
+	UploadAvatar(ctx context.Context, userID uint, file *multipart.FileHeader) (*domain.UserResponse, error)
+	// DeleteAvatar menghapus foto profil pengguna
+	DeleteAvatar(ctx context.Context, userID uint) (*domain.UserResponse, error)
 }
 
+const (
+	// avatarSize adalah sisi foto profil persegi dalam piksel
+	avatarSize = 512
+	// avatarThumbSize adalah sisi thumbnail foto profil untuk daftar chat dan kartu barang
+	avatarThumbSize = 128
+)
+
 // userService adalah implementasi dari UserService
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	sessionRepo      repository.SessionRepository
+	mailer           mailer.Mailer
+	store            storage.Store
+	config           *config.Config
 }
 
 // NewUserService membuat instance baru dari UserService
-func NewUserService(userRepo repository.UserRepository) UserService {
+func NewUserService(userRepo repository.UserRepository, refreshTokenRepo repository.RefreshTokenRepository, sessionRepo repository.SessionRepository, mail mailer.Mailer, store storage.Store, cfg *config.Config) UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		mailer:           mail,
+		store:            store,
+		config:           cfg,
 	}
 }
 
-// GetByID mendapatkan pengguna berdasarkan ID
+// GetByID mendapatkan data lengkap pengguna berdasarkan ID, termasuk email, nomor HP dan alamat.
+// Principal pada context harus memiliki izin policy.UserReadPrivate (pemilik akun atau admin).
 func (s *userService) GetByID(ctx context.Context, id uint) (*domain.UserResponse, error) {
 	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	principal, _ := policy.FromContext(ctx)
+	if !policy.Can(principal, policy.UserReadPrivate, user) {
+		return nil, policy.ErrForbidden
+	}
+
 	userResponse := user.ToResponse()
 	return &userResponse, nil
 }
 
+// GetPublicProfile mendapatkan profil publik pengguna beserta statistik reputasinya.
+// Profil publik tidak memuat data kontak sehingga boleh dilihat siapa saja.
+func (s *userService) GetPublicProfile(ctx context.Context, id uint) (*domain.PublicProfile, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Profil yang disembunyikan karena laporan hanya terlihat oleh pemiliknya dan moderator
+	if user.IsHidden() && !canSeeHidden(ctx, user.ID) {
+		return nil, fmt.Errorf("pengguna dengan ID %d tidak ditemukan", id)
+	}
+
+	stats, err := s.userRepo.GetStats(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := domain.NewPublicProfile(user, *stats)
+	return &profile, nil
+}
+
 // GetAll mendapatkan semua pengguna dengan paginasi dan filter
 func (s *userService) GetAll(ctx context.Context, page, limit int, search string) ([]domain.UserResponse, int, int64, error) {
 	// Validasi input paginasi
@@ -68,7 +138,8 @@ func (s *userService) GetAll(ctx context.Context, page, limit int, search string
 	return userResponses, totalPages, total, nil
 }
 
-// Update memperbarui data pengguna
+// Update memperbarui data pengguna.
+// Principal pada context harus memiliki izin policy.UserUpdate terhadap pengguna tersebut.
 func (s *userService) Update(ctx context.Context, id uint, userData *domain.User) (*domain.UserResponse, error) {
 	// Dapatkan pengguna yang ada
 	existingUser, err := s.userRepo.FindByID(ctx, id)
@@ -76,6 +147,11 @@ func (s *userService) Update(ctx context.Context, id uint, userData *domain.User
 		return nil, err
 	}
 
+	principal, _ := policy.FromContext(ctx)
+	if !policy.Can(principal, policy.UserUpdate, existingUser) {
+		return nil, policy.ErrForbidden
+	}
+
 	// Update data pengguna
 	if userData.Nama != "" {
 		existingUser.Nama = userData.Nama
@@ -86,11 +162,15 @@ func (s *userService) Update(ctx context.Context, id uint, userData *domain.User
 	if userData.Alamat != "" {
 		existingUser.Alamat = userData.Alamat
 	}
+	passwordChanged := false
 	if userData.Password != "" {
 		existingUser.Password = userData.Password // Simpan password yang belum di-hash
 		if err := existingUser.HashPassword(); err != nil {
 			return nil, err
 		}
+		// Ganti password mengakhiri semua sesi yang sudah ada
+		existingUser.InvalidateTokens(time.Now())
+		passwordChanged = true
 	}
 
 	// Role tidak bisa diubah melalui endpoint ini
@@ -101,28 +181,274 @@ func (s *userService) Update(ctx context.Context, id uint, userData *domain.User
 		return nil, err
 	}
 
+	if passwordChanged {
+		if _, err := s.sessionRepo.RevokeByUserID(ctx, id, ""); err != nil {
+			return nil, err
+		}
+		if err := s.refreshTokenRepo.RevokeByUserID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
 	userResponse := existingUser.ToResponse()
 	return &userResponse, nil
 }
 
-// Delete menghapus pengguna (soft delete)
-func (s *userService) Delete(ctx context.Context, id uint) error {
+// Delete menjadwalkan penghapusan akun. Akun langsung tidak bisa dipakai, tetapi data pribadinya
+// baru dianonimkan setelah AccountDeletionGrace sehingga pengguna masih bisa memulihkannya.
+// Principal pada context harus memiliki izin policy.UserDelete terhadap pengguna tersebut.
+func (s *userService) Delete(ctx context.Context, id uint) (*domain.UserResponse, error) {
 	// Cek apakah pengguna ada
-	_, err := s.userRepo.FindByID(ctx, id)
+	existingUser, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	principal, _ := policy.FromContext(ctx)
+	if !policy.Can(principal, policy.UserDelete, existingUser) {
+		return nil, policy.ErrForbidden
+	}
+	if existingUser.IsDeleted() {
+		return nil, errors.ConflictError("akun sudah dijadwalkan untuk dihapus", nil)
+	}
+
+	now := time.Now()
+	scheduledAt := now.Add(s.config.Auth.AccountDeletionGrace).Truncate(time.Second)
+	existingUser.DeletionScheduledAt = &scheduledAt
+	existingUser.InvalidateTokens(now)
+	if err := s.userRepo.Update(ctx, existingUser); err != nil {
+		return nil, err
+	}
+
+	// Akhiri semua sesi dan cabut refresh token agar sesi tidak bisa diperpanjang
+	if _, err := s.sessionRepo.RevokeByUserID(ctx, id, ""); err != nil {
+		return nil, err
+	}
+	if err := s.refreshTokenRepo.RevokeByUserID(ctx, id); err != nil {
+		return nil, err
 	}
 
-	return s.userRepo.Delete(ctx, id)
+	// Kegagalan email tidak membatalkan penghapusan; admin masih bisa membantu pemulihan
+	if err := s.sendRestoreEmail(ctx, existingUser); err != nil {
+		log.Printf("Gagal mengirim email pemulihan akun ke pengguna %d: %v", existingUser.ID, err)
+	}
+
+	userResponse := existingUser.ToResponse()
+	return &userResponse, nil
 }
 
-// HardDelete menghapus pengguna secara permanen
-func (s *userService) HardDelete(ctx context.Context, id uint) error {
-	// Cek apakah pengguna ada
-	_, err := s.userRepo.FindByID(ctx, id)
+// Restore membatalkan penghapusan akun menggunakan token dari email pemulihan
+func (s *userService) Restore(ctx context.Context, token string) (*domain.UserResponse, error) {
+	invalid := errors.ValidationError("tautan pemulihan tidak valid atau masa tenggang sudah habis", nil)
+
+	// Format token: <user_id>.<deletion_scheduled_unix>.<signature>
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalid
+	}
+	userID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+	scheduledAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() >= scheduledAt {
+		return nil, invalid
+	}
+	if !utils.VerifySignedValues(s.config.JWT.Secret, parts[2], "restore-account", parts[0], parts[1]) {
+		return nil, invalid
+	}
+
+	user, err := s.userRepo.FindByID(ctx, uint(userID))
+	if err != nil {
+		return nil, invalid
+	}
+	// Token hanya berlaku untuk jadwal penghapusan yang sedang berjalan
+	if !user.IsPendingDeletion() || user.DeletionScheduledAt.Unix() != scheduledAt {
+		return nil, invalid
+	}
+
+	user.DeletionScheduledAt = nil
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	userResponse := user.ToResponse()
+	return &userResponse, nil
+}
+
+// Anonymize langsung menghapus data pribadi pengguna. Baris pengguna tetap disimpan
+// agar transaksi, chat dan ulasan pihak lain tidak ikut terhapus.
+func (s *userService) Anonymize(ctx context.Context, id uint) error {
+	// Foto profil termasuk data pribadi sehingga file-nya ikut dihapus dari storage
+	if user, err := s.userRepo.FindByID(ctx, id); err == nil && user.AvatarFileID != "" {
+		s.deleteAvatarFiles(ctx, user.AvatarFileID)
+	}
+	return s.userRepo.Anonymize(ctx, id, time.Now())
+}
+
+// RunDeletionWorker menganonimkan akun yang masa tenggangnya habis sampai ctx dibatalkan
+func (s *userService) RunDeletionWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Auth.AccountDeletionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		users, err := s.userRepo.FindDueForAnonymization(ctx, time.Now())
+		if err != nil {
+			log.Printf("Gagal mencari akun yang akan dianonimkan: %v", err)
+		}
+		for _, user := range users {
+			if err := s.Anonymize(ctx, user.ID); err != nil {
+				log.Printf("Gagal menganonimkan pengguna %d: %v", user.ID, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendRestoreEmail mengirim tautan untuk membatalkan penghapusan akun
+func (s *userService) sendRestoreEmail(ctx context.Context, user *domain.User) error {
+	userID := strconv.FormatUint(uint64(user.ID), 10)
+	scheduledAt := strconv.FormatInt(user.DeletionScheduledAt.Unix(), 10)
+	signature := utils.SignValues(s.config.JWT.Secret, "restore-account", userID, scheduledAt)
+	token := userID + "." + scheduledAt + "." + signature
+
+	restoreURL := fmt.Sprintf("%s/restore-account?token=%s", s.config.Server.FrontendURL, url.QueryEscape(token))
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Akun Jubel Anda akan dihapus",
+		Body: fmt.Sprintf(
+			"Halo %s,\n\n"+
+				"Kami menerima permintaan untuk menghapus akun Jubel Anda. Akun Anda sudah dinonaktifkan\n"+
+				"dan data pribadi Anda akan dihapus permanen pada %s.\n\n"+
+				"Riwayat transaksi dan chat dengan pengguna lain tetap disimpan tanpa nama, email\n"+
+				"dan nomor HP Anda.\n\n"+
+				"Jika Anda berubah pikiran, buka tautan berikut sebelum waktu tersebut untuk memulihkan akun:\n\n%s\n",
+			user.Nama, user.DeletionScheduledAt.Format("02 January 2006 15:04 MST"), restoreURL,
+		),
+	})
+}
+
+// UploadAvatar mengganti foto profil pengguna. Gambar dipotong menjadi persegi dan disimpan
+// bersama thumbnail-nya melalui storage yang sama dengan gambar barang.
+func (s *userService) UploadAvatar(ctx context.Context, userID uint, file *multipart.FileHeader) (*domain.UserResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NotFoundError("Pengguna tidak ditemukan", err)
+	}
+
+	principal, _ := policy.FromContext(ctx)
+	if !policy.Can(principal, policy.UserUpdate, user) {
+		return nil, policy.ErrForbidden
+	}
+
+	if file.Size > s.config.Upload.MaxSize {
+		return nil, errors.ValidationError(
+			fmt.Sprintf("Ukuran file terlalu besar (maksimal %d bytes)", s.config.Upload.MaxSize),
+			nil,
+		)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, errors.InternalError("Gagal membuka file", err)
+	}
+	defer src.Close()
+
+	fileBytes, err := io.ReadAll(src)
+	if err != nil {
+		return nil, errors.InternalError("Gagal membaca file", err)
+	}
+
+	// Tipe file ditentukan dari isinya, bukan dari header Content-Type yang dikirim klien
+	contentType := imaging.DetectContentType(fileBytes)
+	if !s.config.Upload.IsAllowedType(contentType) {
+		return nil, errors.ValidationError(
+			fmt.Sprintf("Tipe file tidak didukung, gunakan salah satu dari: %s", strings.Join(s.config.Upload.AllowedTypes, ", ")),
+			nil,
+		).WithMetadata("contentType", contentType)
+	}
+
+	img, format, err := imaging.Decode(fileBytes)
 	if err != nil {
-		return err
+		return nil, errors.ValidationError("File bukan gambar yang valid", err)
 	}
+	avatar, ext, contentType, err := imaging.Encode(imaging.SquareThumbnail(img, avatarSize), format)
+	if err != nil {
+		return nil, errors.InternalError("Gagal memproses foto profil", err)
+	}
+	thumb, _, _, err := imaging.Encode(imaging.SquareThumbnail(img, avatarThumbSize), format)
+	if err != nil {
+		return nil, errors.InternalError("Gagal memproses foto profil", err)
+	}
+
+	// Upload foto profil dan thumbnail-nya
+	key := domain.NewAvatarKey(userID, time.Now(), ext)
+	thumbKey := domain.AvatarThumbKey(key)
+	if err := s.store.Put(ctx, key, avatar, contentType); err != nil {
+		return nil, errors.InternalError("Gagal mengupload foto profil", err)
+	}
+	if err := s.store.Put(ctx, thumbKey, thumb, contentType); err != nil {
+		s.deleteAvatarFiles(ctx, key)
+		return nil, errors.InternalError("Gagal mengupload foto profil", err)
+	}
+
+	oldFileID := user.AvatarFileID
+	user.AvatarFileID = key
+	user.AvatarURL = s.store.URL(key)
+	user.AvatarThumbURL = s.store.URL(thumbKey)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.deleteAvatarFiles(ctx, key)
+		return nil, errors.InternalError("Gagal menyimpan foto profil", err)
+	}
+
+	// File lama dihapus setelah foto baru tersimpan agar pengguna tidak kehilangan foto profil jika upload gagal
+	if oldFileID != "" {
+		s.deleteAvatarFiles(ctx, oldFileID)
+	}
+
+	userResponse := user.ToResponse()
+	return &userResponse, nil
+}
 
-	return s.userRepo.HardDelete(ctx, id)
-}
\ No newline at end of file
+// DeleteAvatar menghapus foto profil pengguna
+func (s *userService) DeleteAvatar(ctx context.Context, userID uint) (*domain.UserResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.NotFoundError("Pengguna tidak ditemukan", err)
+	}
+
+	principal, _ := policy.FromContext(ctx)
+	if !policy.Can(principal, policy.UserUpdate, user) {
+		return nil, policy.ErrForbidden
+	}
+	if user.AvatarFileID == "" {
+		return nil, errors.NotFoundError("Pengguna belum memiliki foto profil", nil)
+	}
+
+	oldFileID := user.AvatarFileID
+	user.AvatarFileID = ""
+	user.AvatarURL = ""
+	user.AvatarThumbURL = ""
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, errors.InternalError("Gagal menghapus foto profil", err)
+	}
+	s.deleteAvatarFiles(ctx, oldFileID)
+
+	userResponse := user.ToResponse()
+	return &userResponse, nil
+}
+
+// deleteAvatarFiles menghapus foto profil dan thumbnail-nya dari storage.
+// Kegagalan hanya dicatat karena tidak memengaruhi data pengguna.
+func (s *userService) deleteAvatarFiles(ctx context.Context, key string) {
+	for _, k := range []string{key, domain.AvatarThumbKey(key)} {
+		if err := s.store.Delete(ctx, k); err != nil {
+			log.Printf("Gagal menghapus file foto profil %s: %v", k, err)
+		}
+	}
+}
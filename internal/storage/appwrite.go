@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+)
+
+// appwriteMaxFileIDLength adalah panjang maksimal file ID Appwrite
+const appwriteMaxFileIDLength = 36
+
+// AppwriteStore menyimpan file di bucket Appwrite Storage. Key dipakai langsung sebagai
+// file ID Appwrite, sehingga hanya boleh berisi huruf, angka, titik, minus dan garis bawah
+// dengan panjang maksimal 36 karakter.
+type AppwriteStore struct {
+	cfg    config.AppwriteConfig
+	client *http.Client
+}
+
+// NewAppwriteStore membuat instance baru AppwriteStore
+func NewAppwriteStore(cfg config.AppwriteConfig) (*AppwriteStore, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("APPWRITE_ENDPOINT tidak boleh kosong")
+	}
+	if cfg.ProjectID == "" {
+		return nil, fmt.Errorf("APPWRITE_PROJECT_ID tidak boleh kosong")
+	}
+	if cfg.BucketID == "" {
+		return nil, fmt.Errorf("APPWRITE_BUCKET_ID tidak boleh kosong")
+	}
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("APPWRITE_API_KEY tidak boleh kosong")
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+
+	return &AppwriteStore{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}, nil
+}
+
+// Put mengupload file ke bucket Appwrite. File lama dengan ID yang sama dihapus terlebih dahulu
+// karena Appwrite tidak mengizinkan file ID ganda.
+func (s *AppwriteStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if len(key) > appwriteMaxFileIDLength {
+		return fmt.Errorf("key file %q terlalu panjang untuk Appwrite (maksimal %d karakter)", key, appwriteMaxFileIDLength)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if err := writer.WriteField("fileId", key); err != nil {
+		return fmt.Errorf("gagal membuat field fileId: %w", err)
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, key))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("gagal membuat form file: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return fmt.Errorf("gagal menulis file ke form: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("gagal menutup writer: %w", err)
+	}
+
+	resp, err := s.do(ctx, http.MethodPost, s.filesURL(), body, writer.FormDataContentType())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return responseError("mengupload file ke Appwrite", resp)
+	}
+	return nil
+}
+
+// Get mengunduh file dari bucket Appwrite
+func (s *AppwriteStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, s.fileURL(key)+"/download", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError("mengunduh file dari Appwrite", resp)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca file dari Appwrite: %w", err)
+	}
+	return data, nil
+}
+
+// Delete menghapus file dari bucket Appwrite
+func (s *AppwriteStore) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, http.MethodDelete, s.fileURL(key), nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return responseError("menghapus file dari Appwrite", resp)
+	}
+	return nil
+}
+
+// URL mengembalikan URL view file yang dapat diakses publik
+func (s *AppwriteStore) URL(key string) string {
+	return s.fileURL(key) + "/view?project=" + url.QueryEscape(s.cfg.ProjectID)
+}
+
+// filesURL mengembalikan URL koleksi file di bucket
+func (s *AppwriteStore) filesURL() string {
+	return fmt.Sprintf("%s/storage/buckets/%s/files", s.cfg.Endpoint, url.PathEscape(s.cfg.BucketID))
+}
+
+// fileURL mengembalikan URL satu file di bucket
+func (s *AppwriteStore) fileURL(key string) string {
+	return s.filesURL() + "/" + url.PathEscape(key)
+}
+
+// do mengirim request yang diautentikasi dengan API key ke Appwrite
+func (s *AppwriteStore) do(ctx context.Context, method, target string, body io.Reader, contentType string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("X-Appwrite-Project", s.cfg.ProjectID)
+	req.Header.Set("X-Appwrite-Key", s.cfg.APIKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengirim request ke Appwrite: %w", err)
+	}
+	return resp, nil
+}
+
+// responseError membuat error dari response yang tidak berhasil. Body dibatasi
+// agar pesan error dari server tidak membanjiri log.
+func responseError(action string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("gagal %s: HTTP %d - %s", action, resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+)
+
+func TestAppwriteStorePutRejectsLongKey(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	store, err := NewAppwriteStore(config.AppwriteConfig{
+		Endpoint:  server.URL,
+		ProjectID: "proyek",
+		APIKey:    "rahasia",
+		BucketID:  "bucket",
+	})
+	if err != nil {
+		t.Fatalf("NewAppwriteStore: %v", err)
+	}
+
+	key := strings.Repeat("a", appwriteMaxFileIDLength-4) + ".jpg_"
+	if err := store.Put(context.Background(), key, []byte("x"), "image/jpeg"); err == nil {
+		t.Fatalf("Put dengan key %d karakter seharusnya ditolak", len(key))
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("Put mengirim %d request ke Appwrite, want 0", n)
+	}
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore menyimpan file di direktori lokal yang disajikan oleh router.Static("/uploads").
+// Cocok untuk pengembangan lokal karena tidak membutuhkan bucket remote.
+type LocalStore struct {
+	dir       string
+	publicURL string
+}
+
+// NewLocalStore membuat instance baru LocalStore dan memastikan direktori penyimpanan ada.
+// publicURL adalah prefix URL tempat direktori disajikan, misalnya "/uploads" atau "https://api.example.com/uploads".
+func NewLocalStore(dir, publicURL string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("gagal membuat direktori upload: %w", err)
+	}
+	return &LocalStore{
+		dir:       dir,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}, nil
+}
+
+// Put menyimpan file ke direktori lokal
+func (s *LocalStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	// Tulis ke file sementara lalu rename agar file tidak pernah terbaca setengah jadi
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("gagal membuat file sementara: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("gagal menulis file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("gagal menulis file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("gagal mengatur izin file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, key)); err != nil {
+		return fmt.Errorf("gagal menyimpan file: %w", err)
+	}
+	return nil
+}
+
+// Get membaca file dari direktori lokal
+func (s *LocalStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca file: %w", err)
+	}
+	return data, nil
+}
+
+// Delete menghapus file dari direktori lokal
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(s.dir, key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("gagal menghapus file: %w", err)
+	}
+	return nil
+}
+
+// URL mengembalikan URL publik file
+func (s *LocalStore) URL(key string) string {
+	return s.publicURL + "/" + key
+}
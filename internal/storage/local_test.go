@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "uploads")
+
+	store, err := NewLocalStore(dir, "https://api.example.com/uploads/")
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+
+	const key = "item_1_lu40q4qo_0.jpg"
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get sebelum Put: err = %v, want ErrNotFound", err)
+	}
+
+	data := []byte("isi gambar")
+	if err := store.Put(ctx, key, data, "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("Get = %q, want %q", got, data)
+	}
+
+	// Put menimpa file lama dengan key yang sama
+	if err := store.Put(ctx, key, []byte("baru"), "image/jpeg"); err != nil {
+		t.Fatalf("Put ulang: %v", err)
+	}
+	if got, _ := store.Get(ctx, key); string(got) != "baru" {
+		t.Errorf("Get setelah Put ulang = %q, want %q", got, "baru")
+	}
+
+	info, err := os.Stat(filepath.Join(dir, key))
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0644 {
+		t.Errorf("izin file = %o, want 644", perm)
+	}
+
+	if got, want := store.URL(key), "https://api.example.com/uploads/"+key; got != want {
+		t.Errorf("URL = %q, want %q", got, want)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get setelah Delete: err = %v, want ErrNotFound", err)
+	}
+	// File yang sudah tidak ada dianggap berhasil dihapus
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete file yang tidak ada: %v", err)
+	}
+
+	// Tidak ada file sementara yang tertinggal
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("direktori upload berisi %d file, want kosong", len(entries))
+	}
+}
+
+func TestLocalStoreRejectsInvalidKeys(t *testing.T) {
+	ctx := context.Background()
+	parent := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(parent, "uploads"), "/uploads")
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+
+	key := "../rahasia.txt"
+	if err := store.Put(ctx, key, []byte("x"), "text/plain"); err == nil {
+		t.Error("Put dengan key di luar direktori seharusnya ditolak")
+	}
+	if _, err := os.Stat(filepath.Join(parent, "rahasia.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file tertulis di luar direktori upload: %v", err)
+	}
+	if _, err := store.Get(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get dengan key di luar direktori: err = %v, want error key tidak valid", err)
+	}
+	if err := store.Delete(ctx, key); err == nil {
+		t.Error("Delete dengan key di luar direktori seharusnya ditolak")
+	}
+}
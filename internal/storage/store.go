@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mfuadfakhruzzaki/jubel/internal/config"
+)
+
+// ErrNotFound dikembalikan Get jika file dengan key tersebut tidak ada
+var ErrNotFound = errors.New("file tidak ditemukan")
+
+// Store adalah interface penyimpanan file yang dapat diakses publik, misalnya gambar barang dan foto profil.
+// Key adalah nama file beserta ekstensinya, misalnya "item_12_1712345678901.jpg".
+type Store interface {
+	// Put menyimpan file dengan key tertentu, menimpa file lama dengan key yang sama
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+
+	// Get membaca isi file. Mengembalikan ErrNotFound jika file tidak ada.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Delete menghapus file. File yang sudah tidak ada dianggap berhasil dihapus.
+	Delete(ctx context.Context, key string) error
+
+	// URL mengembalikan URL publik file
+	URL(key string) string
+}
+
+// Driver storage yang didukung
+const (
+	DriverLocal    = "local"
+	DriverAppwrite = "appwrite"
+	DriverS3       = "s3"
+)
+
+// New membuat Store sesuai STORAGE_DRIVER
+func New(cfg *config.Config) (Store, error) {
+	switch cfg.Storage.Driver {
+	case DriverLocal:
+		return NewLocalStore(cfg.Upload.Dir, cfg.Storage.PublicURL)
+	case DriverAppwrite:
+		return NewAppwriteStore(cfg.Appwrite)
+	case DriverS3:
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER tidak dikenal: %s (gunakan local, appwrite atau s3)", cfg.Storage.Driver)
+	}
+}
+
+// validateKey memastikan key hanya berisi nama file tanpa direktori,
+// sehingga tidak dapat dipakai untuk membaca atau menulis di luar lokasi penyimpanan
+func validateKey(key string) error {
+	if key == "" || key != path.Base(key) || strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+		return fmt.Errorf("key file tidak valid: %q", key)
+	}
+	return nil
+}
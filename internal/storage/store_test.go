@@ -0,0 +1,31 @@
+package storage
+
+import "testing"
+
+func TestValidateKey(t *testing.T) {
+	tests := []struct {
+		key   string
+		valid bool
+	}{
+		{"item_1_lu40q4qo_0.jpg", true},
+		{"avatar_1_lun2elv9_t.png", true},
+		{"", false},
+		{".", false},
+		{"..", false},
+		{"a/b", false},
+		{`a\b`, false},
+		{"../a", false},
+		{`..\a`, false},
+		{"/a", false},
+		{"a/", false},
+	}
+	for _, tt := range tests {
+		err := validateKey(tt.key)
+		if tt.valid && err != nil {
+			t.Errorf("validateKey(%q) = %v, want nil", tt.key, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("validateKey(%q) = nil, want error", tt.key)
+		}
+	}
+}
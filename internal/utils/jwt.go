@@ -6,56 +6,66 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/mfuadfakhruzzaki/jubel/internal/domain"
 )
 
+// TokenPurposeMFAPending menandai token sementara yang hanya bisa ditukar
+// dengan token akses setelah kode verifikasi dua langkah benar
+const TokenPurposeMFAPending = "mfa_pending"
+
 // JWTClaims adalah struktur custom untuk claims JWT
 type JWTClaims struct {
 	UserID uint        `json:"user_id"`
 	Email  string      `json:"email"`
 	Role   domain.Role `json:"role"`
+	// Purpose kosong untuk token akses biasa
+	Purpose string `json:"purpose,omitempty"`
+	// MFA bernilai true jika sesi diautentikasi dengan verifikasi dua langkah
+	MFA bool `json:"mfa,omitempty"`
+	// SessionID adalah ID sesi login tempat token diterbitkan
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // GenerateToken menghasilkan token JWT baru
-func GenerateToken(user *domain.User, secret string, expiry time.Duration) (string, error) {
+func GenerateToken(user *domain.User, keys *KeyRing, expiry time.Duration, sessionID string, mfa bool) (string, error) {
+	return signToken(user, keys, expiry, "", sessionID, mfa)
+}
+
+// GenerateMFAPendingToken menghasilkan token sementara untuk langkah kedua login
+func GenerateMFAPendingToken(user *domain.User, keys *KeyRing, expiry time.Duration) (string, error) {
+	return signToken(user, keys, expiry, TokenPurposeMFAPending, "", false)
+}
+
+// signToken membuat dan menandatangani token JWT
+func signToken(user *domain.User, keys *KeyRing, expiry time.Duration, purpose, sessionID string, mfa bool) (string, error) {
 	// Buat claims dengan data pengguna
 	claims := JWTClaims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   user.Role,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		Purpose:   purpose,
+		MFA:       mfa,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "jubel-app",
 			Subject:   fmt.Sprintf("%d", user.ID),
+			ID:        uuid.New().String(),
 		},
 	}
 
-	// Buat token dengan metode signing HS256
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// Sign token dengan secret key
-	tokenString, err := token.SignedString([]byte(secret))
-	if err != nil {
-		return "", err
-	}
-
-	return tokenString, nil
+	// Sign token dengan kunci aktif dari key ring
+	return keys.Sign(claims)
 }
 
 // ValidateToken memvalidasi dan mengurai token JWT
-func ValidateToken(tokenString, secret string) (*JWTClaims, error) {
-	// Parse token
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validasi metode signing
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("metode signing tidak valid: %v", token.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
-
+func ValidateToken(tokenString string, keys *KeyRing) (*JWTClaims, error) {
+	// Parse token, kunci verifikasi dipilih berdasarkan header kid
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, keys.Keyfunc)
 	if err != nil {
 		return nil, err
 	}
@@ -66,4 +76,4 @@ func ValidateToken(tokenString, secret string) (*JWTClaims, error) {
 	}
 
 	return nil, errors.New("token tidak valid")
-}
\ No newline at end of file
+}
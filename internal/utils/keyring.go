@@ -0,0 +1,281 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey adalah satu kunci penandatanganan JWT di dalam KeyRing
+type SigningKey struct {
+	// ID dipakai sebagai header kid pada token
+	ID     string
+	Method jwt.SigningMethod
+	// Private kosong untuk kunci yang sudah dipensiunkan dan hanya dipakai untuk verifikasi
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// KeyRing menyimpan kunci-kunci untuk menandatangani dan memverifikasi JWT.
+// Token baru ditandatangani dengan kunci aktif, sedangkan token lama tetap bisa
+// diverifikasi selama kuncinya masih ada di KeyRing sehingga rotasi kunci tidak
+// membuat semua pengguna logout.
+type KeyRing struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+	// hmacSecret dipakai untuk HS256 jika tidak ada kunci asimetris,
+	// atau untuk memverifikasi token HS256 lama selama masa migrasi
+	hmacSecret  []byte
+	acceptHS256 bool
+}
+
+// NewHMACKeyRing membuat KeyRing yang hanya menggunakan HS256 dengan secret bersama
+func NewHMACKeyRing(secret string) *KeyRing {
+	return &KeyRing{
+		keys:        make(map[string]*SigningKey),
+		hmacSecret:  []byte(secret),
+		acceptHS256: true,
+	}
+}
+
+// LoadKeyRing memuat kunci dari file PEM di dalam direktori.
+// File <kid>.pem berisi kunci privat (RSA atau Ed25519, PKCS#1/PKCS#8) dan dipakai
+// untuk menandatangani serta memverifikasi. File <kid>.pub.pem berisi kunci publik
+// dari kunci yang sudah dipensiunkan dan hanya dipakai untuk verifikasi.
+// Jika activeKID kosong, kunci privat dengan nama terakhir (urut abjad) menjadi kunci aktif.
+// Jika acceptHS256 true, token HS256 lama yang ditandatangani dengan hmacSecret tetap diterima.
+func LoadKeyRing(dir, activeKID, hmacSecret string, acceptHS256 bool) (*KeyRing, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca direktori kunci JWT: %w", err)
+	}
+
+	ring := &KeyRing{
+		keys:        make(map[string]*SigningKey),
+		hmacSecret:  []byte(hmacSecret),
+		acceptHS256: acceptHS256,
+	}
+
+	var privateIDs []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".pem") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca kunci %s: %w", name, err)
+		}
+
+		var key *SigningKey
+		if strings.HasSuffix(name, ".pub.pem") {
+			key, err = parsePublicKey(strings.TrimSuffix(name, ".pub.pem"), data)
+		} else {
+			key, err = parsePrivateKey(strings.TrimSuffix(name, ".pem"), data)
+			if err == nil {
+				privateIDs = append(privateIDs, key.ID)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gagal memuat kunci %s: %w", name, err)
+		}
+
+		// Kunci privat lebih diutamakan jika ada file publik dengan kid yang sama
+		if existing, ok := ring.keys[key.ID]; ok && existing.Private != nil {
+			continue
+		}
+		ring.keys[key.ID] = key
+	}
+
+	if len(privateIDs) == 0 {
+		return nil, fmt.Errorf("tidak ada kunci privat di direktori %s", dir)
+	}
+
+	if activeKID == "" {
+		sort.Strings(privateIDs)
+		activeKID = privateIDs[len(privateIDs)-1]
+	}
+
+	active, ok := ring.keys[activeKID]
+	if !ok || active.Private == nil {
+		return nil, fmt.Errorf("kunci aktif %q tidak ditemukan atau tidak memiliki kunci privat", activeKID)
+	}
+	ring.active = active
+
+	return ring, nil
+}
+
+// ActiveKeyID mengembalikan kid kunci aktif, kosong jika KeyRing memakai HS256
+func (k *KeyRing) ActiveKeyID() string {
+	if k.active == nil {
+		return ""
+	}
+	return k.active.ID
+}
+
+// Sign menandatangani claims dengan kunci aktif dan menambahkan header kid
+func (k *KeyRing) Sign(claims jwt.Claims) (string, error) {
+	if k.active == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(k.hmacSecret)
+	}
+
+	token := jwt.NewWithClaims(k.active.Method, claims)
+	token.Header["kid"] = k.active.ID
+	return token.SignedString(k.active.Private)
+}
+
+// Keyfunc memilih kunci verifikasi berdasarkan header kid dan alg token
+func (k *KeyRing) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	if kid == "" {
+		// Token tanpa kid adalah token HS256
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || !k.acceptHS256 || len(k.hmacSecret) == 0 {
+			return nil, fmt.Errorf("metode signing tidak valid: %v", token.Header["alg"])
+		}
+		return k.hmacSecret, nil
+	}
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("kunci %q tidak dikenal", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("metode signing tidak valid: %v", token.Header["alg"])
+	}
+	return key.Public, nil
+}
+
+// JWK adalah representasi JSON Web Key untuk kunci publik
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// Parameter RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Parameter Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS adalah kumpulan JSON Web Key
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS mengembalikan semua kunci publik (aktif dan yang sudah dipensiunkan).
+// Kunci HS256 tidak pernah dipublikasikan.
+func (k *KeyRing) JWKS() JWKS {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	set := JWKS{Keys: make([]JWK, 0, len(ids))}
+	for _, id := range ids {
+		key := k.keys[id]
+		jwk := JWK{
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+		}
+
+		switch pub := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+// parsePrivateKey mengurai kunci privat RSA atau Ed25519 dari PEM
+func parsePrivateKey(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("format PEM tidak valid")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("tipe PEM %q tidak didukung", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 2048 {
+			return nil, errors.New("kunci RSA minimal 2048 bit")
+		}
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodRS256, Private: key, Public: &key.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodEdDSA, Private: key, Public: key.Public()}, nil
+	default:
+		return nil, errors.New("hanya kunci RSA dan Ed25519 yang didukung")
+	}
+}
+
+// parsePublicKey mengurai kunci publik RSA atau Ed25519 dari PEM
+func parsePublicKey(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("format PEM tidak valid")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("tipe PEM %q tidak didukung", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PublicKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodRS256, Public: key}, nil
+	case ed25519.PublicKey:
+		return &SigningKey{ID: kid, Method: jwt.SigningMethodEdDSA, Public: key}, nil
+	default:
+		return nil, errors.New("hanya kunci RSA dan Ed25519 yang didukung")
+	}
+}
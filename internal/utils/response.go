@@ -27,6 +27,21 @@ type Meta struct {
 	TotalPages int   `json:"total_pages"`
 }
 
+// CursorResponse adalah struktur response dengan paginasi berbasis cursor
+type CursorResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+	Meta    CursorMeta  `json:"meta"`
+}
+
+// CursorMeta untuk informasi paginasi berbasis cursor
+type CursorMeta struct {
+	// NextCursor dikirim sebagai parameter cursor untuk halaman berikutnya, kosong jika sudah habis
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+}
+
 // ErrorResponse mengirimkan response error
 func ErrorResponse(c *gin.Context, statusCode int, message string, data interface{}) {
 	c.JSON(statusCode, StandardResponse{
@@ -53,4 +68,14 @@ func SuccessPaginatedResponse(c *gin.Context, statusCode int, message string, da
 		Data:    data,
 		Meta:    meta,
 	})
-}
\ No newline at end of file
+}
+
+// SuccessCursorResponse mengirimkan response sukses dengan paginasi berbasis cursor
+func SuccessCursorResponse(c *gin.Context, statusCode int, message string, data interface{}, meta CursorMeta) {
+	c.JSON(statusCode, CursorResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+		Meta:    meta,
+	})
+}
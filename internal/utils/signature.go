@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// SignValues menghasilkan tanda tangan HMAC-SHA256 dari beberapa nilai.
+// Digunakan untuk tautan yang harus bisa diverifikasi tanpa disimpan di database.
+func SignValues(secret string, values ...string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join(values, "\x00")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignedValues memeriksa apakah tanda tangan cocok dengan nilai-nilai yang diberikan
+func VerifySignedValues(secret, signature string, values ...string) bool {
+	expected := SignValues(secret, values...)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,16 @@
+-- Tabel refresh token (disimpan dalam bentuk hash, dirotasi setiap kali dipakai)
+CREATE TABLE refresh_token (
+    id SERIAL PRIMARY KEY,
+    pengguna_id INT NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    family_id VARCHAR(36) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk pencarian refresh token
+CREATE INDEX idx_refresh_token_pengguna ON refresh_token(pengguna_id);
+CREATE INDEX idx_refresh_token_family ON refresh_token(family_id);
@@ -0,0 +1,14 @@
+-- Token yang diterbitkan sebelum waktu ini dianggap tidak berlaku (misalnya setelah ganti password)
+ALTER TABLE pengguna ADD COLUMN token_valid_after TIMESTAMP;
+
+-- Tabel token akses yang dicabut sebelum kedaluwarsa (logout)
+CREATE TABLE token_dicabut (
+    jti VARCHAR(36) PRIMARY KEY,
+    pengguna_id INT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk pembersihan token yang sudah kedaluwarsa
+CREATE INDEX idx_token_dicabut_expires ON token_dicabut(expires_at);
@@ -0,0 +1,13 @@
+-- Tabel token reset password (sekali pakai, disimpan dalam bentuk hash)
+CREATE TABLE reset_password (
+    id SERIAL PRIMARY KEY,
+    pengguna_id INT NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk pencarian token reset password
+CREATE INDEX idx_reset_password_pengguna ON reset_password(pengguna_id);
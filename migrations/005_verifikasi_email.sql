@@ -0,0 +1,5 @@
+-- Kolom waktu verifikasi email pengguna
+ALTER TABLE pengguna ADD COLUMN email_verified_at TIMESTAMP;
+
+-- Akun yang sudah ada sebelum verifikasi email diberlakukan dianggap terverifikasi
+UPDATE pengguna SET email_verified_at = created_at WHERE email_verified_at IS NULL;
@@ -0,0 +1,20 @@
+-- Kolom verifikasi dua langkah (TOTP) pada pengguna
+ALTER TABLE pengguna ADD COLUMN totp_secret VARCHAR(64);
+ALTER TABLE pengguna ADD COLUMN totp_enabled_at TIMESTAMP;
+ALTER TABLE pengguna ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+-- Tandai refresh token yang berasal dari login dengan verifikasi dua langkah
+ALTER TABLE refresh_token ADD COLUMN mfa BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Tabel kode pemulihan 2FA (sekali pakai, disimpan dalam bentuk hash)
+CREATE TABLE kode_pemulihan (
+    id SERIAL PRIMARY KEY,
+    pengguna_id INT NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk pencarian kode pemulihan
+CREATE INDEX idx_kode_pemulihan_pengguna ON kode_pemulihan(pengguna_id);
@@ -0,0 +1,10 @@
+-- Tabel percobaan login gagal per email dan per IP
+CREATE TABLE percobaan_login (
+    attempt_key VARCHAR(255) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP
+);
+
+-- Buat index untuk membersihkan catatan lama
+CREATE INDEX idx_percobaan_login_last_failure ON percobaan_login(last_failure_at);
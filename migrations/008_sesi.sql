@@ -0,0 +1,14 @@
+-- Tabel sesi login per perangkat (id sama dengan family_id refresh token)
+CREATE TABLE sesi (
+    id VARCHAR(36) PRIMARY KEY,
+    pengguna_id INT NOT NULL,
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk pencarian sesi pengguna
+CREATE INDEX idx_sesi_pengguna ON sesi(pengguna_id);
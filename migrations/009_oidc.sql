@@ -0,0 +1,28 @@
+-- Tabel state login OpenID Connect yang sedang berlangsung (state, nonce, PKCE code_verifier)
+CREATE TABLE oidc_state (
+    state_hash VARCHAR(64) PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Buat index untuk membersihkan state yang kedaluwarsa
+CREATE INDEX idx_oidc_state_expires ON oidc_state(expires_at);
+
+-- Tabel identitas penyedia OpenID Connect yang terhubung ke pengguna
+CREATE TABLE identitas_oidc (
+    id SERIAL PRIMARY KEY,
+    pengguna_id INT NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(100),
+    last_login_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, subject),
+    FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk pencarian identitas pengguna
+CREATE INDEX idx_identitas_oidc_pengguna ON identitas_oidc(pengguna_id);
@@ -0,0 +1,17 @@
+-- Tabel personal access token untuk integrasi dan bot
+CREATE TABLE token_akses (
+    id SERIAL PRIMARY KEY,
+    pengguna_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    token_prefix VARCHAR(16) NOT NULL,
+    scopes TEXT NOT NULL,
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk pencarian token pengguna
+CREATE INDEX idx_token_akses_pengguna ON token_akses(pengguna_id);
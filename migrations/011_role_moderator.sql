@@ -0,0 +1,2 @@
+-- Tambah role moderator (tidak bisa dijalankan di dalam transaksi)
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'moderator';
@@ -0,0 +1,23 @@
+-- Kolom penangguhan akun oleh admin
+ALTER TABLE pengguna ADD COLUMN suspended_at TIMESTAMP;
+ALTER TABLE pengguna ADD COLUMN suspended_until TIMESTAMP;
+ALTER TABLE pengguna ADD COLUMN suspend_reason TEXT;
+
+-- Buat index untuk mencari pengguna yang ditangguhkan
+CREATE INDEX idx_pengguna_suspended ON pengguna(suspended_at) WHERE suspended_at IS NOT NULL;
+
+-- Tabel catatan tindakan admin terhadap pengguna
+CREATE TABLE log_admin (
+    id SERIAL PRIMARY KEY,
+    admin_id INT NOT NULL,
+    target_id INT NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    detail TEXT,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (admin_id) REFERENCES pengguna(id),
+    FOREIGN KEY (target_id) REFERENCES pengguna(id)
+);
+
+-- Buat index untuk pencarian catatan per pengguna
+CREATE INDEX idx_log_admin_target ON log_admin(target_id);
@@ -0,0 +1,27 @@
+-- Tabel ulasan untuk transaksi yang sudah selesai
+CREATE TABLE ulasan (
+    id SERIAL PRIMARY KEY,
+    transaksi_id INT NOT NULL,
+    penulis_id INT NOT NULL,
+    target_id INT NOT NULL,
+    peran VARCHAR(10) NOT NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    komentar TEXT,
+    balasan TEXT,
+    balasan_at TIMESTAMP,
+    hidden_at TIMESTAMP,
+    hidden_reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (transaksi_id, penulis_id),
+    FOREIGN KEY (transaksi_id) REFERENCES transaksi(id) ON DELETE CASCADE,
+    FOREIGN KEY (penulis_id) REFERENCES pengguna(id) ON DELETE CASCADE,
+    FOREIGN KEY (target_id) REFERENCES pengguna(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk pencarian ulasan pengguna
+CREATE INDEX idx_ulasan_target ON ulasan(target_id);
+
+-- Agregat rating pengguna dari ulasan yang tidak disembunyikan
+ALTER TABLE pengguna ADD COLUMN rating_rata_rata NUMERIC(3,2);
+ALTER TABLE pengguna ADD COLUMN jumlah_ulasan INT NOT NULL DEFAULT 0;
@@ -0,0 +1,17 @@
+-- Tabel permintaan ekspor data pribadi pengguna
+CREATE TABLE ekspor_data (
+    id SERIAL PRIMARY KEY,
+    pengguna_id INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    file_path VARCHAR(255),
+    error TEXT,
+    completed_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk mencari ekspor terbaru pengguna dan antrean worker
+CREATE INDEX idx_ekspor_data_pengguna ON ekspor_data(pengguna_id, created_at);
+CREATE INDEX idx_ekspor_data_status ON ekspor_data(status) WHERE status IN ('pending', 'processing', 'ready');
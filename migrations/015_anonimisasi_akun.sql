@@ -0,0 +1,31 @@
+-- Kolom penghapusan akun dengan masa tenggang dan penganoniman
+ALTER TABLE pengguna ADD COLUMN deletion_scheduled_at TIMESTAMP;
+ALTER TABLE pengguna ADD COLUMN anonymized_at TIMESTAMP;
+
+-- Email dan nomor HP dikosongkan (NULL) saat akun dianonimkan
+ALTER TABLE pengguna ALTER COLUMN email DROP NOT NULL;
+ALTER TABLE pengguna ALTER COLUMN no_hp DROP NOT NULL;
+
+-- Buat index untuk worker penganoniman
+CREATE INDEX idx_pengguna_deletion ON pengguna(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL AND anonymized_at IS NULL;
+
+-- Pengguna yang dihapus dengan soft delete lama langsung dijadwalkan untuk dianonimkan
+UPDATE pengguna SET deletion_scheduled_at = deleted_at, deleted_at = NULL WHERE deleted_at IS NOT NULL;
+
+-- Menghapus pengguna atau barang tidak boleh ikut menghapus riwayat pihak lain
+ALTER TABLE barang DROP CONSTRAINT IF EXISTS barang_penjual_id_fkey;
+ALTER TABLE barang ADD CONSTRAINT barang_penjual_id_fkey FOREIGN KEY (penjual_id) REFERENCES pengguna(id);
+ALTER TABLE transaksi DROP CONSTRAINT IF EXISTS transaksi_pembeli_id_fkey;
+ALTER TABLE transaksi ADD CONSTRAINT transaksi_pembeli_id_fkey FOREIGN KEY (pembeli_id) REFERENCES pengguna(id);
+ALTER TABLE transaksi DROP CONSTRAINT IF EXISTS transaksi_barang_id_fkey;
+ALTER TABLE transaksi ADD CONSTRAINT transaksi_barang_id_fkey FOREIGN KEY (barang_id) REFERENCES barang(id);
+ALTER TABLE chat DROP CONSTRAINT IF EXISTS chat_pengirim_id_fkey;
+ALTER TABLE chat ADD CONSTRAINT chat_pengirim_id_fkey FOREIGN KEY (pengirim_id) REFERENCES pengguna(id);
+ALTER TABLE chat DROP CONSTRAINT IF EXISTS chat_penerima_id_fkey;
+ALTER TABLE chat ADD CONSTRAINT chat_penerima_id_fkey FOREIGN KEY (penerima_id) REFERENCES pengguna(id);
+ALTER TABLE chat DROP CONSTRAINT IF EXISTS chat_barang_id_fkey;
+ALTER TABLE chat ADD CONSTRAINT chat_barang_id_fkey FOREIGN KEY (barang_id) REFERENCES barang(id);
+ALTER TABLE ulasan DROP CONSTRAINT IF EXISTS ulasan_penulis_id_fkey;
+ALTER TABLE ulasan ADD CONSTRAINT ulasan_penulis_id_fkey FOREIGN KEY (penulis_id) REFERENCES pengguna(id);
+ALTER TABLE ulasan DROP CONSTRAINT IF EXISTS ulasan_target_id_fkey;
+ALTER TABLE ulasan ADD CONSTRAINT ulasan_target_id_fkey FOREIGN KEY (target_id) REFERENCES pengguna(id);
@@ -0,0 +1,4 @@
+-- Kolom foto profil pengguna. Thumbnail disimpan dengan ID file avatar_file_id + "_thumb"
+ALTER TABLE pengguna ADD COLUMN avatar_file_id VARCHAR(64);
+ALTER TABLE pengguna ADD COLUMN avatar_url TEXT;
+ALTER TABLE pengguna ADD COLUMN avatar_thumb_url TEXT;
@@ -0,0 +1,13 @@
+-- Tabel pemblokiran antar pengguna
+CREATE TABLE blokir (
+    id SERIAL PRIMARY KEY,
+    pemblokir_id INT NOT NULL,
+    diblokir_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (pemblokir_id, diblokir_id),
+    FOREIGN KEY (pemblokir_id) REFERENCES pengguna(id) ON DELETE CASCADE,
+    FOREIGN KEY (diblokir_id) REFERENCES pengguna(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk memeriksa blokir dari arah sebaliknya
+CREATE INDEX idx_blokir_diblokir ON blokir(diblokir_id);
@@ -0,0 +1,36 @@
+-- Tabel laporan pengguna terhadap barang, pesan chat atau pengguna lain
+CREATE TABLE laporan (
+    id SERIAL PRIMARY KEY,
+    pelapor_id INT NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_id INT NOT NULL,
+    pemilik_target_id INT NOT NULL,
+    alasan VARCHAR(30) NOT NULL,
+    deskripsi TEXT,
+    cuplikan TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    moderator_id INT,
+    catatan_penyelesaian TEXT,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pelapor_id) REFERENCES pengguna(id),
+    FOREIGN KEY (pemilik_target_id) REFERENCES pengguna(id),
+    FOREIGN KEY (moderator_id) REFERENCES pengguna(id)
+);
+
+-- Buat index untuk antrean moderasi dan penghitungan laporan terbuka per target
+CREATE INDEX idx_laporan_antrean ON laporan(status, created_at);
+CREATE INDEX idx_laporan_target ON laporan(target_type, target_id) WHERE status IN ('open', 'reviewing');
+
+-- Satu pelapor hanya boleh memiliki satu laporan terbuka untuk target yang sama
+CREATE UNIQUE INDEX idx_laporan_pelapor_terbuka ON laporan(pelapor_id, target_type, target_id) WHERE status IN ('open', 'reviewing');
+
+-- Kolom penyembunyian konten yang dilaporkan
+ALTER TABLE barang ADD COLUMN hidden_at TIMESTAMP;
+ALTER TABLE chat ADD COLUMN hidden_at TIMESTAMP;
+ALTER TABLE pengguna ADD COLUMN hidden_at TIMESTAMP;
+
+-- Hubungkan catatan tindakan admin dengan laporan yang ditindaklanjuti
+ALTER TABLE log_admin ADD COLUMN laporan_id INT REFERENCES laporan(id);
+CREATE INDEX idx_log_admin_laporan ON log_admin(laporan_id) WHERE laporan_id IS NOT NULL;
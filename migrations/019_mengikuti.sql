@@ -0,0 +1,34 @@
+-- Tabel pengguna yang mengikuti penjual
+CREATE TABLE mengikuti (
+    id SERIAL PRIMARY KEY,
+    pengikut_id INT NOT NULL,
+    diikuti_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (pengikut_id, diikuti_id),
+    FOREIGN KEY (pengikut_id) REFERENCES pengguna(id) ON DELETE CASCADE,
+    FOREIGN KEY (diikuti_id) REFERENCES pengguna(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk menghitung dan mencari pengikut
+CREATE INDEX idx_mengikuti_diikuti ON mengikuti(diikuti_id);
+
+-- Buat index untuk feed barang terbaru dari penjual yang diikuti
+CREATE INDEX idx_barang_penjual_terbaru ON barang(penjual_id, created_at DESC, id DESC);
+
+-- Tabel notifikasi dalam aplikasi
+CREATE TABLE notifikasi (
+    id SERIAL PRIMARY KEY,
+    pengguna_id INT NOT NULL,
+    jenis VARCHAR(30) NOT NULL,
+    judul VARCHAR(200) NOT NULL,
+    pesan TEXT,
+    barang_id INT,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pengguna_id) REFERENCES pengguna(id) ON DELETE CASCADE,
+    FOREIGN KEY (barang_id) REFERENCES barang(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk daftar notifikasi dan jumlah notifikasi yang belum dibaca
+CREATE INDEX idx_notifikasi_pengguna ON notifikasi(pengguna_id, created_at DESC);
+CREATE INDEX idx_notifikasi_belum_dibaca ON notifikasi(pengguna_id) WHERE read_at IS NULL;
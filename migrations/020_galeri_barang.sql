@@ -0,0 +1,23 @@
+-- Tabel galeri gambar barang
+CREATE TABLE item_images (
+    id SERIAL PRIMARY KEY,
+    barang_id INT NOT NULL,
+    file_key VARCHAR(255) NOT NULL DEFAULT '',
+    url VARCHAR(255) NOT NULL,
+    keterangan VARCHAR(255) NOT NULL DEFAULT '',
+    urutan INT NOT NULL DEFAULT 0,
+    sampul BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (barang_id) REFERENCES barang(id) ON DELETE CASCADE
+);
+
+-- Buat index untuk menampilkan galeri sesuai urutan
+CREATE INDEX idx_item_images_barang ON item_images(barang_id, urutan);
+
+-- Setiap barang hanya boleh memiliki satu gambar sampul
+CREATE UNIQUE INDEX idx_item_images_sampul ON item_images(barang_id) WHERE sampul;
+
+-- Gambar lama menjadi sampul galeri; file_key kosong karena key file-nya tidak disimpan
+INSERT INTO item_images (barang_id, url, urutan, sampul)
+SELECT id, gambar, 0, TRUE FROM barang
+WHERE gambar IS NOT NULL AND gambar <> '';
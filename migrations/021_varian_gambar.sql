@@ -0,0 +1,4 @@
+-- URL varian gambar barang (thumbnail untuk daftar barang, medium untuk halaman detail).
+-- Gambar lama tidak memiliki varian sehingga kolom ini kosong dan respons memakai URL ukuran penuh.
+ALTER TABLE item_images ADD COLUMN thumb_url VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE item_images ADD COLUMN medium_url VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,23 @@
+-- Ekstensi trigram untuk pencarian yang toleran salah ketik
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Vektor pencarian barang: nama (bobot A), deskripsi (B) dan kategori (C).
+-- Kategori dipetakan dengan CASE karena cast enum ke text tidak immutable
+-- sehingga tidak dapat dipakai di generated column.
+ALTER TABLE barang ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(nama_barang, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(deskripsi, '')), 'B') ||
+    setweight(to_tsvector('simple', CASE kategori
+        WHEN 'Buku' THEN 'Buku'
+        WHEN 'Elektronik' THEN 'Elektronik'
+        WHEN 'Perabotan' THEN 'Perabotan'
+        WHEN 'Kos-kosan' THEN 'Kos-kosan'
+        ELSE 'Lainnya'
+    END), 'C')
+) STORED;
+
+-- Buat index untuk full-text search
+CREATE INDEX idx_barang_search_vector ON barang USING GIN (search_vector);
+
+-- Buat index trigram untuk mencari nama barang yang mirip
+CREATE INDEX idx_barang_nama_trgm ON barang USING GIN (nama_barang gin_trgm_ops);
@@ -0,0 +1,20 @@
+-- Log pencarian barang dari GET /items, tanpa identitas pengguna
+CREATE TABLE log_pencarian (
+    id SERIAL PRIMARY KEY,
+    kueri VARCHAR(100) NOT NULL,
+    jumlah_hasil BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Buat index untuk log pencarian
+CREATE INDEX idx_log_pencarian_created_at ON log_pencarian(created_at);
+
+-- Agregasi jumlah pencarian per kueri untuk saran kueri populer
+CREATE TABLE kueri_populer (
+    kueri VARCHAR(100) PRIMARY KEY,
+    jumlah BIGINT NOT NULL DEFAULT 0,
+    terakhir_dicari TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- text_pattern_ops membuat pencocokan awalan kueri LIKE 'awalan%' memakai index
+CREATE INDEX idx_kueri_populer_prefix ON kueri_populer(kueri text_pattern_ops);
@@ -0,0 +1,9 @@
+-- Hash pencari (HMAC dari ID pengguna atau alamat IP) agar kueri populer dihitung
+-- sekali per pencari dalam jendela deduplikasi
+ALTER TABLE log_pencarian ADD COLUMN pencari_hash VARCHAR(64);
+
+-- Pencarian yang menambah jumlah pencarian di kueri populer
+ALTER TABLE log_pencarian ADD COLUMN dihitung BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Deduplikasi hanya mencari pencarian yang sudah dihitung ke kueri populer
+CREATE INDEX idx_log_pencarian_pencari ON log_pencarian(pencari_hash, kueri, created_at) WHERE dihitung;